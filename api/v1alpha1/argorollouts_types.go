@@ -17,8 +17,10 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // RolloutManagerSpec defines the desired state of Argo Rollouts
@@ -27,15 +29,25 @@ type RolloutManagerSpec struct {
 	// Env lets you specify environment for Rollouts pods
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// EnvFrom lists the Secrets and ConfigMaps, in the namespace of the RolloutManager, whose keys are added to the environment of the Rollouts controller container
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
 	// Extra Command arguments that would append to the Rollouts
-	// ExtraCommandArgs will not be added, if one of these commands is already part of the Rollouts command
-	// with same or different value.
+	// ExtraCommandArgs take precedence over the flags of the Rollouts command set by the operator, except for the flags owned by the operator,
+	// such as --namespaced, and those set by another field of the RolloutManager, which may not be set here.
 	ExtraCommandArgs []string `json:"extraCommandArgs,omitempty"`
 
 	// Image defines Argo Rollouts controller image (optional)
 	Image string `json:"image,omitempty"`
 
-	// NodePlacement defines NodeSelectors and Taints for Rollouts workloads
+	// ImagePullPolicy of the Argo Rollouts controller container. Defaults to Always.
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// ImagePullSecrets lists the Secrets, in the namespace of the RolloutManager, used to pull the images of the Argo Rollouts controller pods
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// NodePlacement defines the scheduling of Rollouts workloads: node selector, tolerations, affinity, topology spread constraints, priority class and runtime class
 	NodePlacement *RolloutsNodePlacementSpec `json:"nodePlacement,omitempty"`
 
 	// Version defines Argo Rollouts controller tag (optional)
@@ -50,21 +62,332 @@ type RolloutManagerSpec struct {
 	// Resources requests/limits for Argo Rollout controller
 	ControllerResources *corev1.ResourceRequirements `json:"controllerResources,omitempty"`
 
+	// ExtraContainers are added to the Argo Rollouts controller pods, after the argo-rollouts container, for example to run a sidecar
+	ExtraContainers []corev1.Container `json:"extraContainers,omitempty"`
+
+	// ExtraVolumes are added to the Argo Rollouts controller pods, for use by ExtraVolumeMounts and ExtraContainers
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+
+	// ExtraVolumeMounts are added to the argo-rollouts container of the Argo Rollouts controller pods
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+
 	// SkipNotificationSecretDeployment lets you specify if the argo notification secret should be deployed
 	SkipNotificationSecretDeployment bool `json:"skipNotificationSecretDeployment,omitempty"`
 
-	// Plugins specify the traffic and metric plugins in Argo Rollout
+	// NotificationSecretSources lists Secrets whose keys are merged into the argo-rollouts-notification-secret Secret, and kept in sync.
+	// Keys of the notification Secret which are not part of a source are left untouched.
+	NotificationSecretSources []NotificationSecretSource `json:"notificationSecretSources,omitempty"`
+
+	// SkipServiceMonitorDeployment lets you specify if the ServiceMonitor of the Argo Rollouts controller metrics should be deployed, when the Prometheus operator is installed.
+	// The metrics Service is deployed in either case.
+	SkipServiceMonitorDeployment bool `json:"skipServiceMonitorDeployment,omitempty"`
+
+	// Plugins specify the traffic, metric and step plugins in Argo Rollout
 	Plugins Plugins `json:"plugins,omitempty"`
+
+	// Replicas is the number of Argo Rollouts controller pods to run. Defaults to 1.
+	// When more than one replica is requested, leader election is enabled on the controller.
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// ReadinessDeadlineSeconds is the number of seconds the Argo Rollouts controller may take to become available, once the RolloutManager is reconciled,
+	// after which the RolloutManager is put in the Failure phase. When unset, or set to 0, there is no deadline.
+	// +kubebuilder:validation:Minimum=0
+	ReadinessDeadlineSeconds *int32 `json:"readinessDeadlineSeconds,omitempty"`
+
+	// LeaderElection lets you configure leader election of the Argo Rollouts controller
+	LeaderElection *RolloutsLeaderElectionSpec `json:"leaderElection,omitempty"`
+
+	// Strategy lets you configure how the Argo Rollouts controller pods are replaced when their Deployment is updated
+	Strategy *RolloutsDeploymentStrategySpec `json:"strategy,omitempty"`
+
+	// Logging lets you configure the logs of the Argo Rollouts controller
+	Logging *RolloutsLoggingSpec `json:"logging,omitempty"`
+
+	// Tuning lets you configure the worker threads of the Argo Rollouts controller, and the rate limits of its Kubernetes client
+	Tuning *RolloutsTuningSpec `json:"tuning,omitempty"`
+
+	// Probes lets you configure the liveness and readiness probes of the Argo Rollouts controller container, and add a startup probe to it
+	Probes *RolloutsProbesSpec `json:"probes,omitempty"`
+
+	// PodDisruptionBudget lets you specify a PodDisruptionBudget for the Argo Rollouts controller pods. No PodDisruptionBudget is created when this field is not set.
+	PodDisruptionBudget *RolloutsPodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
+
+	// Dashboard lets you deploy the Argo Rollouts dashboard alongside the Argo Rollouts controller
+	Dashboard *RolloutsDashboardSpec `json:"dashboard,omitempty"`
+
+	// Notifications lets you configure the notification engine of Argo Rollouts. When set, the operator manages the argo-rollouts-notification-configmap ConfigMap, and reverts any change made to it outside of the RolloutManager.
+	Notifications *RolloutsNotificationConfigurationSpec `json:"notifications,omitempty"`
+
+	// CRDManagement lets the operator install and upgrade the Argo Rollouts CRDs (Rollout, AnalysisRun, AnalysisTemplate, ClusterAnalysisTemplate and Experiment)
+	CRDManagement *RolloutsCRDManagementSpec `json:"crdManagement,omitempty"`
+
+	// Overrides are patches applied, in order, to the resources managed by the operator, after the operator has built their desired state.
+	// They allow setting fields of these resources which are not exposed by the RolloutManager.
+	Overrides []ResourceOverride `json:"overrides,omitempty"`
+}
+
+// RolloutsNotificationConfigurationSpec is used to configure the services, templates, triggers and subscriptions of the Argo Rollouts notification engine.
+// For more information, see the upstream Argo Rollouts notifications documentation.
+type RolloutsNotificationConfigurationSpec struct {
+	// Services holds the configuration of the notification services, keyed by service name (for example 'slack').
+	// The configuration is in YAML, and may reference keys of the argo-rollouts-notification-secret Secret as '$key'.
+	Services map[string]string `json:"services,omitempty"`
+	// Templates holds the notification templates
+	Templates []NotificationTemplate `json:"templates,omitempty"`
+	// Triggers holds the notification triggers
+	Triggers []NotificationTrigger `json:"triggers,omitempty"`
+	// DefaultTriggers is the list of triggers that are used by subscriptions which do not specify any trigger
+	DefaultTriggers []string `json:"defaultTriggers,omitempty"`
+	// DefaultSubscriptions holds the subscriptions that apply to all Rollouts
+	DefaultSubscriptions []NotificationSubscription `json:"defaultSubscriptions,omitempty"`
+	// TemplateSets references ConfigMaps, in the namespace of the RolloutManager, that hold reusable templates and triggers
+	// under 'template.<name>' and 'trigger.<name>' keys. Templates and triggers that are defined in this spec take precedence.
+	TemplateSets []NotificationTemplateSetReference `json:"templateSets,omitempty"`
+}
+
+// NotificationTemplate defines the content of a notification
+type NotificationTemplate struct {
+	// Name of the template, it is referenced by the 'send' field of triggers
+	Name string `json:"name"`
+	// Message is the notification message
+	Message string `json:"message,omitempty"`
+	// Services holds the service specific fields of the template, in YAML, keyed by service name (for example 'slack' or 'email')
+	Services map[string]string `json:"services,omitempty"`
+}
+
+// NotificationTrigger defines when a notification is sent
+type NotificationTrigger struct {
+	// Name of the trigger, it is referenced by subscriptions
+	Name string `json:"name"`
+	// Conditions holds the conditions of the trigger, and the templates to send when they are met
+	Conditions []NotificationTriggerCondition `json:"conditions"`
+}
+
+// NotificationTriggerCondition defines a condition of a notification trigger
+type NotificationTriggerCondition struct {
+	// When is the expression that is evaluated against the Rollout
+	When string `json:"when"`
+	// Send is the list of templates to send when the condition is met
+	Send []string `json:"send"`
+	// OncePer is the field of the Rollout that is used to send the notification only once per value
+	OncePer string `json:"oncePer,omitempty"`
+	// Description of the condition
+	Description string `json:"description,omitempty"`
+}
+
+// NotificationSubscription subscribes recipients to notification triggers
+type NotificationSubscription struct {
+	// Recipients of the notifications, in the '<service>:<recipient>' format (for example 'slack:my-channel')
+	Recipients []string `json:"recipients"`
+	// Triggers that notify the recipients. The default triggers are used when this is empty.
+	Triggers []string `json:"triggers,omitempty"`
+	// Selector is a label selector that restricts the subscription to matching Rollouts
+	Selector string `json:"selector,omitempty"`
+}
+
+// NotificationSecretSource references a Secret whose keys are merged into the argo-rollouts-notification-secret Secret
+type NotificationSecretSource struct {
+	// Name of the Secret, in the namespace of the RolloutManager
+	Name string `json:"name"`
+	// Keys lists the keys of the Secret to merge into the notification Secret. All the keys of the Secret are merged, under the same name, when this is empty.
+	Keys []NotificationSecretKeyMapping `json:"keys,omitempty"`
+}
+
+// NotificationSecretKeyMapping maps a key of a source Secret to a key of the argo-rollouts-notification-secret Secret
+type NotificationSecretKeyMapping struct {
+	// Key of the source Secret
+	Key string `json:"key"`
+	// TargetKey is the key in the notification Secret, which services reference as '$key'. Defaults to Key.
+	TargetKey string `json:"targetKey,omitempty"`
+}
+
+// NotificationTemplateSetReference references a ConfigMap that holds reusable notification templates and triggers
+type NotificationTemplateSetReference struct {
+	// Name of the ConfigMap
+	Name string `json:"name"`
+}
+
+// RolloutsCRDManagementSpec is used to configure the management of the Argo Rollouts CRDs by the operator
+type RolloutsCRDManagementSpec struct {
+	// Enabled lets you specify if the operator should install and upgrade the Argo Rollouts CRDs.
+	// CRDs are never deleted by the operator, and CRDs installed by OLM are left untouched.
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// ResourceOverride is a patch of one of the resources managed by the operator
+type ResourceOverride struct {
+	// Kind of the resource to patch
+	// +kubebuilder:validation:Enum=Deployment;Service;ServiceAccount;Role;ClusterRole;ServiceMonitor
+	Kind string `json:"kind"`
+	// Name of the resource to patch: argo-rollouts-metrics for the metrics Service, argo-rollouts for the other kinds
+	Name string `json:"name"`
+	// Type of the patch: StrategicMerge, or JSON for an RFC 6902 JSON patch. Defaults to StrategicMerge.
+	// +kubebuilder:validation:Enum=StrategicMerge;JSON
+	Type ResourceOverridePatchType `json:"type,omitempty"`
+	// Patch to apply to the resource, in YAML or JSON
+	Patch string `json:"patch"`
+}
+
+// ResourceOverridePatchType defines how the patch of a ResourceOverride is applied.
+type ResourceOverridePatchType string
+
+const (
+	// ResourceOverridePatchTypeStrategicMerge applies the patch as a Kubernetes strategic merge patch.
+	ResourceOverridePatchTypeStrategicMerge ResourceOverridePatchType = "StrategicMerge"
+	// ResourceOverridePatchTypeJSON applies the patch as an RFC 6902 JSON patch.
+	ResourceOverridePatchTypeJSON ResourceOverridePatchType = "JSON"
+)
+
+// RolloutsDashboardSpec is used to configure the Argo Rollouts dashboard
+type RolloutsDashboardSpec struct {
+	// Enabled lets you specify if the Argo Rollouts dashboard should be deployed
+	Enabled bool `json:"enabled,omitempty"`
+	// Image defines Argo Rollouts dashboard image (optional)
+	Image string `json:"image,omitempty"`
+	// Version defines Argo Rollouts dashboard tag (optional). Defaults to the version of the Argo Rollouts controller.
+	Version string `json:"version,omitempty"`
+	// Resources requests/limits for the Argo Rollouts dashboard
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+}
+
+// RolloutsLeaderElectionSpec is used to configure leader election of the Argo Rollouts controller
+type RolloutsLeaderElectionSpec struct {
+	// Enabled lets you explicitly enable or disable leader election. Leader election cannot be disabled when more than one replica is requested.
+	Enabled *bool `json:"enabled,omitempty"`
+	// LeaseDuration is the duration that non-leader candidates will wait to force acquire leadership
+	LeaseDuration *metav1.Duration `json:"leaseDuration,omitempty"`
+	// RenewDeadline is the duration that the acting leader will retry refreshing leadership before giving up
+	RenewDeadline *metav1.Duration `json:"renewDeadline,omitempty"`
+	// RetryPeriod is the duration that leader election clients should wait between tries of actions
+	RetryPeriod *metav1.Duration `json:"retryPeriod,omitempty"`
+}
+
+// RolloutsDeploymentStrategySpec is used to configure the strategy of the Deployment of the Argo Rollouts controller
+type RolloutsDeploymentStrategySpec struct {
+	// Type of the strategy: RollingUpdate, or Recreate to stop the running pods before starting new ones. Defaults to RollingUpdate.
+	// +kubebuilder:validation:Enum=RollingUpdate;Recreate
+	Type appsv1.DeploymentStrategyType `json:"type,omitempty"`
+	// MaxSurge is the number or percentage of pods that can be started above the number of replicas during a rolling update. Defaults to 25%.
+	// It may only be set when Type is RollingUpdate.
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// MaxUnavailable is the number or percentage of pods that can be unavailable during a rolling update. Defaults to 25%.
+	// It may only be set when Type is RollingUpdate.
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// RolloutsLoggingSpec is used to configure the logs of the Argo Rollouts controller
+type RolloutsLoggingSpec struct {
+	// Level of the logs of the Argo Rollouts controller: debug, info, warn or error. Defaults to info.
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+	Level string `json:"level,omitempty"`
+	// Format of the logs of the Argo Rollouts controller: text or json. Defaults to text.
+	// +kubebuilder:validation:Enum=text;json
+	Format string `json:"format,omitempty"`
+	// KlogLevel is the verbosity of the logs of the Kubernetes client libraries used by the Argo Rollouts controller. Defaults to 0.
+	// +kubebuilder:validation:Minimum=0
+	KlogLevel *int32 `json:"klogLevel,omitempty"`
+}
+
+// RolloutsTuningSpec is used to configure the worker threads of the Argo Rollouts controller, and the rate limits of its Kubernetes client.
+// Fields which are not set keep the defaults of the Argo Rollouts controller.
+type RolloutsTuningSpec struct {
+	// RolloutThreads is the number of worker threads which reconcile Rollouts
+	// +kubebuilder:validation:Minimum=1
+	RolloutThreads *int32 `json:"rolloutThreads,omitempty"`
+	// AnalysisThreads is the number of worker threads which reconcile AnalysisRuns
+	// +kubebuilder:validation:Minimum=1
+	AnalysisThreads *int32 `json:"analysisThreads,omitempty"`
+	// QPS is the maximum number of queries per second sent by the Kubernetes client of the Argo Rollouts controller
+	// +kubebuilder:validation:Minimum=1
+	QPS *int32 `json:"qps,omitempty"`
+	// Burst is the maximum burst of queries sent by the Kubernetes client of the Argo Rollouts controller. It must not be lower than QPS.
+	// +kubebuilder:validation:Minimum=1
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+// RolloutsProbesSpec is used to configure the probes of the Argo Rollouts controller container.
+// The probes keep checking the /healthz (liveness and startup) and /metrics (readiness) endpoints of the Argo Rollouts controller.
+type RolloutsProbesSpec struct {
+	// Liveness configures the liveness probe of the Argo Rollouts controller container
+	Liveness *RolloutsProbeSpec `json:"liveness,omitempty"`
+	// Readiness configures the readiness probe of the Argo Rollouts controller container
+	Readiness *RolloutsProbeSpec `json:"readiness,omitempty"`
+	// Startup adds a startup probe to the Argo Rollouts controller container, which holds off the liveness and readiness probes until
+	// the controller has started. By default, the controller is given 300 seconds to start. No startup probe is added when this is not set.
+	Startup *RolloutsProbeSpec `json:"startup,omitempty"`
+}
+
+// RolloutsProbeSpec overrides the timings and thresholds of a probe. Fields which are not set keep the defaults of the operator.
+type RolloutsProbeSpec struct {
+	// InitialDelaySeconds is the number of seconds after the container has started before the probe is initiated
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+	// PeriodSeconds is how often, in seconds, the probe is performed
+	// +kubebuilder:validation:Minimum=1
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+	// TimeoutSeconds is the number of seconds after which the probe times out
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// SuccessThreshold is the minimum consecutive successes for the probe to be considered successful after having failed.
+	// It must be 1 for the liveness and startup probes.
+	// +kubebuilder:validation:Minimum=1
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+	// FailureThreshold is the minimum consecutive failures for the probe to be considered failed after having succeeded
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// RolloutsPodDisruptionBudgetSpec is used to configure the PodDisruptionBudget of the Argo Rollouts controller.
+// Only one of MinAvailable and MaxUnavailable may be set. If neither is set, MaxUnavailable defaults to 1.
+type RolloutsPodDisruptionBudgetSpec struct {
+	// MinAvailable is the number or percentage of Argo Rollouts controller pods that must remain available during a disruption
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the number or percentage of Argo Rollouts controller pods that may be unavailable during a disruption
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
 }
 
-// Plugin is used to integrate traffic management and metric plugins into the Argo Rollouts controller. For more information on these plugins, see the upstream Argo Rollouts documentation.
+// Plugin is used to integrate traffic management, metric and step plugins into the Argo Rollouts controller. For more information on these plugins, see the upstream Argo Rollouts documentation.
 type Plugin struct {
 	// Name of the plugin, it must match the name required by the plugin so it can find its configuration
 	Name string `json:"name"`
-	// Location supports http(s):// urls and file://, though file:// requires the plugin be available on the filesystem
-	Location string `json:"location"`
+	// Location supports http(s):// urls and file://, though file:// requires the plugin be available on the filesystem.
+	// Location is required, unless Source is set.
+	Location string `json:"location,omitempty"`
 	// SHA256 is an optional sha256 checksum of the plugin executable
 	SHA256 string `json:"sha256,omitempty"`
+	// Args is an optional list of arguments passed to the plugin executable when it is started by the Argo Rollouts controller
+	Args []string `json:"args,omitempty"`
+	// Source loads the plugin executable from an OCI image, a PersistentVolumeClaim, a ConfigMap or a Secret, rather than from Location.
+	Source *PluginSource `json:"source,omitempty"`
+}
+
+// PluginSource defines where the plugin executable is loaded from. Exactly one of its fields must be set.
+type PluginSource struct {
+	// Image copies the plugin executable out of an OCI image into the Argo Rollouts controller pod, using an init container.
+	Image *PluginImageSource `json:"image,omitempty"`
+	// PersistentVolumeClaim mounts the plugin executable from an existing PersistentVolumeClaim.
+	PersistentVolumeClaim *PluginVolumeSource `json:"persistentVolumeClaim,omitempty"`
+	// ConfigMap mounts the plugin executable from a key of an existing ConfigMap.
+	ConfigMap *PluginVolumeSource `json:"configMap,omitempty"`
+	// Secret mounts the plugin executable from a key of an existing Secret.
+	Secret *PluginVolumeSource `json:"secret,omitempty"`
+}
+
+// PluginImageSource is an OCI image containing a plugin executable.
+type PluginImageSource struct {
+	// Reference of the image, such as quay.io/my-org/my-plugin:v1.0.0. The image must provide 'cp', and run as a non-root user.
+	Reference string `json:"reference"`
+	// Path of the plugin executable within the image.
+	Path string `json:"path"`
+}
+
+// PluginVolumeSource is a PersistentVolumeClaim, ConfigMap or Secret containing a plugin executable, in the namespace of the RolloutManager.
+type PluginVolumeSource struct {
+	// Name of the PersistentVolumeClaim, ConfigMap or Secret.
+	Name string `json:"name"`
+	// Path of the plugin executable: a file path within the PersistentVolumeClaim, or a key of the ConfigMap or Secret.
+	Path string `json:"path"`
 }
 
 type Plugins struct {
@@ -72,14 +395,52 @@ type Plugins struct {
 	TrafficManagement []Plugin `json:"trafficManagement,omitempty"`
 	// Metric holds a list of metric plugins used to gather and report metrics during rollouts.
 	Metric []Plugin `json:"metric,omitempty"`
+	// Step holds a list of step plugins, which can be used as custom steps of a canary rollout.
+	Step []Plugin `json:"step,omitempty"`
+	// ChecksumPolicy controls whether a sha256 checksum is required on every plugin. When set to Required, a plugin without a checksum
+	// causes the RolloutManager to fail. Defaults to Optional.
+	// +kubebuilder:validation:Enum=Optional;Required
+	ChecksumPolicy PluginChecksumPolicy `json:"checksumPolicy,omitempty"`
+	// Prefetch configures an init container which downloads the plugins before the Argo Rollouts controller starts, rather than
+	// having the controller download them on every start.
+	Prefetch *PluginPrefetchSpec `json:"prefetch,omitempty"`
+}
+
+// PluginPrefetchSpec is used to download the plugins into the plugin-bin volume of the Argo Rollouts controller pod, using an init container.
+type PluginPrefetchSpec struct {
+	// Enabled will download the plugins with an http(s):// location using an init container, verify their sha256 checksum, and point
+	// the Argo Rollouts controller at the downloaded files using file:// locations.
+	Enabled bool `json:"enabled"`
+	// Image of the init container. The image must provide 'sh', 'curl' and 'sha256sum', and run as a non-root user.
+	// Defaults to registry.access.redhat.com/ubi9/ubi-minimal.
+	Image string `json:"image,omitempty"`
 }
 
-// ArgoRolloutsNodePlacementSpec is used to specify NodeSelector and Tolerations for Rollouts workloads
+// PluginChecksumPolicy defines whether plugins must be verified using a sha256 checksum.
+type PluginChecksumPolicy string
+
+const (
+	// PluginChecksumPolicyOptional verifies the checksum of a plugin only when one is specified.
+	PluginChecksumPolicyOptional PluginChecksumPolicy = "Optional"
+	// PluginChecksumPolicyRequired requires a checksum on every plugin.
+	PluginChecksumPolicyRequired PluginChecksumPolicy = "Required"
+)
+
+// ArgoRolloutsNodePlacementSpec is used to specify the scheduling of Rollouts workloads. NodeSelector and Tolerations apply to the controller and dashboard pods, the other fields to the controller pods only.
 type RolloutsNodePlacementSpec struct {
 	// NodeSelector is a field of PodSpec, it is a map of key value pairs used for node selection
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
 	// Tolerations allow the pods to schedule onto nodes with matching taints
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+	// Affinity is the scheduling affinity of the Argo Rollouts controller pods.
+	// When more than one replica is requested, the pod anti-affinity that spreads the controller pods across nodes is added to it.
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+	// TopologySpreadConstraints describes how the Argo Rollouts controller pods are spread across topology domains, such as zones
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+	// PriorityClassName is the name of the PriorityClass of the Argo Rollouts controller pods
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+	// RuntimeClassName is the name of the RuntimeClass used to run the Argo Rollouts controller pods
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
 }
 
 // RolloutManagerStatus defines the observed state of RolloutManager
@@ -99,6 +460,9 @@ type RolloutManagerStatus struct {
 
 	// Conditions is an array of the RolloutManager's status conditions
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ObservedGeneration is the .metadata.generation of the RolloutManager that was last reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 type RolloutControllerPhase string
@@ -112,6 +476,30 @@ const (
 
 const (
 	RolloutManagerConditionType = "Reconciled"
+
+	// RolloutManagerCRDVersionSkewConditionType is set when the management of the Argo Rollouts CRDs is enabled.
+	// It is true when the CRDs on the cluster do not match the version of the Argo Rollouts controller.
+	RolloutManagerCRDVersionSkewConditionType = "CRDVersionSkew"
+
+	// RolloutManagerReadyConditionType is true when the RolloutManager has been reconciled, and the Argo Rollouts controller is available.
+	RolloutManagerReadyConditionType = "Ready"
+
+	// RolloutManagerProgressingConditionType is true while the RolloutManager has been reconciled, but the Argo Rollouts controller is not yet available.
+	RolloutManagerProgressingConditionType = "Progressing"
+)
+
+// The conditions of the steps of the reconciliation of a RolloutManager, in the order they are reconciled.
+// When a step fails, its condition is false, with the reason and message of the failure, and the conditions of the next steps are unknown.
+const (
+	RolloutManagerConfigurationValidConditionType            = "ConfigurationValid"
+	RolloutManagerCRDsReconciledConditionType                = "CRDsReconciled"
+	RolloutManagerRBACReconciledConditionType                = "RBACReconciled"
+	RolloutManagerNotificationsReconciledConditionType       = "NotificationsReconciled"
+	RolloutManagerConfigMapReconciledConditionType           = "ConfigMapReconciled"
+	RolloutManagerDeploymentReconciledConditionType          = "DeploymentReconciled"
+	RolloutManagerPodDisruptionBudgetReconciledConditionType = "PodDisruptionBudgetReconciled"
+	RolloutManagerMetricsReconciledConditionType             = "MetricsReconciled"
+	RolloutManagerDashboardReconciledConditionType           = "DashboardReconciled"
 )
 
 const (
@@ -120,6 +508,25 @@ const (
 	RolloutManagerReasonMultipleClusterScopedRolloutManager = "MultipleClusterScopedRolloutManager"
 	RolloutManagerReasonInvalidScoped                       = "InvalidRolloutManagerScope"
 	RolloutManagerReasonInvalidNamespace                    = "InvalidRolloutManagerNamespace"
+	RolloutManagerReasonInvalidHAConfiguration              = "InvalidHAConfiguration"
+	RolloutManagerReasonInvalidNotificationConfiguration    = "InvalidNotificationConfiguration"
+	RolloutManagerReasonInvalidPluginConfiguration          = "InvalidPluginConfiguration"
+	RolloutManagerReasonInvalidPodConfiguration             = "InvalidPodConfiguration"
+	RolloutManagerReasonInvalidOverrideConfiguration        = "InvalidOverrideConfiguration"
+	RolloutManagerReasonInvalidCRDManagementConfiguration   = "InvalidCRDManagementConfiguration"
+	RolloutManagerReasonInvalidControllerConfiguration      = "InvalidControllerConfiguration"
+	RolloutManagerReasonInvalidExtraCommandArgs             = "InvalidExtraCommandArgs"
+	RolloutManagerReasonMissingEnvSource                    = "MissingEnvSource"
+	RolloutManagerReasonMissingNotificationSource           = "MissingNotificationSource"
+	RolloutManagerReasonPluginFetchFailed                   = "PluginFetchFailed"
+	RolloutManagerReasonRolloutsControllerFailed            = "RolloutsControllerFailed"
+	RolloutManagerReasonProgressDeadlineExceeded            = "ProgressDeadlineExceeded"
+	RolloutManagerReasonReadinessDeadlineExceeded           = "ReadinessDeadlineExceeded"
+	RolloutManagerReasonCRDVersionSkew                      = "CRDVersionSkew"
+	RolloutManagerReasonCRDVersionMatch                     = "CRDVersionMatch"
+	RolloutManagerReasonNotReconciled                       = "NotReconciled"
+	RolloutManagerReasonAvailable                           = "Available"
+	RolloutManagerReasonNotAvailable                        = "NotAvailable"
 )
 
 type ResourceMetadata struct {
@@ -133,6 +540,7 @@ type ResourceMetadata struct {
 
 //+kubebuilder:object:root=true
 //+kubebuilder:subresource:status
+//+kubebuilder:storageversion
 
 // RolloutManager is the Schema for the RolloutManagers API
 type RolloutManager struct {
@@ -0,0 +1,92 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// These defaults are shared by the webhooks of this package and the controller.
+const (
+	// ArgoRolloutsImageEnvName is an environment variable that can be used to deploy a
+	// Custom Image of rollouts controller.
+	ArgoRolloutsImageEnvName = "ARGO_ROLLOUTS_IMAGE"
+
+	// ArgoRolloutsDefaultImage is the default image for rollouts controller.
+	DefaultArgoRolloutsImage = "quay.io/argoproj/argo-rollouts"
+
+	// ArgoRolloutsDefaultVersion is the default version for the Rollouts controller.
+	DefaultArgoRolloutsVersion = "v1.7.1" // v1.7.1
+
+	// OpenShiftRolloutPluginName is the plugin name for Openshift Route Plugin
+	OpenShiftRolloutPluginName = "argoproj-labs/openshift"
+
+	// NamespacedCommandArg is the Rollouts controller argument that is managed by the operator, based on .spec.namespaceScoped
+	NamespacedCommandArg = "--namespaced"
+
+	// LeaderElectCommandArg is the Rollouts controller argument that enables leader election, set by .spec.leaderElection.enabled,
+	// and managed by the operator when more than one replica is requested
+	LeaderElectCommandArg = "--leader-elect"
+
+	// LeaderElectionLeaseDurationCommandArg is the Rollouts controller argument set by .spec.leaderElection.leaseDuration
+	LeaderElectionLeaseDurationCommandArg = "--leader-election-lease-duration"
+
+	// LeaderElectionRenewDeadlineCommandArg is the Rollouts controller argument set by .spec.leaderElection.renewDeadline
+	LeaderElectionRenewDeadlineCommandArg = "--leader-election-renew-deadline"
+
+	// LeaderElectionRetryPeriodCommandArg is the Rollouts controller argument set by .spec.leaderElection.retryPeriod
+	LeaderElectionRetryPeriodCommandArg = "--leader-election-retry-period"
+
+	// LogLevelCommandArg is the Rollouts controller argument set by .spec.logging.level
+	LogLevelCommandArg = "--loglevel"
+
+	// LogFormatCommandArg is the Rollouts controller argument set by .spec.logging.format
+	LogFormatCommandArg = "--logformat"
+
+	// KlogLevelCommandArg is the Rollouts controller argument set by .spec.logging.klogLevel
+	KlogLevelCommandArg = "--kloglevel"
+
+	// RolloutThreadsCommandArg is the Rollouts controller argument set by .spec.tuning.rolloutThreads
+	RolloutThreadsCommandArg = "--rollout-threads"
+
+	// AnalysisThreadsCommandArg is the Rollouts controller argument set by .spec.tuning.analysisThreads
+	AnalysisThreadsCommandArg = "--analysis-threads"
+
+	// QPSCommandArg is the Rollouts controller argument set by .spec.tuning.qps
+	QPSCommandArg = "--qps"
+
+	// BurstCommandArg is the Rollouts controller argument set by .spec.tuning.burst
+	BurstCommandArg = "--burst"
+
+	// RolloutsContainerName is the name of the Argo Rollouts controller container, which is managed by the operator
+	RolloutsContainerName = "argo-rollouts"
+
+	// PluginFetcherContainerName is the name of the init container which downloads the plugins, when plugin prefetch is enabled.
+	PluginFetcherContainerName = "plugin-fetcher"
+
+	// PluginSourceNamePrefix is the prefix of the volume, and init container, names of the plugins that are loaded from a source.
+	PluginSourceNamePrefix = "plugin-source-"
+
+	// PluginBinVolumeName is the name of the volume of the Argo Rollouts controller pod into which the plugins are loaded.
+	PluginBinVolumeName = "plugin-bin"
+
+	// TmpVolumeName is the name of the volume of the Argo Rollouts controller pod which is mounted at /tmp.
+	TmpVolumeName = "tmp"
+
+	// DefaultArgoRolloutsResourceName is the default name for Rollouts controller resources such as
+	// deployment, service, role, rolebinding and serviceaccount.
+	DefaultArgoRolloutsResourceName = "argo-rollouts"
+
+	// DefaultArgoRolloutsMetricsServiceName is the default name for rollouts metrics Service.
+	DefaultArgoRolloutsMetricsServiceName = "argo-rollouts-metrics"
+)
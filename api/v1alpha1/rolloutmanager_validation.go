@@ -0,0 +1,274 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+// The validation rules of this file are shared by the validating webhook, which rejects an invalid RolloutManager, and by the controller,
+// which reports the first error on the status of a RolloutManager that was admitted without the webhook.
+
+// CommandArg is a flag of the Rollouts controller command, along with its values, as it appears on the command line.
+// +kubebuilder:object:generate=false
+type CommandArg struct {
+	// Flag is the name of the flag, such as '--loglevel', or empty for arguments which precede the first flag
+	Flag string
+	// Tokens are the arguments which make up the flag and its values, such as ['--loglevel', 'debug'] or ['--loglevel=debug']
+	Tokens []string
+}
+
+// ParseCommandArgs splits command arguments into flags, each followed by its values. The value of a flag may either be part of the
+// flag, as in '--loglevel=debug', or be given by the arguments which follow the flag, up to the next flag.
+func ParseCommandArgs(args []string) []CommandArg {
+	var res []CommandArg
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+			res = append(res, CommandArg{Flag: strings.SplitN(arg, "=", 2)[0], Tokens: []string{arg}})
+			continue
+		}
+		if len(res) == 0 {
+			res = append(res, CommandArg{})
+		}
+		res[len(res)-1].Tokens = append(res[len(res)-1].Tokens, arg)
+	}
+	return res
+}
+
+// getTypedCommandArgs returns the flags of the Rollouts controller command which are set by the leader election, logging and tuning
+// configuration of the RolloutManager, keyed by flag, along with the field which sets them.
+func getTypedCommandArgs(spec RolloutManagerSpec) map[string]string {
+	res := map[string]string{}
+
+	if leaderElection := spec.LeaderElection; leaderElection != nil {
+		if leaderElection.Enabled != nil {
+			res[LeaderElectCommandArg] = ".spec.leaderElection.enabled"
+		}
+		if leaderElection.LeaseDuration != nil {
+			res[LeaderElectionLeaseDurationCommandArg] = ".spec.leaderElection.leaseDuration"
+		}
+		if leaderElection.RenewDeadline != nil {
+			res[LeaderElectionRenewDeadlineCommandArg] = ".spec.leaderElection.renewDeadline"
+		}
+		if leaderElection.RetryPeriod != nil {
+			res[LeaderElectionRetryPeriodCommandArg] = ".spec.leaderElection.retryPeriod"
+		}
+	}
+
+	if logging := spec.Logging; logging != nil {
+		if logging.Level != "" {
+			res[LogLevelCommandArg] = ".spec.logging.level"
+		}
+		if logging.Format != "" {
+			res[LogFormatCommandArg] = ".spec.logging.format"
+		}
+		if logging.KlogLevel != nil {
+			res[KlogLevelCommandArg] = ".spec.logging.klogLevel"
+		}
+	}
+
+	if tuning := spec.Tuning; tuning != nil {
+		if tuning.RolloutThreads != nil {
+			res[RolloutThreadsCommandArg] = ".spec.tuning.rolloutThreads"
+		}
+		if tuning.AnalysisThreads != nil {
+			res[AnalysisThreadsCommandArg] = ".spec.tuning.analysisThreads"
+		}
+		if tuning.QPS != nil {
+			res[QPSCommandArg] = ".spec.tuning.qps"
+		}
+		if tuning.Burst != nil {
+			res[BurstCommandArg] = ".spec.tuning.burst"
+		}
+	}
+
+	return res
+}
+
+// ValidateExtraCommandArgs verifies that the extra command arguments can be merged into the Rollouts controller command: they must not set
+// the flags owned by the operator, nor those set by another field of the RolloutManager, nor set a flag more than once.
+func ValidateExtraCommandArgs(fldPath *field.Path, spec RolloutManagerSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	typedCommandArgs := getTypedCommandArgs(spec)
+	multipleReplicas := spec.Replicas != nil && *spec.Replicas > 1
+
+	flags := map[string]bool{}
+	idx := 0
+	for _, arg := range ParseCommandArgs(spec.ExtraCommandArgs) {
+		argPath := fldPath.Index(idx)
+		idx += len(arg.Tokens)
+
+		if arg.Flag == "" {
+			allErrs = append(allErrs, field.Invalid(argPath, strings.Join(arg.Tokens, " "), fmt.Sprintf("%s does not follow a flag", strings.Join(arg.Tokens, " "))))
+			continue
+		}
+		if arg.Flag == NamespacedCommandArg {
+			allErrs = append(allErrs, field.Forbidden(argPath, fmt.Sprintf("%s is owned by the operator, use .spec.namespaceScoped instead", arg.Flag)))
+			continue
+		}
+		if arg.Flag == LeaderElectCommandArg && multipleReplicas {
+			allErrs = append(allErrs, field.Forbidden(argPath, fmt.Sprintf("%s is owned by the operator when more than one replica is requested", arg.Flag)))
+			continue
+		}
+		if typedField, exists := typedCommandArgs[arg.Flag]; exists {
+			allErrs = append(allErrs, field.Forbidden(argPath, fmt.Sprintf("%s is set by %s, and must not also be set in .spec.extraCommandArgs", arg.Flag, typedField)))
+			continue
+		}
+		if flags[arg.Flag] {
+			err := field.Duplicate(argPath, arg.Flag)
+			err.Detail = fmt.Sprintf("%s is set more than once", arg.Flag)
+			allErrs = append(allErrs, err)
+		}
+		flags[arg.Flag] = true
+	}
+
+	return allErrs
+}
+
+// IsReservedContainerName returns true if name is the name of a container, or init container, of the Argo Rollouts controller pod which
+// is managed by the operator, and which therefore may not be used by an extra container.
+func IsReservedContainerName(name string) bool {
+	return name == RolloutsContainerName || name == PluginFetcherContainerName || strings.HasPrefix(name, PluginSourceNamePrefix)
+}
+
+// IsReservedVolumeName returns true if name is the name of a volume of the Argo Rollouts controller pod which is managed by the operator,
+// and which therefore may not be used by an extra volume.
+func IsReservedVolumeName(name string) bool {
+	return name == PluginBinVolumeName || name == TmpVolumeName || strings.HasPrefix(name, PluginSourceNamePrefix)
+}
+
+// ValidateExtraContainers verifies that the extra containers have unique names, which are not reserved by the operator.
+func ValidateExtraContainers(fldPath *field.Path, containers []corev1.Container) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{}
+	for i, container := range containers {
+		namePath := fldPath.Index(i).Child("name")
+		if IsReservedContainerName(container.Name) {
+			allErrs = append(allErrs, field.Forbidden(namePath, fmt.Sprintf("the name of extra container %s is reserved by the operator", container.Name)))
+			continue
+		}
+		if names[container.Name] {
+			err := field.Duplicate(namePath, container.Name)
+			err.Detail = fmt.Sprintf("extra container %s is defined more than once", container.Name)
+			allErrs = append(allErrs, err)
+		}
+		names[container.Name] = true
+	}
+	return allErrs
+}
+
+// ValidateExtraVolumes verifies that the extra volumes have unique names, which are not reserved by the operator.
+func ValidateExtraVolumes(fldPath *field.Path, volumes []corev1.Volume) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{}
+	for i, volume := range volumes {
+		namePath := fldPath.Index(i).Child("name")
+		if IsReservedVolumeName(volume.Name) {
+			allErrs = append(allErrs, field.Forbidden(namePath, fmt.Sprintf("the name of extra volume %s is reserved by the operator", volume.Name)))
+			continue
+		}
+		if names[volume.Name] {
+			err := field.Duplicate(namePath, volume.Name)
+			err.Detail = fmt.Sprintf("extra volume %s is defined more than once", volume.Name)
+			allErrs = append(allErrs, err)
+		}
+		names[volume.Name] = true
+	}
+	return allErrs
+}
+
+// ValidateNotificationSecretSources verifies that no two keys of the notification Secret sources are merged under the same target key, since one
+// would silently replace the other. Sources without keys merge every key of their Secret, and are verified by the controller once the Secret is read.
+func ValidateNotificationSecretSources(fldPath *field.Path, sources []NotificationSecretSource) field.ErrorList {
+	var allErrs field.ErrorList
+	targetKeys := map[string]bool{}
+	for i, source := range sources {
+		for j, mapping := range source.Keys {
+			targetKey := mapping.TargetKey
+			if targetKey == "" {
+				targetKey = mapping.Key
+			}
+			if targetKeys[targetKey] {
+				err := field.Duplicate(fldPath.Index(i).Child("keys").Index(j), targetKey)
+				err.Detail = fmt.Sprintf("target key %s is provided by more than one notification Secret source key", targetKey)
+				allErrs = append(allErrs, err)
+			}
+			targetKeys[targetKey] = true
+		}
+	}
+	return allErrs
+}
+
+// ValidateCRDManagement verifies that the management of the Argo Rollouts CRDs, which are cluster-scoped, is only enabled on a cluster-scoped RolloutManager.
+func ValidateCRDManagement(fldPath *field.Path, spec RolloutManagerSpec) field.ErrorList {
+	if spec.CRDManagement == nil || !spec.CRDManagement.Enabled || !spec.NamespaceScoped {
+		return nil
+	}
+	return field.ErrorList{field.Forbidden(fldPath.Child("enabled"), "the Argo Rollouts CRDs may only be managed by a cluster-scoped RolloutManager")}
+}
+
+// OverrideTargets are the names of the resources which may be patched by .spec.overrides, by kind.
+var OverrideTargets = map[string]string{
+	"Deployment":     DefaultArgoRolloutsResourceName,
+	"Service":        DefaultArgoRolloutsMetricsServiceName,
+	"ServiceAccount": DefaultArgoRolloutsResourceName,
+	"Role":           DefaultArgoRolloutsResourceName,
+	"ClusterRole":    DefaultArgoRolloutsResourceName,
+	"ServiceMonitor": DefaultArgoRolloutsResourceName,
+}
+
+// ValidateOverrides verifies that every override targets a resource managed by the operator, and holds a patch that can be parsed, according to its type.
+func ValidateOverrides(fldPath *field.Path, overrides []ResourceOverride) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, override := range overrides {
+		overridePath := fldPath.Index(i)
+
+		targetName, exists := OverrideTargets[override.Kind]
+		if !exists {
+			allErrs = append(allErrs, field.Invalid(overridePath.Child("kind"), override.Kind, fmt.Sprintf("resources of kind %s are not managed by the operator", override.Kind)))
+			continue
+		}
+		if override.Name != targetName {
+			allErrs = append(allErrs, field.Invalid(overridePath.Child("name"), override.Name, fmt.Sprintf("the operator manages the %s %s, not %s", override.Kind, targetName, override.Name)))
+			continue
+		}
+
+		patchPath := overridePath.Child("patch")
+
+		patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(patchPath, override.Patch, fmt.Sprintf("the patch of %s %s is not valid YAML or JSON: %v", override.Kind, override.Name, err)))
+			continue
+		}
+
+		if override.Type == ResourceOverridePatchTypeJSON {
+			if _, err := jsonpatch.DecodePatch(patch); err != nil {
+				allErrs = append(allErrs, field.Invalid(patchPath, override.Patch, fmt.Sprintf("the patch of %s %s is not a valid JSON patch, which must be a list of operations: %v", override.Kind, override.Name, err)))
+			}
+		} else if !strings.HasPrefix(strings.TrimSpace(string(patch)), "{") {
+			allErrs = append(allErrs, field.Invalid(patchPath, override.Patch, fmt.Sprintf("the patch of %s %s is not a valid strategic merge patch, which must be an object", override.Kind, override.Name)))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+var _ = Describe("RolloutManager validation tests", func() {
+
+	It("should split the command arguments into flags and their values", func() {
+		Expect(ParseCommandArgs([]string{"value0", "--flag1", "--flag2=value2", "--flag3", "value3", "value4"})).To(Equal([]CommandArg{
+			{Flag: "", Tokens: []string{"value0"}},
+			{Flag: "--flag1", Tokens: []string{"--flag1"}},
+			{Flag: "--flag2", Tokens: []string{"--flag2=value2"}},
+			{Flag: "--flag3", Tokens: []string{"--flag3", "value3", "value4"}},
+		}))
+	})
+
+	It("should report every invalid extra command argument, at the index of its flag", func() {
+		replicas := int32(3)
+		spec := RolloutManagerSpec{
+			Replicas: &replicas,
+			Tuning:   &RolloutsTuningSpec{QPS: &replicas},
+			ExtraCommandArgs: []string{
+				"--loglevel", "debug",
+				"--namespaced",
+				"--leader-elect",
+				"--qps=10",
+				"--loglevel=info",
+			},
+		}
+
+		errs := ValidateExtraCommandArgs(field.NewPath("spec", "extraCommandArgs"), spec)
+		Expect(errs).To(HaveLen(4))
+		Expect(errs[0].Field).To(Equal("spec.extraCommandArgs[2]"))
+		Expect(errs[0].Detail).To(Equal("--namespaced is owned by the operator, use .spec.namespaceScoped instead"))
+		Expect(errs[1].Field).To(Equal("spec.extraCommandArgs[3]"))
+		Expect(errs[1].Detail).To(Equal("--leader-elect is owned by the operator when more than one replica is requested"))
+		Expect(errs[2].Field).To(Equal("spec.extraCommandArgs[4]"))
+		Expect(errs[2].Detail).To(Equal("--qps is set by .spec.tuning.qps, and must not also be set in .spec.extraCommandArgs"))
+		Expect(errs[3].Field).To(Equal("spec.extraCommandArgs[5]"))
+		Expect(errs[3].Type).To(Equal(field.ErrorTypeDuplicate))
+	})
+
+	leaderElectionDisabled := false
+
+	DescribeTable("should reject an extra command argument which is set by .spec.leaderElection", func(leaderElection RolloutsLeaderElectionSpec, extraCommandArg string, expectedDetail string) {
+		spec := RolloutManagerSpec{
+			LeaderElection:   &leaderElection,
+			ExtraCommandArgs: []string{"--loglevel", "debug", extraCommandArg},
+		}
+
+		errs := ValidateExtraCommandArgs(field.NewPath("spec", "extraCommandArgs"), spec)
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.extraCommandArgs[2]"))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+		Expect(errs[0].Detail).To(Equal(expectedDetail))
+	},
+		Entry("--leader-elect", RolloutsLeaderElectionSpec{Enabled: &leaderElectionDisabled}, "--leader-elect=true",
+			"--leader-elect is set by .spec.leaderElection.enabled, and must not also be set in .spec.extraCommandArgs"),
+		Entry("--leader-election-lease-duration", RolloutsLeaderElectionSpec{LeaseDuration: &metav1.Duration{Duration: 30 * time.Second}},
+			"--leader-election-lease-duration=1m",
+			"--leader-election-lease-duration is set by .spec.leaderElection.leaseDuration, and must not also be set in .spec.extraCommandArgs"),
+		Entry("--leader-election-renew-deadline", RolloutsLeaderElectionSpec{RenewDeadline: &metav1.Duration{Duration: 20 * time.Second}},
+			"--leader-election-renew-deadline=1m",
+			"--leader-election-renew-deadline is set by .spec.leaderElection.renewDeadline, and must not also be set in .spec.extraCommandArgs"),
+		Entry("--leader-election-retry-period", RolloutsLeaderElectionSpec{RetryPeriod: &metav1.Duration{Duration: 5 * time.Second}},
+			"--leader-election-retry-period=10s",
+			"--leader-election-retry-period is set by .spec.leaderElection.retryPeriod, and must not also be set in .spec.extraCommandArgs"),
+	)
+
+	It("should accept a leader election extra command argument whose .spec.leaderElection field is not set", func() {
+		leaderElectionEnabled := true
+		spec := RolloutManagerSpec{
+			LeaderElection:   &RolloutsLeaderElectionSpec{Enabled: &leaderElectionEnabled},
+			ExtraCommandArgs: []string{"--leader-election-lease-duration=1m", "--leader-election-renew-deadline", "40s", "--leader-election-retry-period=10s"},
+		}
+
+		Expect(ValidateExtraCommandArgs(field.NewPath("spec", "extraCommandArgs"), spec)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,289 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var (
+	// imageNameRegexp matches an image repository, optionally prefixed by a registry host (and port), without a tag or digest.
+	imageNameRegexp = regexp.MustCompile(`^(?:[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]*[a-zA-Z0-9])?)*(?::[0-9]+)?/)?[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+
+	// imageTagRegexp matches an image tag.
+	imageTagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+	// imageDigestRegexp matches an image digest, such as 'sha256:<hex>'.
+	imageDigestRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,}$`)
+)
+
+// log is for logging in this package.
+var rolloutmanagerlog = logf.Log.WithName("rolloutmanager-resource")
+
+// SetupWebhookWithManager registers the defaulting and validating webhooks of RolloutManager with the manager.
+func (r *RolloutManager) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-argoproj-io-v1alpha1-rolloutmanager,mutating=true,failurePolicy=fail,sideEffects=None,groups=argoproj.io,resources=rolloutmanagers,verbs=create;update,versions=v1alpha1,name=mrolloutmanager.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &RolloutManager{}
+
+// Default implements webhook.Defaulter so that the effective Argo Rollouts controller image and version are visible in the object.
+func (r *RolloutManager) Default() {
+	rolloutmanagerlog.Info("default", "name", r.Name, "namespace", r.Namespace)
+
+	if r.Spec.Image == "" && r.Spec.Version == "" {
+		// The image env var only applies when neither value is set in the spec.
+		if e := os.Getenv(ArgoRolloutsImageEnvName); e != "" {
+			image, version := splitImageReference(e)
+			if version == "" {
+				// Leave the spec unset, so that the env var value is used as is by the controller.
+				return
+			}
+			r.Spec.Image = image
+			r.Spec.Version = version
+			return
+		}
+	}
+
+	if r.Spec.Image == "" {
+		r.Spec.Image = DefaultArgoRolloutsImage
+	}
+	if r.Spec.Version == "" {
+		r.Spec.Version = DefaultArgoRolloutsVersion
+	}
+}
+
+//+kubebuilder:webhook:path=/validate-argoproj-io-v1alpha1-rolloutmanager,mutating=false,failurePolicy=fail,sideEffects=None,groups=argoproj.io,resources=rolloutmanagers,verbs=create;update,versions=v1alpha1,name=vrolloutmanager.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &RolloutManager{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *RolloutManager) ValidateCreate() (admission.Warnings, error) {
+	rolloutmanagerlog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
+
+	return nil, r.validateRolloutManager()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *RolloutManager) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	rolloutmanagerlog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
+
+	return nil, r.validateRolloutManager()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *RolloutManager) ValidateDelete() (admission.Warnings, error) {
+	// Nothing to validate on deletion.
+	return nil, nil
+}
+
+// validateRolloutManager returns an Invalid error containing every problem found in the spec, or nil if the spec is valid.
+func (r *RolloutManager) validateRolloutManager() error {
+	specPath := field.NewPath("spec")
+
+	var allErrs field.ErrorList
+	allErrs = append(allErrs, validateImage(specPath.Child("image"), r.Spec.Image)...)
+	allErrs = append(allErrs, validateVersion(specPath.Child("version"), r.Spec.Version)...)
+	allErrs = append(allErrs, ValidateExtraCommandArgs(specPath.Child("extraCommandArgs"), r.Spec)...)
+	allErrs = append(allErrs, validateTuning(specPath.Child("tuning"), r.Spec.Tuning)...)
+	allErrs = append(allErrs, validatePlugins(specPath.Child("plugins"), r.Spec.Plugins)...)
+	allErrs = append(allErrs, ValidateExtraContainers(specPath.Child("extraContainers"), r.Spec.ExtraContainers)...)
+	allErrs = append(allErrs, ValidateExtraVolumes(specPath.Child("extraVolumes"), r.Spec.ExtraVolumes)...)
+	allErrs = append(allErrs, ValidateNotificationSecretSources(specPath.Child("notificationSecretSources"), r.Spec.NotificationSecretSources)...)
+	allErrs = append(allErrs, ValidateOverrides(specPath.Child("overrides"), r.Spec.Overrides)...)
+	allErrs = append(allErrs, ValidateCRDManagement(specPath.Child("crdManagement"), r.Spec)...)
+
+	if r.Spec.Dashboard != nil {
+		dashboardPath := specPath.Child("dashboard")
+		allErrs = append(allErrs, validateImage(dashboardPath.Child("image"), r.Spec.Dashboard.Image)...)
+		allErrs = append(allErrs, validateVersion(dashboardPath.Child("version"), r.Spec.Dashboard.Version)...)
+	}
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(GroupVersion.WithKind("RolloutManager").GroupKind(), r.Name, allErrs)
+}
+
+// validateImage verifies that an image, if set, is a repository name without a tag or digest.
+func validateImage(fldPath *field.Path, image string) field.ErrorList {
+	if image == "" || imageNameRegexp.MatchString(image) {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath, image, "must be a valid image repository name, without a tag or digest")}
+}
+
+// validateVersion verifies that a version, if set, is either an image tag or an image digest.
+func validateVersion(fldPath *field.Path, version string) field.ErrorList {
+	if version == "" || imageTagRegexp.MatchString(version) || imageDigestRegexp.MatchString(version) {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath, version, "must be a valid image tag, or an image digest such as 'sha256:<hex>'")}
+}
+
+// validateTuning verifies that the burst of the Kubernetes client of the Argo Rollouts controller is not lower than its QPS.
+func validateTuning(fldPath *field.Path, tuning *RolloutsTuningSpec) field.ErrorList {
+	if tuning == nil || tuning.QPS == nil || tuning.Burst == nil || *tuning.Burst >= *tuning.QPS {
+		return nil
+	}
+	return field.ErrorList{field.Invalid(fldPath.Child("burst"), *tuning.Burst, "must not be lower than qps")}
+}
+
+// validatePlugins verifies that plugin names are unique within each plugin type, that the OpenShift Route plugin is not redefined,
+// that every plugin has either a location or a single source, and that every plugin has a checksum when checksums are required.
+func validatePlugins(fldPath *field.Path, plugins Plugins) field.ErrorList {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, validatePluginSources(fldPath.Child("trafficManagement"), plugins.TrafficManagement)...)
+	allErrs = append(allErrs, validatePluginSources(fldPath.Child("metric"), plugins.Metric)...)
+	allErrs = append(allErrs, validatePluginSources(fldPath.Child("step"), plugins.Step)...)
+
+	if plugins.ChecksumPolicy == PluginChecksumPolicyRequired {
+		allErrs = append(allErrs, validatePluginChecksums(fldPath.Child("trafficManagement"), plugins.TrafficManagement)...)
+		allErrs = append(allErrs, validatePluginChecksums(fldPath.Child("metric"), plugins.Metric)...)
+		allErrs = append(allErrs, validatePluginChecksums(fldPath.Child("step"), plugins.Step)...)
+	}
+
+	trafficPath := fldPath.Child("trafficManagement")
+	trafficNames := map[string]bool{}
+	for i, plugin := range plugins.TrafficManagement {
+		if plugin.Name == OpenShiftRolloutPluginName {
+			allErrs = append(allErrs, field.Forbidden(trafficPath.Index(i).Child("name"), "the plugin "+OpenShiftRolloutPluginName+" cannot be modified or added through the RolloutManager CR"))
+			continue
+		}
+		if trafficNames[plugin.Name] {
+			allErrs = append(allErrs, field.Duplicate(trafficPath.Index(i).Child("name"), plugin.Name))
+		}
+		trafficNames[plugin.Name] = true
+	}
+
+	metricPath := fldPath.Child("metric")
+	metricNames := map[string]bool{}
+	for i, plugin := range plugins.Metric {
+		if metricNames[plugin.Name] {
+			allErrs = append(allErrs, field.Duplicate(metricPath.Index(i).Child("name"), plugin.Name))
+		}
+		metricNames[plugin.Name] = true
+	}
+
+	stepPath := fldPath.Child("step")
+	stepNames := map[string]bool{}
+	for i, plugin := range plugins.Step {
+		if stepNames[plugin.Name] {
+			allErrs = append(allErrs, field.Duplicate(stepPath.Index(i).Child("name"), plugin.Name))
+		}
+		stepNames[plugin.Name] = true
+	}
+
+	return allErrs
+}
+
+// validatePluginSources verifies that every plugin of the list has either a location or a source, and that a source sets exactly one of its fields.
+func validatePluginSources(fldPath *field.Path, plugins []Plugin) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, plugin := range plugins {
+		pluginPath := fldPath.Index(i)
+		source := plugin.Source
+
+		if source == nil {
+			if plugin.Location == "" {
+				allErrs = append(allErrs, field.Required(pluginPath.Child("location"), "a location is required when no source is set"))
+			}
+			continue
+		}
+
+		sourcePath := pluginPath.Child("source")
+		if plugin.Location != "" {
+			allErrs = append(allErrs, field.Forbidden(pluginPath.Child("location"), "may not be set together with "+sourcePath.String()))
+		}
+
+		var sourcesSet []string
+		if source.Image != nil {
+			sourcesSet = append(sourcesSet, "image")
+			if source.Image.Reference == "" {
+				allErrs = append(allErrs, field.Required(sourcePath.Child("image", "reference"), ""))
+			}
+			if source.Image.Path == "" {
+				allErrs = append(allErrs, field.Required(sourcePath.Child("image", "path"), ""))
+			}
+		}
+		volumeSources := []struct {
+			name   string
+			source *PluginVolumeSource
+		}{
+			{"persistentVolumeClaim", source.PersistentVolumeClaim},
+			{"configMap", source.ConfigMap},
+			{"secret", source.Secret},
+		}
+		for _, vs := range volumeSources {
+			if vs.source == nil {
+				continue
+			}
+			sourcesSet = append(sourcesSet, vs.name)
+			if vs.source.Name == "" {
+				allErrs = append(allErrs, field.Required(sourcePath.Child(vs.name, "name"), ""))
+			}
+			if vs.source.Path == "" {
+				allErrs = append(allErrs, field.Required(sourcePath.Child(vs.name, "path"), ""))
+			}
+		}
+
+		if len(sourcesSet) != 1 {
+			allErrs = append(allErrs, field.Invalid(sourcePath, strings.Join(sourcesSet, ", "), "exactly one of image, persistentVolumeClaim, configMap and secret must be set"))
+		}
+	}
+	return allErrs
+}
+
+// validatePluginChecksums verifies that every plugin of the list has a sha256 checksum.
+func validatePluginChecksums(fldPath *field.Path, plugins []Plugin) field.ErrorList {
+	var allErrs field.ErrorList
+	for i, plugin := range plugins {
+		if plugin.SHA256 == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("sha256"), "a checksum is required when the checksum policy is "+string(PluginChecksumPolicyRequired)))
+		}
+	}
+	return allErrs
+}
+
+// splitImageReference splits an image reference such as 'quay.io/argoproj/argo-rollouts:v1.7.1' (or '...@sha256:<hex>')
+// into its repository and its tag (or digest). The returned version is empty when the reference has neither.
+func splitImageReference(ref string) (string, string) {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	// A ':' before the last '/' separates a registry host from its port, rather than a tag.
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
@@ -0,0 +1,266 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("RolloutManager webhook tests", func() {
+
+	var rm *RolloutManager
+
+	BeforeEach(func() {
+		rm = &RolloutManager{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-rollouts-manager",
+				Namespace: "test-ns",
+			},
+		}
+	})
+
+	Context("Default tests", func() {
+
+		AfterEach(func() {
+			Expect(os.Unsetenv(ArgoRolloutsImageEnvName)).To(Succeed())
+		})
+
+		It("should fill in the default image and version when they are not set", func() {
+			rm.Default()
+			Expect(rm.Spec.Image).To(Equal(DefaultArgoRolloutsImage))
+			Expect(rm.Spec.Version).To(Equal(DefaultArgoRolloutsVersion))
+		})
+
+		It("should only fill in the values that are not set", func() {
+			rm.Spec.Version = "v1.6.0"
+			rm.Default()
+			Expect(rm.Spec.Image).To(Equal(DefaultArgoRolloutsImage))
+			Expect(rm.Spec.Version).To(Equal("v1.6.0"))
+		})
+
+		DescribeTable("should use the image env var when neither image nor version are set", func(envValue, expectedImage, expectedVersion string) {
+			Expect(os.Setenv(ArgoRolloutsImageEnvName, envValue)).To(Succeed())
+			rm.Default()
+			Expect(rm.Spec.Image).To(Equal(expectedImage))
+			Expect(rm.Spec.Version).To(Equal(expectedVersion))
+		},
+			Entry("image with tag", "quay.io/my/rollouts:v1.6.0", "quay.io/my/rollouts", "v1.6.0"),
+			Entry("image with digest", "quay.io/my/rollouts@sha256:995450a0a7f7843d68e96d1a7f63422fa29b245c58f7b57dd0cf9cad72b8308f", "quay.io/my/rollouts", "sha256:995450a0a7f7843d68e96d1a7f63422fa29b245c58f7b57dd0cf9cad72b8308f"),
+			Entry("registry with port and tag", "localhost:5000/rollouts:latest", "localhost:5000/rollouts", "latest"),
+			Entry("image without tag is left to the controller", "localhost:5000/rollouts", "", ""),
+		)
+
+		It("should not use the image env var when the image is set", func() {
+			Expect(os.Setenv(ArgoRolloutsImageEnvName, "quay.io/my/rollouts:v1.6.0")).To(Succeed())
+			rm.Spec.Image = "quay.io/other/rollouts"
+			rm.Default()
+			Expect(rm.Spec.Image).To(Equal("quay.io/other/rollouts"))
+			Expect(rm.Spec.Version).To(Equal(DefaultArgoRolloutsVersion))
+		})
+	})
+
+	Context("Validate tests", func() {
+
+		It("should accept a valid RolloutManager on create, update and delete", func() {
+			rm.Spec = RolloutManagerSpec{
+				Image:            "quay.io/argoproj/argo-rollouts",
+				Version:          "v1.7.1",
+				ExtraCommandArgs: []string{"--loglevel", "debug"},
+				Plugins: Plugins{
+					TrafficManagement: []Plugin{{Name: "argoproj-labs/gatewayAPI", Location: "https://example.com/gatewayapi"}},
+					Metric:            []Plugin{{Name: "argoproj-labs/sample-prometheus", Location: "https://example.com/prometheus"}},
+					Step:              []Plugin{{Name: "argoproj-labs/sample-step", Location: "https://example.com/step", Args: []string{"--verbose"}}},
+				},
+			}
+
+			_, err := rm.ValidateCreate()
+			Expect(err).ToNot(HaveOccurred())
+			_, err = rm.ValidateUpdate(rm.DeepCopy())
+			Expect(err).ToNot(HaveOccurred())
+			_, err = rm.ValidateDelete()
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		qps, burst, replicas := int32(50), int32(20), int32(2)
+
+		DescribeTable("should reject an invalid RolloutManager", func(spec RolloutManagerSpec, expectedField string) {
+			rm.Spec = spec
+
+			_, err := rm.ValidateCreate()
+			Expect(err).To(HaveOccurred())
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(expectedField))
+
+			_, err = rm.ValidateUpdate(rm.DeepCopy())
+			Expect(err).To(HaveOccurred())
+		},
+			Entry("duplicate traffic management plugin names", RolloutManagerSpec{
+				Plugins: Plugins{TrafficManagement: []Plugin{
+					{Name: "argoproj-labs/gatewayAPI", Location: "https://example.com/a"},
+					{Name: "argoproj-labs/gatewayAPI", Location: "https://example.com/b"},
+				}},
+			}, "spec.plugins.trafficManagement[1].name"),
+			Entry("duplicate metric plugin names", RolloutManagerSpec{
+				Plugins: Plugins{Metric: []Plugin{
+					{Name: "argoproj-labs/sample-prometheus", Location: "https://example.com/a"},
+					{Name: "argoproj-labs/sample-prometheus", Location: "https://example.com/b"},
+				}},
+			}, "spec.plugins.metric[1].name"),
+			Entry("duplicate step plugin names", RolloutManagerSpec{
+				Plugins: Plugins{Step: []Plugin{
+					{Name: "argoproj-labs/sample-step", Location: "https://example.com/a"},
+					{Name: "argoproj-labs/sample-step", Location: "https://example.com/b", Args: []string{"--verbose"}},
+				}},
+			}, "spec.plugins.step[1].name"),
+			Entry("plugin without a checksum when checksums are required", RolloutManagerSpec{
+				Plugins: Plugins{
+					ChecksumPolicy: PluginChecksumPolicyRequired,
+					Metric:         []Plugin{{Name: "argoproj-labs/sample-prometheus", Location: "https://example.com/a", SHA256: "abc"}},
+					Step:           []Plugin{{Name: "argoproj-labs/sample-step", Location: "https://example.com/b"}},
+				},
+			}, "spec.plugins.step[0].sha256"),
+			Entry("plugin without a location or a source", RolloutManagerSpec{
+				Plugins: Plugins{Metric: []Plugin{{Name: "argoproj-labs/sample-prometheus"}}},
+			}, "spec.plugins.metric[0].location"),
+			Entry("plugin with both a location and a source", RolloutManagerSpec{
+				Plugins: Plugins{Metric: []Plugin{{
+					Name:     "argoproj-labs/sample-prometheus",
+					Location: "https://example.com/a",
+					Source:   &PluginSource{ConfigMap: &PluginVolumeSource{Name: "plugins", Path: "prometheus"}},
+				}}},
+			}, "spec.plugins.metric[0].location"),
+			Entry("plugin with more than one source", RolloutManagerSpec{
+				Plugins: Plugins{Step: []Plugin{{
+					Name: "argoproj-labs/sample-step",
+					Source: &PluginSource{
+						PersistentVolumeClaim: &PluginVolumeSource{Name: "plugins", Path: "bin/step"},
+						Secret:                &PluginVolumeSource{Name: "plugins", Path: "step"},
+					},
+				}}},
+			}, "spec.plugins.step[0].source"),
+			Entry("plugin with an image source without a path", RolloutManagerSpec{
+				Plugins: Plugins{TrafficManagement: []Plugin{{
+					Name:   "argoproj-labs/gatewayAPI",
+					Source: &PluginSource{Image: &PluginImageSource{Reference: "quay.io/my-org/gatewayapi:v0.4.0"}},
+				}}},
+			}, "spec.plugins.trafficManagement[0].source.image.path"),
+			Entry("redefined OpenShift Route plugin", RolloutManagerSpec{
+				Plugins: Plugins{TrafficManagement: []Plugin{
+					{Name: OpenShiftRolloutPluginName, Location: "https://example.com/openshift"},
+				}},
+			}, "spec.plugins.trafficManagement[0].name"),
+			Entry("--namespaced in extraCommandArgs", RolloutManagerSpec{
+				ExtraCommandArgs: []string{"--loglevel", "debug", "--namespaced"},
+			}, "spec.extraCommandArgs[2]"),
+			Entry("--namespaced with a value in extraCommandArgs", RolloutManagerSpec{
+				ExtraCommandArgs: []string{"--namespaced=false"},
+			}, "spec.extraCommandArgs[0]"),
+			Entry("argument which does not follow a flag in extraCommandArgs", RolloutManagerSpec{
+				ExtraCommandArgs: []string{"debug", "--loglevel", "info"},
+			}, "spec.extraCommandArgs[0]"),
+			Entry("--leader-elect in extraCommandArgs with more than one replica", RolloutManagerSpec{
+				Replicas:         &replicas,
+				ExtraCommandArgs: []string{"--leader-elect=false"},
+			}, "spec.extraCommandArgs[0]"),
+			Entry("flag of extraCommandArgs also set by .spec.logging", RolloutManagerSpec{
+				Logging:          &RolloutsLoggingSpec{Level: "debug"},
+				ExtraCommandArgs: []string{"--kloglevel", "2", "--loglevel", "info"},
+			}, "spec.extraCommandArgs[2]"),
+			Entry("flag set more than once in extraCommandArgs", RolloutManagerSpec{
+				ExtraCommandArgs: []string{"--rollout-threads", "5", "--rollout-threads=10"},
+			}, "spec.extraCommandArgs[2]"),
+			Entry("image containing a tag", RolloutManagerSpec{
+				Image: "quay.io/argoproj/argo-rollouts:v1.7.1",
+			}, "spec.image"),
+			Entry("image containing upper case characters", RolloutManagerSpec{
+				Image: "quay.io/Argoproj/argo-rollouts",
+			}, "spec.image"),
+			Entry("malformed version", RolloutManagerSpec{
+				Version: "v1.7.1 latest",
+			}, "spec.version"),
+			Entry("malformed digest", RolloutManagerSpec{
+				Version: "sha256:not-a-digest",
+			}, "spec.version"),
+			Entry("burst lower than qps", RolloutManagerSpec{
+				Tuning: &RolloutsTuningSpec{QPS: &qps, Burst: &burst},
+			}, "spec.tuning.burst"),
+			Entry("extra container named like the Argo Rollouts controller container", RolloutManagerSpec{
+				ExtraContainers: []corev1.Container{{Name: RolloutsContainerName, Image: "quay.io/my/sidecar:v1"}},
+			}, "spec.extraContainers[0].name"),
+			Entry("extra container named like the plugin fetcher init container", RolloutManagerSpec{
+				ExtraContainers: []corev1.Container{{Name: PluginFetcherContainerName, Image: "quay.io/my/sidecar:v1"}},
+			}, "spec.extraContainers[0].name"),
+			Entry("extra container named like a plugin source init container", RolloutManagerSpec{
+				ExtraContainers: []corev1.Container{{Name: "sidecar"}, {Name: PluginSourceNamePrefix + "a"}},
+			}, "spec.extraContainers[1].name"),
+			Entry("extra volume named like a volume of the operator", RolloutManagerSpec{
+				ExtraVolumes: []corev1.Volume{{Name: TmpVolumeName}},
+			}, "spec.extraVolumes[0].name"),
+			Entry("extra volume named like a plugin source volume", RolloutManagerSpec{
+				ExtraVolumes: []corev1.Volume{{Name: PluginSourceNamePrefix + "a"}},
+			}, "spec.extraVolumes[0].name"),
+			Entry("duplicate extra container names", RolloutManagerSpec{
+				ExtraContainers: []corev1.Container{{Name: "sidecar"}, {Name: "sidecar"}},
+			}, "spec.extraContainers[1].name"),
+			Entry("duplicate extra volume names", RolloutManagerSpec{
+				ExtraVolumes: []corev1.Volume{{Name: "config"}, {Name: "config"}},
+			}, "spec.extraVolumes[1].name"),
+			Entry("notification Secret source keys with the same target key", RolloutManagerSpec{
+				NotificationSecretSources: []NotificationSecretSource{
+					{Name: "team-secret", Keys: []NotificationSecretKeyMapping{{Key: "token", TargetKey: "slack-token"}}},
+					{Name: "other-team-secret", Keys: []NotificationSecretKeyMapping{{Key: "slack-token"}}},
+				},
+			}, "spec.notificationSecretSources[1].keys[0]"),
+			Entry("override with an invalid JSON patch", RolloutManagerSpec{
+				Overrides: []ResourceOverride{{Kind: "Deployment", Name: "argo-rollouts", Type: ResourceOverridePatchTypeJSON, Patch: `{"op": "add"}`}},
+			}, "spec.overrides[0].patch"),
+			Entry("override with a strategic merge patch which is not an object", RolloutManagerSpec{
+				Overrides: []ResourceOverride{{Kind: "Deployment", Name: "argo-rollouts", Patch: "- spec"}},
+			}, "spec.overrides[0].patch"),
+			Entry("override of a kind that is not managed by the operator", RolloutManagerSpec{
+				Overrides: []ResourceOverride{{Kind: "ConfigMap", Name: "argo-rollouts-config", Patch: "{}"}},
+			}, "spec.overrides[0].kind"),
+			Entry("override of a resource that is not managed by the operator", RolloutManagerSpec{
+				Overrides: []ResourceOverride{{Kind: "Service", Name: DefaultArgoRolloutsResourceName, Patch: "{}"}},
+			}, "spec.overrides[0].name"),
+			Entry("CRD management on a namespace-scoped RolloutManager", RolloutManagerSpec{
+				NamespaceScoped: true,
+				CRDManagement:   &RolloutsCRDManagementSpec{Enabled: true},
+			}, "spec.crdManagement.enabled"),
+			Entry("malformed dashboard image", RolloutManagerSpec{
+				Dashboard: &RolloutsDashboardSpec{Enabled: true, Image: "quay.io/my/dashboard@sha256"},
+			}, "spec.dashboard.image"),
+		)
+
+		DescribeTable("should accept valid images and versions", func(image, version string) {
+			rm.Spec.Image = image
+			rm.Spec.Version = version
+			_, err := rm.ValidateCreate()
+			Expect(err).ToNot(HaveOccurred())
+		},
+			Entry("image without registry", "argoproj/argo-rollouts", "latest"),
+			Entry("registry with port", "localhost:5000/argo-rollouts", "v1.7.1"),
+			Entry("digest", "quay.io/argoproj/argo-rollouts", "sha256:995450a0a7f7843d68e96d1a7f63422fa29b245c58f7b57dd0cf9cad72b8308f"),
+		)
+	})
+})
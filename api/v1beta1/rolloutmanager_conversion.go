@@ -0,0 +1,396 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+)
+
+var _ conversion.Convertible = &RolloutManager{}
+
+// ConvertTo converts this RolloutManager to the Hub version (v1alpha1).
+func (src *RolloutManager) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha1.RolloutManager)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	// Controller
+	dst.Spec.Image = src.Spec.Controller.Image
+	dst.Spec.Version = src.Spec.Controller.Version
+	dst.Spec.ImagePullPolicy = src.Spec.Controller.ImagePullPolicy
+	dst.Spec.ImagePullSecrets = src.Spec.Controller.ImagePullSecrets
+	dst.Spec.Env = src.Spec.Controller.Env
+	dst.Spec.EnvFrom = src.Spec.Controller.EnvFrom
+	dst.Spec.ExtraCommandArgs = src.Spec.Controller.ExtraCommandArgs
+	dst.Spec.ControllerResources = src.Spec.Controller.Resources
+	dst.Spec.ExtraContainers = src.Spec.Controller.ExtraContainers
+	dst.Spec.ExtraVolumes = src.Spec.Controller.ExtraVolumes
+	dst.Spec.ExtraVolumeMounts = src.Spec.Controller.ExtraVolumeMounts
+	dst.Spec.Replicas = src.Spec.Controller.Replicas
+	dst.Spec.ReadinessDeadlineSeconds = src.Spec.Controller.ReadinessDeadlineSeconds
+	dst.Spec.LeaderElection = (*v1alpha1.RolloutsLeaderElectionSpec)(src.Spec.Controller.LeaderElection)
+	dst.Spec.Strategy = (*v1alpha1.RolloutsDeploymentStrategySpec)(src.Spec.Controller.Strategy)
+	dst.Spec.Logging = (*v1alpha1.RolloutsLoggingSpec)(src.Spec.Controller.Logging)
+	dst.Spec.Tuning = (*v1alpha1.RolloutsTuningSpec)(src.Spec.Controller.Tuning)
+	dst.Spec.Probes = convertProbesToHub(src.Spec.Controller.Probes)
+	dst.Spec.PodDisruptionBudget = (*v1alpha1.RolloutsPodDisruptionBudgetSpec)(src.Spec.Controller.PodDisruptionBudget)
+
+	// Scope
+	dst.Spec.NamespaceScoped = src.Spec.Scope.NamespaceScoped
+
+	// Plugins
+	dst.Spec.Plugins = v1alpha1.Plugins{
+		TrafficManagement: convertPluginsToHub(src.Spec.Plugins.TrafficManagement),
+		Metric:            convertPluginsToHub(src.Spec.Plugins.Metric),
+		Step:              convertPluginsToHub(src.Spec.Plugins.Step),
+		ChecksumPolicy:    v1alpha1.PluginChecksumPolicy(src.Spec.Plugins.ChecksumPolicy),
+		Prefetch:          (*v1alpha1.PluginPrefetchSpec)(src.Spec.Plugins.Prefetch),
+	}
+
+	// Notifications
+	dst.Spec.SkipNotificationSecretDeployment = src.Spec.Notifications.SkipSecretDeployment
+	dst.Spec.NotificationSecretSources = convertNotificationSecretSourcesToHub(src.Spec.Notifications.SecretSources)
+	dst.Spec.Notifications = convertNotificationConfigurationToHub(src.Spec.Notifications.Configuration)
+
+	// Metrics
+	dst.Spec.SkipServiceMonitorDeployment = src.Spec.Metrics.SkipServiceMonitorDeployment
+
+	dst.Spec.NodePlacement = (*v1alpha1.RolloutsNodePlacementSpec)(src.Spec.NodePlacement)
+	dst.Spec.AdditionalMetadata = (*v1alpha1.ResourceMetadata)(src.Spec.AdditionalMetadata)
+	dst.Spec.Dashboard = (*v1alpha1.RolloutsDashboardSpec)(src.Spec.Dashboard)
+	dst.Spec.CRDManagement = (*v1alpha1.RolloutsCRDManagementSpec)(src.Spec.CRDManagement)
+	dst.Spec.Overrides = convertResourceOverridesToHub(src.Spec.Overrides)
+
+	// Status
+	dst.Status = v1alpha1.RolloutManagerStatus{
+		RolloutController:  v1alpha1.RolloutControllerPhase(src.Status.RolloutController),
+		Phase:              v1alpha1.RolloutControllerPhase(src.Status.Phase),
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+	}
+
+	return nil
+}
+
+// ConvertFrom converts from the Hub version (v1alpha1) to this version.
+func (dst *RolloutManager) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha1.RolloutManager)
+
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec = RolloutManagerSpec{
+		Controller: RolloutsControllerSpec{
+			Image:                    src.Spec.Image,
+			Version:                  src.Spec.Version,
+			ImagePullPolicy:          src.Spec.ImagePullPolicy,
+			ImagePullSecrets:         src.Spec.ImagePullSecrets,
+			Env:                      src.Spec.Env,
+			EnvFrom:                  src.Spec.EnvFrom,
+			ExtraCommandArgs:         src.Spec.ExtraCommandArgs,
+			Resources:                src.Spec.ControllerResources,
+			ExtraContainers:          src.Spec.ExtraContainers,
+			ExtraVolumes:             src.Spec.ExtraVolumes,
+			ExtraVolumeMounts:        src.Spec.ExtraVolumeMounts,
+			Replicas:                 src.Spec.Replicas,
+			ReadinessDeadlineSeconds: src.Spec.ReadinessDeadlineSeconds,
+			LeaderElection:           (*RolloutsLeaderElectionSpec)(src.Spec.LeaderElection),
+			Strategy:                 (*RolloutsDeploymentStrategySpec)(src.Spec.Strategy),
+			Logging:                  (*RolloutsLoggingSpec)(src.Spec.Logging),
+			Tuning:                   (*RolloutsTuningSpec)(src.Spec.Tuning),
+			Probes:                   convertProbesFromHub(src.Spec.Probes),
+			PodDisruptionBudget:      (*RolloutsPodDisruptionBudgetSpec)(src.Spec.PodDisruptionBudget),
+		},
+		Scope: RolloutsScopeSpec{
+			NamespaceScoped: src.Spec.NamespaceScoped,
+		},
+		Plugins: Plugins{
+			TrafficManagement: convertPluginsFromHub(src.Spec.Plugins.TrafficManagement),
+			Metric:            convertPluginsFromHub(src.Spec.Plugins.Metric),
+			Step:              convertPluginsFromHub(src.Spec.Plugins.Step),
+			ChecksumPolicy:    PluginChecksumPolicy(src.Spec.Plugins.ChecksumPolicy),
+			Prefetch:          (*PluginPrefetchSpec)(src.Spec.Plugins.Prefetch),
+		},
+		Notifications: RolloutsNotificationsSpec{
+			SkipSecretDeployment: src.Spec.SkipNotificationSecretDeployment,
+			SecretSources:        convertNotificationSecretSourcesFromHub(src.Spec.NotificationSecretSources),
+			Configuration:        convertNotificationConfigurationFromHub(src.Spec.Notifications),
+		},
+		Metrics: RolloutsMetricsSpec{
+			SkipServiceMonitorDeployment: src.Spec.SkipServiceMonitorDeployment,
+		},
+		NodePlacement:      (*RolloutsNodePlacementSpec)(src.Spec.NodePlacement),
+		AdditionalMetadata: (*ResourceMetadata)(src.Spec.AdditionalMetadata),
+		Dashboard:          (*RolloutsDashboardSpec)(src.Spec.Dashboard),
+		CRDManagement:      (*RolloutsCRDManagementSpec)(src.Spec.CRDManagement),
+		Overrides:          convertResourceOverridesFromHub(src.Spec.Overrides),
+	}
+
+	dst.Status = RolloutManagerStatus{
+		RolloutController:  RolloutControllerPhase(src.Status.RolloutController),
+		Phase:              RolloutControllerPhase(src.Status.Phase),
+		Conditions:         src.Status.Conditions,
+		ObservedGeneration: src.Status.ObservedGeneration,
+	}
+
+	return nil
+}
+
+func convertPluginsToHub(plugins []Plugin) []v1alpha1.Plugin {
+	if plugins == nil {
+		return nil
+	}
+	res := make([]v1alpha1.Plugin, 0, len(plugins))
+	for _, plugin := range plugins {
+		res = append(res, v1alpha1.Plugin{
+			Name:     plugin.Name,
+			Location: plugin.Location,
+			SHA256:   plugin.SHA256,
+			Args:     plugin.Args,
+			Source:   convertPluginSourceToHub(plugin.Source),
+		})
+	}
+	return res
+}
+
+func convertPluginsFromHub(plugins []v1alpha1.Plugin) []Plugin {
+	if plugins == nil {
+		return nil
+	}
+	res := make([]Plugin, 0, len(plugins))
+	for _, plugin := range plugins {
+		res = append(res, Plugin{
+			Name:     plugin.Name,
+			Location: plugin.Location,
+			SHA256:   plugin.SHA256,
+			Args:     plugin.Args,
+			Source:   convertPluginSourceFromHub(plugin.Source),
+		})
+	}
+	return res
+}
+
+func convertPluginSourceToHub(src *PluginSource) *v1alpha1.PluginSource {
+	if src == nil {
+		return nil
+	}
+	return &v1alpha1.PluginSource{
+		Image:                 (*v1alpha1.PluginImageSource)(src.Image),
+		PersistentVolumeClaim: (*v1alpha1.PluginVolumeSource)(src.PersistentVolumeClaim),
+		ConfigMap:             (*v1alpha1.PluginVolumeSource)(src.ConfigMap),
+		Secret:                (*v1alpha1.PluginVolumeSource)(src.Secret),
+	}
+}
+
+func convertPluginSourceFromHub(src *v1alpha1.PluginSource) *PluginSource {
+	if src == nil {
+		return nil
+	}
+	return &PluginSource{
+		Image:                 (*PluginImageSource)(src.Image),
+		PersistentVolumeClaim: (*PluginVolumeSource)(src.PersistentVolumeClaim),
+		ConfigMap:             (*PluginVolumeSource)(src.ConfigMap),
+		Secret:                (*PluginVolumeSource)(src.Secret),
+	}
+}
+
+func convertNotificationConfigurationToHub(src *RolloutsNotificationConfigurationSpec) *v1alpha1.RolloutsNotificationConfigurationSpec {
+	if src == nil {
+		return nil
+	}
+
+	dst := &v1alpha1.RolloutsNotificationConfigurationSpec{
+		Services:        src.Services,
+		DefaultTriggers: src.DefaultTriggers,
+	}
+
+	if src.Templates != nil {
+		dst.Templates = make([]v1alpha1.NotificationTemplate, 0, len(src.Templates))
+		for _, template := range src.Templates {
+			dst.Templates = append(dst.Templates, v1alpha1.NotificationTemplate(template))
+		}
+	}
+
+	if src.Triggers != nil {
+		dst.Triggers = make([]v1alpha1.NotificationTrigger, 0, len(src.Triggers))
+		for _, trigger := range src.Triggers {
+			var conditions []v1alpha1.NotificationTriggerCondition
+			if trigger.Conditions != nil {
+				conditions = make([]v1alpha1.NotificationTriggerCondition, 0, len(trigger.Conditions))
+				for _, condition := range trigger.Conditions {
+					conditions = append(conditions, v1alpha1.NotificationTriggerCondition(condition))
+				}
+			}
+			dst.Triggers = append(dst.Triggers, v1alpha1.NotificationTrigger{Name: trigger.Name, Conditions: conditions})
+		}
+	}
+
+	if src.DefaultSubscriptions != nil {
+		dst.DefaultSubscriptions = make([]v1alpha1.NotificationSubscription, 0, len(src.DefaultSubscriptions))
+		for _, subscription := range src.DefaultSubscriptions {
+			dst.DefaultSubscriptions = append(dst.DefaultSubscriptions, v1alpha1.NotificationSubscription(subscription))
+		}
+	}
+
+	if src.TemplateSets != nil {
+		dst.TemplateSets = make([]v1alpha1.NotificationTemplateSetReference, 0, len(src.TemplateSets))
+		for _, templateSet := range src.TemplateSets {
+			dst.TemplateSets = append(dst.TemplateSets, v1alpha1.NotificationTemplateSetReference(templateSet))
+		}
+	}
+
+	return dst
+}
+
+func convertNotificationConfigurationFromHub(src *v1alpha1.RolloutsNotificationConfigurationSpec) *RolloutsNotificationConfigurationSpec {
+	if src == nil {
+		return nil
+	}
+
+	dst := &RolloutsNotificationConfigurationSpec{
+		Services:        src.Services,
+		DefaultTriggers: src.DefaultTriggers,
+	}
+
+	if src.Templates != nil {
+		dst.Templates = make([]NotificationTemplate, 0, len(src.Templates))
+		for _, template := range src.Templates {
+			dst.Templates = append(dst.Templates, NotificationTemplate(template))
+		}
+	}
+
+	if src.Triggers != nil {
+		dst.Triggers = make([]NotificationTrigger, 0, len(src.Triggers))
+		for _, trigger := range src.Triggers {
+			var conditions []NotificationTriggerCondition
+			if trigger.Conditions != nil {
+				conditions = make([]NotificationTriggerCondition, 0, len(trigger.Conditions))
+				for _, condition := range trigger.Conditions {
+					conditions = append(conditions, NotificationTriggerCondition(condition))
+				}
+			}
+			dst.Triggers = append(dst.Triggers, NotificationTrigger{Name: trigger.Name, Conditions: conditions})
+		}
+	}
+
+	if src.DefaultSubscriptions != nil {
+		dst.DefaultSubscriptions = make([]NotificationSubscription, 0, len(src.DefaultSubscriptions))
+		for _, subscription := range src.DefaultSubscriptions {
+			dst.DefaultSubscriptions = append(dst.DefaultSubscriptions, NotificationSubscription(subscription))
+		}
+	}
+
+	if src.TemplateSets != nil {
+		dst.TemplateSets = make([]NotificationTemplateSetReference, 0, len(src.TemplateSets))
+		for _, templateSet := range src.TemplateSets {
+			dst.TemplateSets = append(dst.TemplateSets, NotificationTemplateSetReference(templateSet))
+		}
+	}
+
+	return dst
+}
+
+func convertNotificationSecretSourcesToHub(sources []NotificationSecretSource) []v1alpha1.NotificationSecretSource {
+	if sources == nil {
+		return nil
+	}
+	res := make([]v1alpha1.NotificationSecretSource, 0, len(sources))
+	for _, source := range sources {
+		var keys []v1alpha1.NotificationSecretKeyMapping
+		if source.Keys != nil {
+			keys = make([]v1alpha1.NotificationSecretKeyMapping, 0, len(source.Keys))
+			for _, key := range source.Keys {
+				keys = append(keys, v1alpha1.NotificationSecretKeyMapping(key))
+			}
+		}
+		res = append(res, v1alpha1.NotificationSecretSource{Name: source.Name, Keys: keys})
+	}
+	return res
+}
+
+func convertNotificationSecretSourcesFromHub(sources []v1alpha1.NotificationSecretSource) []NotificationSecretSource {
+	if sources == nil {
+		return nil
+	}
+	res := make([]NotificationSecretSource, 0, len(sources))
+	for _, source := range sources {
+		var keys []NotificationSecretKeyMapping
+		if source.Keys != nil {
+			keys = make([]NotificationSecretKeyMapping, 0, len(source.Keys))
+			for _, key := range source.Keys {
+				keys = append(keys, NotificationSecretKeyMapping(key))
+			}
+		}
+		res = append(res, NotificationSecretSource{Name: source.Name, Keys: keys})
+	}
+	return res
+}
+
+func convertResourceOverridesToHub(overrides []ResourceOverride) []v1alpha1.ResourceOverride {
+	if overrides == nil {
+		return nil
+	}
+	res := make([]v1alpha1.ResourceOverride, 0, len(overrides))
+	for _, override := range overrides {
+		res = append(res, v1alpha1.ResourceOverride{
+			Kind:  override.Kind,
+			Name:  override.Name,
+			Type:  v1alpha1.ResourceOverridePatchType(override.Type),
+			Patch: override.Patch,
+		})
+	}
+	return res
+}
+
+func convertResourceOverridesFromHub(overrides []v1alpha1.ResourceOverride) []ResourceOverride {
+	if overrides == nil {
+		return nil
+	}
+	res := make([]ResourceOverride, 0, len(overrides))
+	for _, override := range overrides {
+		res = append(res, ResourceOverride{
+			Kind:  override.Kind,
+			Name:  override.Name,
+			Type:  ResourceOverridePatchType(override.Type),
+			Patch: override.Patch,
+		})
+	}
+	return res
+}
+
+func convertProbesToHub(src *RolloutsProbesSpec) *v1alpha1.RolloutsProbesSpec {
+	if src == nil {
+		return nil
+	}
+	return &v1alpha1.RolloutsProbesSpec{
+		Liveness:  (*v1alpha1.RolloutsProbeSpec)(src.Liveness),
+		Readiness: (*v1alpha1.RolloutsProbeSpec)(src.Readiness),
+		Startup:   (*v1alpha1.RolloutsProbeSpec)(src.Startup),
+	}
+}
+
+func convertProbesFromHub(src *v1alpha1.RolloutsProbesSpec) *RolloutsProbesSpec {
+	if src == nil {
+		return nil
+	}
+	return &RolloutsProbesSpec{
+		Liveness:  (*RolloutsProbeSpec)(src.Liveness),
+		Readiness: (*RolloutsProbeSpec)(src.Readiness),
+		Startup:   (*RolloutsProbeSpec)(src.Startup),
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	fuzz "github.com/google/gofuzz"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+)
+
+var _ = Describe("RolloutManager conversion tests", func() {
+
+	// fuzzer fills in every field of the RolloutManager types, so that a field which is not converted fails the round-trip tests.
+	fuzzer := fuzz.New().NilChance(0.2).NumElements(0, 3).Funcs(
+		func(q *resource.Quantity, c fuzz.Continue) {
+			*q = *resource.NewQuantity(c.Int63n(1000), resource.DecimalSI)
+		},
+		func(t *metav1.Time, c fuzz.Continue) {
+			*t = metav1.Unix(c.Int63n(1_000_000_000), 0)
+		},
+	)
+
+	It("should convert a v1beta1 RolloutManager to v1alpha1 and back without losing data", func() {
+		for i := 0; i < 100; i++ {
+			original := &RolloutManager{}
+			fuzzer.Fuzz(original)
+			original.TypeMeta = metav1.TypeMeta{}
+
+			hub := &v1alpha1.RolloutManager{}
+			Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+
+			roundTripped := &RolloutManager{}
+			Expect(roundTripped.ConvertFrom(hub)).To(Succeed())
+
+			Expect(roundTripped).To(Equal(original))
+		}
+	})
+
+	It("should convert a v1alpha1 RolloutManager to v1beta1 and back without losing data", func() {
+		for i := 0; i < 100; i++ {
+			original := &v1alpha1.RolloutManager{}
+			fuzzer.Fuzz(original)
+			original.TypeMeta = metav1.TypeMeta{}
+
+			spoke := &RolloutManager{}
+			Expect(spoke.ConvertFrom(original.DeepCopy())).To(Succeed())
+
+			roundTripped := &v1alpha1.RolloutManager{}
+			Expect(spoke.ConvertTo(roundTripped)).To(Succeed())
+
+			Expect(roundTripped).To(Equal(original))
+		}
+	})
+
+	It("should convert the metrics group of a v1beta1 RolloutManager to v1alpha1 and back", func() {
+		for i := 0; i < 10; i++ {
+			original := &RolloutManager{}
+			fuzzer.Fuzz(&original.Spec.Metrics)
+
+			hub := &v1alpha1.RolloutManager{}
+			Expect(original.DeepCopy().ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.SkipServiceMonitorDeployment).To(Equal(original.Spec.Metrics.SkipServiceMonitorDeployment))
+
+			roundTripped := &RolloutManager{}
+			Expect(roundTripped.ConvertFrom(hub)).To(Succeed())
+
+			Expect(roundTripped.Spec.Metrics).To(Equal(original.Spec.Metrics))
+		}
+	})
+
+	It("should move the flat v1alpha1 fields into the v1beta1 sub-structs", func() {
+		replicas := int32(2)
+		maxUnavailable := intstr.FromInt(1)
+
+		hub := &v1alpha1.RolloutManager{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-rollouts-manager",
+				Namespace: "test-ns",
+			},
+			Spec: v1alpha1.RolloutManagerSpec{
+				Env:              []corev1.EnvVar{{Name: "foo", Value: "bar"}},
+				ExtraCommandArgs: []string{"--loglevel", "debug"},
+				Image:            "quay.io/my/rollouts",
+				Version:          "v1.7.1",
+				NamespaceScoped:  true,
+				ControllerResources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+				},
+				SkipNotificationSecretDeployment: true,
+				SkipServiceMonitorDeployment:     true,
+				Plugins: v1alpha1.Plugins{
+					Metric: []v1alpha1.Plugin{{Name: "argoproj-labs/sample-prometheus", Location: "https://example.com/prometheus", SHA256: "abc"}},
+				},
+				Replicas:            &replicas,
+				PodDisruptionBudget: &v1alpha1.RolloutsPodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable},
+			},
+			Status: v1alpha1.RolloutManagerStatus{
+				Phase: v1alpha1.PhaseAvailable,
+			},
+		}
+
+		rm := &RolloutManager{}
+		Expect(rm.ConvertFrom(hub)).To(Succeed())
+
+		Expect(rm.Name).To(Equal(hub.Name))
+		Expect(rm.Namespace).To(Equal(hub.Namespace))
+		Expect(rm.Spec.Controller.Image).To(Equal("quay.io/my/rollouts"))
+		Expect(rm.Spec.Controller.Version).To(Equal("v1.7.1"))
+		Expect(rm.Spec.Controller.Env).To(Equal(hub.Spec.Env))
+		Expect(rm.Spec.Controller.ExtraCommandArgs).To(Equal([]string{"--loglevel", "debug"}))
+		Expect(rm.Spec.Controller.Resources).To(Equal(hub.Spec.ControllerResources))
+		Expect(*rm.Spec.Controller.Replicas).To(Equal(int32(2)))
+		Expect(rm.Spec.Controller.PodDisruptionBudget.MaxUnavailable).To(Equal(&maxUnavailable))
+		Expect(rm.Spec.Scope.NamespaceScoped).To(BeTrue())
+		Expect(rm.Spec.Notifications.SkipSecretDeployment).To(BeTrue())
+		Expect(rm.Spec.Metrics.SkipServiceMonitorDeployment).To(BeTrue())
+		Expect(rm.Spec.Plugins.Metric).To(Equal([]Plugin{{Name: "argoproj-labs/sample-prometheus", Location: "https://example.com/prometheus", SHA256: "abc"}}))
+		Expect(rm.Status.Phase).To(Equal(PhaseAvailable))
+	})
+})
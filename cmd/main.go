@@ -25,16 +25,20 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	rolloutsmanagerv1beta1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1beta1"
 
 	controllers "github.com/argoproj-labs/argo-rollouts-manager/controllers"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
@@ -42,6 +46,9 @@ import (
 	//+kubebuilder:scaffold:imports
 )
 
+// enableWebhooksEnvName is an environment variable that can be set to 'true' to serve the RolloutManager admission webhooks.
+const enableWebhooksEnvName = "ENABLE_WEBHOOKS"
+
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
@@ -51,6 +58,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(rolloutsmanagerv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(rolloutsmanagerv1beta1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -79,6 +87,12 @@ func main() {
 		WebhookServer: webhook.NewServer(webhook.Options{
 			Port: 9443,
 		}),
+		// Only the Pods of the Rollouts controllers are watched, so the other Pods of the cluster are not cached.
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.Pod{}: {Label: controllers.RolloutsPodLabelSelector()},
+			},
+		},
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "rolloutsmanager.argoproj.io",
@@ -128,10 +142,20 @@ func main() {
 		Scheme:                                mgr.GetScheme(),
 		OpenShiftRoutePluginLocation:          openShiftRoutePluginLocation,
 		NamespaceScopedArgoRolloutsController: isNamespaceScoped,
+		Recorder:                              mgr.GetEventRecorderFor("argo-rollouts-manager"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RolloutManager")
 		os.Exit(1)
 	}
+
+	// The webhooks require a serving certificate, so they are only registered when explicitly enabled.
+	// This also registers the conversion webhook between the v1alpha1 and v1beta1 versions of RolloutManager.
+	if strings.ToLower(os.Getenv(enableWebhooksEnvName)) == "true" {
+		if err = (&rolloutsmanagerv1alpha1.RolloutManager{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "RolloutManager")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,10 @@
+// Package crd embeds the Argo Rollouts CRDs that are copied from the Argo Rollouts repository by 'hack/upgrade-rollouts-script',
+// so that the operator can install them. They match the default version of the Argo Rollouts controller.
+package crd
+
+import "embed"
+
+// RolloutsCRDs holds the Argo Rollouts CRDs, under 'bases/'.
+//
+//go:embed bases/*-crd.yaml
+var RolloutsCRDs embed.FS
@@ -18,17 +18,21 @@ package rollouts
 
 import (
 	"context"
+	"time"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,6 +55,9 @@ type RolloutManagerReconciler struct {
 	// NamespaceScopedArgoRolloutsController is used to configure scope of Argo Rollouts controller
 	// If value is true then deploy namespace-scoped Argo Rollouts controller else cluster-scoped
 	NamespaceScopedArgoRolloutsController bool
+
+	// Recorder records the events on the RolloutManagers, such as the creation of the resources they manage
+	Recorder record.EventRecorder
 }
 
 var log = logr.Log.WithName("rollouts-controller")
@@ -69,6 +76,7 @@ const (
 //+kubebuilder:rbac:groups=apps,resources=deployments/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=deployments,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=pods/eviction,verbs=create
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=podtemplates,verbs=get;list;watch
 //+kubebuilder:rbac:groups="appmesh.k8s.aws",resources=virtualnodes;virtualrouters,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups="appmesh.k8s.aws",resources=virtualservices,verbs=get;list;watch
@@ -89,7 +97,7 @@ const (
 //+kubebuilder:rbac:groups="apisix.apache.org",resources=apisixroutes,verbs=watch;get;update
 //+kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=create;watch;get;update;patch;list
 //+kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=create;watch;get;update;patch;list
-//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;list;watch;create;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -107,7 +115,7 @@ func (r *RolloutManagerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			reqLogger.Info("Skipping reconciliation of RolloutManager as request Namespace no longer exists")
 
 			// Ensure that any cluster-scoped resources are removed, since the RolloutManager was deleted.
-			if err := r.removeClusterScopedResourcesIfApplicable(ctx); err != nil {
+			if err := r.removeClusterScopedResourcesIfApplicable(ctx, nil); err != nil {
 				reqLogger.Error(err, "unable to remove cluster scoped resources for non-existing Namespace")
 				return ctrl.Result{}, err
 			}
@@ -129,11 +137,17 @@ func (r *RolloutManagerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 			// The RolloutManager CR has likely been deleted: owned objects are automatically garbage collected.
 			// However, cluster-scoped resources cannot be owned by a namespace-scoped RolloutManager CR, so we must delete them manually.
-			if err := r.removeClusterScopedResourcesIfApplicable(ctx); err != nil {
+			// The events are recorded on the deleted RolloutManager, and can still be listed in its namespace.
+			deletedRolloutManager := &rolloutsmanagerv1alpha1.RolloutManager{ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace}}
+			if err := r.removeClusterScopedResourcesIfApplicable(ctx, deletedRolloutManager); err != nil {
 				reqLogger.Error(err, "unable to remove cluster scoped resources for non-existing RolloutManager")
 				return ctrl.Result{}, err
 			}
 
+			if err := r.updateRolloutManagersMetric(ctx); err != nil {
+				reqLogger.Error(err, "unable to update the RolloutManagers metric")
+			}
+
 			// Return and don't requeue
 			return reconcile.Result{}, nil
 		}
@@ -149,11 +163,20 @@ func (r *RolloutManagerReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		return reconcile.Result{}, err
 	}
 
+	if err := r.updateRolloutManagersMetric(ctx); err != nil {
+		log.Error(err, "unable to update the RolloutManagers metric")
+	}
+
 	// Next return the reconcileErr if applicable
 	if reconcileErr != nil {
 		return reconcile.Result{}, reconcileErr
 	}
 
+	// The status of the Rollouts controller is checked again until it is available, even if none of the watched resources change.
+	if requeueAfter := getRequeueDelay(*rolloutManager, time.Now()); requeueAfter > 0 {
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -172,15 +195,28 @@ func (r *RolloutManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Watch for changes to ConfigMap sub-resources owned by RolloutManager.
 	bld.Owns(&corev1.ConfigMap{})
 
+	// Watch for changes to ConfigMaps that are referenced as notification template sets, or from the environment of the Rollouts controller, by RolloutManagers.
+	bld.Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.enqueueRolloutManagersReferencingConfigMap))
+
 	// Watch for changes to Secret sub-resources owned by RolloutManager.
 	bld.Owns(&corev1.Secret{})
 
+	// Watch for changes to Secrets that are referenced as notification Secret sources, or from the environment of the Rollouts controller, by RolloutManagers.
+	bld.Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.enqueueRolloutManagersReferencingSecret))
+
 	// Watch for changes to Service sub-resources owned by RolloutManager.
 	bld.Owns(&corev1.Service{})
 
 	// Watch for changes to Deployment sub-resources owned by RolloutManager.
 	bld.Owns(&appsv1.Deployment{})
 
+	// Watch for changes to Rollouts controller Pods, so that failures of their containers and plugin init containers are reported on the RolloutManager.
+	// Other Pods are ignored, and are not cached either: see RolloutsPodLabelSelector.
+	bld.Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.enqueueRolloutManagersForRolloutsPod), builder.WithPredicates(predicate.NewPredicateFuncs(isRolloutsPod)))
+
+	// Watch for changes to PodDisruptionBudget sub-resources owned by RolloutManager.
+	bld.Owns(&policyv1.PodDisruptionBudget{})
+
 	// Watch for changes to Role sub-resources owned by RolloutManager.
 	bld.Owns(&rbacv1.Role{})
 
@@ -188,13 +224,18 @@ func (r *RolloutManagerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	bld.Owns(&rbacv1.RoleBinding{})
 
 	// We can't use Owns for ClusterRole/ClusterRoleBinding, because namespace-scoped resources like RolloutManager cannot own cluster-scoped resources like ClusterRole/ClusterRoleBinding.
-	// Instead, we watch all ClusterRoles/ClusterRoleBindings with the name DefaultArgoRolloutsResourceName, and when they change, we inform all RolloutManagers
+	// Instead, we watch all ClusterRoles/ClusterRoleBindings with the name DefaultArgoRolloutsResourceName (or DefaultArgoRolloutsDashboardResourceName), and when they change, we inform all RolloutManagers
 	bld.Watches(&rbacv1.ClusterRole{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRolloutManagers), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
-		return object.GetName() == DefaultArgoRolloutsResourceName
+		return object.GetName() == DefaultArgoRolloutsResourceName || object.GetName() == DefaultArgoRolloutsDashboardResourceName
 	})))
 
 	bld.Watches(&rbacv1.ClusterRoleBinding{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRolloutManagers), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
-		return object.GetName() == DefaultArgoRolloutsResourceName
+		return object.GetName() == DefaultArgoRolloutsResourceName || object.GetName() == DefaultArgoRolloutsDashboardResourceName
+	})))
+
+	// Watch the Argo Rollouts CRDs, so that changes to them are reverted, and reported, on the RolloutManagers which manage them.
+	bld.Watches(&crdv1.CustomResourceDefinition{}, handler.EnqueueRequestsFromMapFunc(r.enqueueAllRolloutManagers), builder.WithPredicates(predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return isRolloutsCRDName(object.GetName())
 	})))
 
 	if crdExists, err := r.doesCRDExist(mgr.GetConfig(), serviceMonitorsCRDName); err != nil {
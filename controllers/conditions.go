@@ -0,0 +1,123 @@
+package rollouts
+
+import (
+	"fmt"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// reconcileStepConditionTypes are the condition types of the steps of reconcileRolloutsManager, in the order they are reconciled.
+var reconcileStepConditionTypes = []string{
+	rolloutsmanagerv1alpha1.RolloutManagerConfigurationValidConditionType,
+	rolloutsmanagerv1alpha1.RolloutManagerCRDsReconciledConditionType,
+	rolloutsmanagerv1alpha1.RolloutManagerRBACReconciledConditionType,
+	rolloutsmanagerv1alpha1.RolloutManagerNotificationsReconciledConditionType,
+	rolloutsmanagerv1alpha1.RolloutManagerConfigMapReconciledConditionType,
+	rolloutsmanagerv1alpha1.RolloutManagerDeploymentReconciledConditionType,
+	rolloutsmanagerv1alpha1.RolloutManagerPodDisruptionBudgetReconciledConditionType,
+	rolloutsmanagerv1alpha1.RolloutManagerMetricsReconciledConditionType,
+	rolloutsmanagerv1alpha1.RolloutManagerDashboardReconciledConditionType,
+}
+
+// createStepConditions returns the conditions of the reconcile steps, once reconcileRolloutsManager has returned from 'step' with the 'reconciled' condition:
+// - the steps before 'step' succeeded
+// - 'step' has the status, reason and message of the 'reconciled' condition
+// - the steps after 'step' were not reconciled.
+// All the steps succeeded when 'step' is empty.
+func createStepConditions(step string, reconciled metav1.Condition) []metav1.Condition {
+
+	res := make([]metav1.Condition, 0, len(reconcileStepConditionTypes))
+
+	reached := false
+	for _, conditionType := range reconcileStepConditionTypes {
+
+		switch {
+		case conditionType == step:
+			reached = true
+			res = append(res, metav1.Condition{
+				Type:    conditionType,
+				Status:  reconciled.Status,
+				Reason:  reconciled.Reason,
+				Message: reconciled.Message,
+			})
+
+		case reached:
+			res = append(res, metav1.Condition{
+				Type:    conditionType,
+				Status:  metav1.ConditionUnknown,
+				Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonNotReconciled,
+				Message: fmt.Sprintf("not reconciled, as %s is not true", step),
+			})
+
+		default:
+			res = append(res, metav1.Condition{
+				Type:   conditionType,
+				Status: metav1.ConditionTrue,
+				Reason: rolloutsmanagerv1alpha1.RolloutManagerReasonSuccess,
+			})
+		}
+	}
+
+	return res
+}
+
+// createReadyCondition returns the Ready condition of the RolloutManager, from its Reconciled condition and its phase:
+// it is true once the RolloutManager is reconciled and the Argo Rollouts controller is available.
+func createReadyCondition(reconciled metav1.Condition, phase rolloutsmanagerv1alpha1.RolloutControllerPhase) metav1.Condition {
+
+	if reconciled.Status != metav1.ConditionTrue {
+		return metav1.Condition{
+			Type:    rolloutsmanagerv1alpha1.RolloutManagerReadyConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reconciled.Reason,
+			Message: reconciled.Message,
+		}
+	}
+
+	if phase == rolloutsmanagerv1alpha1.PhaseAvailable {
+		return metav1.Condition{
+			Type:    rolloutsmanagerv1alpha1.RolloutManagerReadyConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonAvailable,
+			Message: "the Argo Rollouts controller is available",
+		}
+	}
+
+	return metav1.Condition{
+		Type:    rolloutsmanagerv1alpha1.RolloutManagerReadyConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonNotAvailable,
+		Message: "waiting for the Argo Rollouts controller to be available",
+	}
+}
+
+// createProgressingCondition returns the Progressing condition of the RolloutManager, from its Reconciled condition and its phase:
+// it is true while the RolloutManager is reconciled, but the Argo Rollouts controller is not yet available.
+func createProgressingCondition(reconciled metav1.Condition, phase rolloutsmanagerv1alpha1.RolloutControllerPhase) metav1.Condition {
+
+	if reconciled.Status != metav1.ConditionTrue {
+		return metav1.Condition{
+			Type:    rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reconciled.Reason,
+			Message: reconciled.Message,
+		}
+	}
+
+	if phase == rolloutsmanagerv1alpha1.PhaseAvailable {
+		return metav1.Condition{
+			Type:    rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonAvailable,
+			Message: "the Argo Rollouts controller is available",
+		}
+	}
+
+	return metav1.Condition{
+		Type:    rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonNotAvailable,
+		Message: "waiting for the Argo Rollouts controller to be available",
+	}
+}
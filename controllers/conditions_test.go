@@ -0,0 +1,103 @@
+package rollouts
+
+import (
+	"context"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("createStepConditions tests", func() {
+
+	It("should report all the steps as successful when all of them were reconciled", func() {
+		conditions := createStepConditions("", createCondition(""))
+		Expect(conditions).To(HaveLen(len(reconcileStepConditionTypes)))
+		for _, condition := range conditions {
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonSuccess))
+		}
+	})
+
+	It("should report the failed step, and the following steps as not reconciled", func() {
+		conditions := createStepConditions(v1alpha1.RolloutManagerDeploymentReconciledConditionType, createCondition("failed to create the Deployment"))
+
+		Expect(meta.IsStatusConditionTrue(conditions, v1alpha1.RolloutManagerConfigurationValidConditionType)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(conditions, v1alpha1.RolloutManagerConfigMapReconciledConditionType)).To(BeTrue())
+
+		deployment := meta.FindStatusCondition(conditions, v1alpha1.RolloutManagerDeploymentReconciledConditionType)
+		Expect(deployment.Status).To(Equal(metav1.ConditionFalse))
+		Expect(deployment.Reason).To(Equal(v1alpha1.RolloutManagerReasonErrorOccurred))
+		Expect(deployment.Message).To(Equal("failed to create the Deployment"))
+
+		dashboard := meta.FindStatusCondition(conditions, v1alpha1.RolloutManagerDashboardReconciledConditionType)
+		Expect(dashboard.Status).To(Equal(metav1.ConditionUnknown))
+		Expect(dashboard.Reason).To(Equal(v1alpha1.RolloutManagerReasonNotReconciled))
+	})
+})
+
+var _ = Describe("RolloutManager status conditions tests", func() {
+	var (
+		ctx context.Context
+		a   v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager()
+		a.Generation = 1
+		r = makeTestReconciler(&a)
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+		Expect(os.Setenv(ClusterScopedArgoRolloutsNamespaces, a.Namespace)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)).To(Succeed())
+	})
+
+	It("should set a condition per reconcile step, the Ready and Progressing conditions, and the observed generation", func() {
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&a)}
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&a), &a)).To(Succeed())
+		Expect(a.Status.ObservedGeneration).To(Equal(a.Generation))
+		for _, conditionType := range reconcileStepConditionTypes {
+			Expect(meta.IsStatusConditionTrue(a.Status.Conditions, conditionType)).To(BeTrue(), conditionType)
+		}
+
+		By("verifying that the RolloutManager is progressing, until the Deployment is available")
+		Expect(meta.IsStatusConditionFalse(a.Status.Conditions, v1alpha1.RolloutManagerReadyConditionType)).To(BeTrue())
+		Expect(meta.IsStatusConditionTrue(a.Status.Conditions, v1alpha1.RolloutManagerProgressingConditionType)).To(BeTrue())
+
+		By("making the RolloutManager invalid")
+		a.Spec.Replicas = int32Ptr(2)
+		a.Spec.LeaderElection = &v1alpha1.RolloutsLeaderElectionSpec{Enabled: boolPtr(false)}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&a), &a)).To(Succeed())
+
+		configurationValid := meta.FindStatusCondition(a.Status.Conditions, v1alpha1.RolloutManagerConfigurationValidConditionType)
+		Expect(configurationValid.Status).To(Equal(metav1.ConditionFalse))
+		Expect(configurationValid.Reason).To(Equal(v1alpha1.RolloutManagerReasonInvalidHAConfiguration))
+		Expect(configurationValid.Message).To(Equal(UnsupportedLeaderElectionDisabled))
+
+		deployment := meta.FindStatusCondition(a.Status.Conditions, v1alpha1.RolloutManagerDeploymentReconciledConditionType)
+		Expect(deployment.Status).To(Equal(metav1.ConditionUnknown))
+
+		ready := meta.FindStatusCondition(a.Status.Conditions, v1alpha1.RolloutManagerReadyConditionType)
+		Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+		Expect(ready.Reason).To(Equal(v1alpha1.RolloutManagerReasonInvalidHAConfiguration))
+		Expect(meta.IsStatusConditionFalse(a.Status.Conditions, v1alpha1.RolloutManagerProgressingConditionType)).To(BeTrue())
+	})
+})
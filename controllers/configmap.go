@@ -3,6 +3,7 @@ package rollouts
 import (
 	"context"
 	"sort"
+	"strings"
 
 	"fmt"
 	"reflect"
@@ -22,6 +23,9 @@ import (
 // From https://argo-rollouts.readthedocs.io/en/stable/features/traffic-management/plugins/
 const TrafficRouterPluginConfigMapKey = "trafficRouterPlugins"
 const MetricPluginConfigMapKey = "metricPlugins"
+const StepPluginConfigMapKey = "stepPlugins"
+
+const UnsupportedPluginConfiguration = "invalid plugin configuration"
 
 // Reconcile the Rollouts Default Config Map.
 func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
@@ -42,10 +46,11 @@ func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr ro
 
 	setRolloutsLabelsAndAnnotationsToObject(&desiredConfigMap.ObjectMeta, cr)
 
+	// The OpenShift Route plugin is downloaded by the init container, like the plugins of the RolloutManager, when plugin prefetch is enabled
 	trafficRouterPluginsMap := map[string]pluginItem{
 		OpenShiftRolloutPluginName: {
 			Name:     OpenShiftRolloutPluginName,
-			Location: r.OpenShiftRoutePluginLocation,
+			Location: getPluginLocation(cr, getOpenShiftRoutePlugin(r.OpenShiftRoutePluginLocation)),
 		},
 	}
 
@@ -59,23 +64,14 @@ func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr ro
 		if _, exists := trafficRouterPluginsMap[plugin.Name]; !exists {
 			trafficRouterPluginsMap[plugin.Name] = pluginItem{
 				Name:     plugin.Name,
-				Location: plugin.Location,
+				Location: getPluginLocation(cr, plugin),
+				Sha256:   plugin.SHA256,
+				Args:     plugin.Args,
 			}
 		}
 	}
 
-	// Sort trafficRouterPluginsMap keys for deterministic ordering
-	trafficRouterPluginKeys := make([]string, 0, len(trafficRouterPluginsMap))
-	for key := range trafficRouterPluginsMap {
-		trafficRouterPluginKeys = append(trafficRouterPluginKeys, key)
-	}
-	sort.Strings(trafficRouterPluginKeys)
-
-	// Convert trafficRouterPluginsMap to sorted slice
-	trafficRouterPlugins := make([]pluginItem, 0, len(trafficRouterPluginsMap))
-	for _, key := range trafficRouterPluginKeys {
-		trafficRouterPlugins = append(trafficRouterPlugins, trafficRouterPluginsMap[key])
-	}
+	trafficRouterPlugins := sortPluginItems(trafficRouterPluginsMap)
 
 	// Append metric plugins specified in RolloutManager CR
 	metricPluginsMap := map[string]pluginItem{}
@@ -84,24 +80,28 @@ func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr ro
 		if _, exists := metricPluginsMap[plugin.Name]; !exists {
 			metricPluginsMap[plugin.Name] = pluginItem{
 				Name:     plugin.Name,
-				Location: plugin.Location,
+				Location: getPluginLocation(cr, plugin),
 				Sha256:   plugin.SHA256,
+				Args:     plugin.Args,
 			}
 		}
 	}
+	metricPlugins := sortPluginItems(metricPluginsMap)
 
-	// Sort metricPluginsMap keys for deterministic ordering
-	metricPluginKeys := make([]string, 0, len(metricPluginsMap))
-	for key := range metricPluginsMap {
-		metricPluginKeys = append(metricPluginKeys, key)
-	}
-	sort.Strings(metricPluginKeys)
-
-	// Convert metricPluginsMap to sorted slice
-	metricPlugins := make([]pluginItem, 0, len(metricPluginsMap))
-	for _, key := range metricPluginKeys {
-		metricPlugins = append(metricPlugins, metricPluginsMap[key])
+	// Append step plugins specified in RolloutManager CR
+	stepPluginsMap := map[string]pluginItem{}
+	for _, plugin := range cr.Spec.Plugins.Step {
+		// Check for duplicate step plugins
+		if _, exists := stepPluginsMap[plugin.Name]; !exists {
+			stepPluginsMap[plugin.Name] = pluginItem{
+				Name:     plugin.Name,
+				Location: getPluginLocation(cr, plugin),
+				Sha256:   plugin.SHA256,
+				Args:     plugin.Args,
+			}
+		}
 	}
+	stepPlugins := sortPluginItems(stepPluginsMap)
 
 	desiredTrafficRouterPluginString, err := yaml.Marshal(trafficRouterPlugins)
 	if err != nil {
@@ -113,9 +113,15 @@ func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr ro
 		return fmt.Errorf("error marshalling metricPlugins to string %s", err)
 	}
 
+	desiredStepPluginString, err := yaml.Marshal(stepPlugins)
+	if err != nil {
+		return fmt.Errorf("error marshalling stepPlugins to string %s", err)
+	}
+
 	desiredConfigMap.Data = map[string]string{
 		TrafficRouterPluginConfigMapKey: string(desiredTrafficRouterPluginString),
 		MetricPluginConfigMapKey:        string(desiredMetricPluginString),
+		StepPluginConfigMapKey:          string(desiredStepPluginString),
 	}
 
 	actualConfigMap := &corev1.ConfigMap{}
@@ -124,36 +130,41 @@ func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr ro
 		if errors.IsNotFound(err) {
 			// ConfigMap is not present, create default config map
 			log.Info("configMap not found, creating default configmap with openshift route plugin information")
-			return r.Client.Create(ctx, desiredConfigMap)
+			return r.createResource(ctx, &cr, desiredConfigMap)
 		}
 		return fmt.Errorf("failed to get the serviceAccount associated with %s: %w", desiredConfigMap.Name, err)
 	}
 
 	// Unmarshal the existing plugin data from the actual ConfigMap
-	var actualTrafficRouterPlugins, actualMetricPlugins []pluginItem
+	var actualTrafficRouterPlugins, actualMetricPlugins, actualStepPlugins []pluginItem
 	if err = yaml.Unmarshal([]byte(actualConfigMap.Data[TrafficRouterPluginConfigMapKey]), &actualTrafficRouterPlugins); err != nil {
 		return fmt.Errorf("failed to unmarshal traffic router plugins: %s", err)
 	}
 	if err = yaml.Unmarshal([]byte(actualConfigMap.Data[MetricPluginConfigMapKey]), &actualMetricPlugins); err != nil {
 		return fmt.Errorf("failed to unmarshal metric plugins: %s", err)
 	}
+	if err = yaml.Unmarshal([]byte(actualConfigMap.Data[StepPluginConfigMapKey]), &actualStepPlugins); err != nil {
+		return fmt.Errorf("failed to unmarshal step plugins: %s", err)
+	}
 
 	// Check if an update is needed by comparing desired and actual plugin configurations
-	updateNeeded := !reflect.DeepEqual(actualTrafficRouterPlugins, trafficRouterPlugins) || !reflect.DeepEqual(actualMetricPlugins, metricPlugins)
+	updateNeeded := !reflect.DeepEqual(actualTrafficRouterPlugins, trafficRouterPlugins) || !reflect.DeepEqual(actualMetricPlugins, metricPlugins) ||
+		!reflect.DeepEqual(actualStepPlugins, stepPlugins)
 
 	if updateNeeded {
 		// Update the ConfigMap's plugin data with the new values
 		actualConfigMap.Data[TrafficRouterPluginConfigMapKey] = string(desiredTrafficRouterPluginString)
 		actualConfigMap.Data[MetricPluginConfigMapKey] = string(desiredMetricPluginString)
+		actualConfigMap.Data[StepPluginConfigMapKey] = string(desiredStepPluginString)
 
 		// Update the ConfigMap in the cluster
-		if err := r.Client.Update(ctx, actualConfigMap); err != nil {
+		if err := r.updateResource(ctx, &cr, actualConfigMap); err != nil {
 			return fmt.Errorf("failed to update ConfigMap: %v", err)
 		}
 		log.Info("ConfigMap updated successfully")
 
 		// Restarting rollouts pod only if configMap is updated
-		if err := r.restartRolloutsPod(ctx, cr.Namespace); err != nil {
+		if err := r.restartRolloutsPod(ctx, cr); err != nil {
 			return err
 		}
 	}
@@ -161,10 +172,63 @@ func (r *RolloutManagerReconciler) reconcileConfigMap(ctx context.Context, cr ro
 	return nil
 }
 
+// validateRolloutsPlugins verifies that every plugin of the RolloutManager has either a location or a valid source, and has a sha256 checksum
+// when checksums are required by .spec.plugins.checksumPolicy.
+func validateRolloutsPlugins(cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	pluginsByType := []struct {
+		pluginType string
+		plugins    []rolloutsmanagerv1alpha1.Plugin
+	}{
+		{"trafficManagement", cr.Spec.Plugins.TrafficManagement},
+		{"metric", cr.Spec.Plugins.Metric},
+		{"step", cr.Spec.Plugins.Step},
+	}
+
+	for _, pt := range pluginsByType {
+		for _, plugin := range pt.plugins {
+			if problem := getPluginSourceProblem(plugin); problem != "" {
+				return validationFailure(fmt.Errorf("%s: the %s plugin %s %s", UnsupportedPluginConfiguration, pt.pluginType, plugin.Name, problem))
+			}
+
+			if cr.Spec.Plugins.ChecksumPolicy == rolloutsmanagerv1alpha1.PluginChecksumPolicyRequired && plugin.SHA256 == "" {
+				return validationFailure(fmt.Errorf("%s: the %s plugin %s has no sha256 checksum, which is required by .spec.plugins.checksumPolicy", UnsupportedPluginConfiguration, pt.pluginType, plugin.Name))
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// invalidPluginConfiguration returns true if the error was returned by validateRolloutsPlugins.
+func invalidPluginConfiguration(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), UnsupportedPluginConfiguration)
+}
+
+// sortPluginItems returns the plugins of the given map as a slice sorted by plugin name, for deterministic ordering.
+func sortPluginItems(pluginsMap map[string]pluginItem) []pluginItem {
+	keys := make([]string, 0, len(pluginsMap))
+	for key := range pluginsMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	plugins := make([]pluginItem, 0, len(pluginsMap))
+	for _, key := range keys {
+		plugin := pluginsMap[key]
+		// An empty list of args is omitted from the ConfigMap, so normalize it to nil to match what is read back
+		if len(plugin.Args) == 0 {
+			plugin.Args = nil
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins
+}
+
 // restartRolloutsPod deletes the Rollouts Pod to trigger a restart
-func (r *RolloutManagerReconciler) restartRolloutsPod(ctx context.Context, namespace string) error {
+func (r *RolloutManagerReconciler) restartRolloutsPod(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
 	deployment := &appsv1.Deployment{}
-	if err := r.Client.Get(ctx, types.NamespacedName{Name: DefaultArgoRolloutsResourceName, Namespace: namespace}, deployment); err != nil {
+	if err := r.Client.Get(ctx, types.NamespacedName{Name: DefaultArgoRolloutsResourceName, Namespace: cr.Namespace}, deployment); err != nil {
 		if errors.IsNotFound(err) {
 			// If Deployment isn't found, return nil as there is no child pod to restart
 			return nil
@@ -174,7 +238,7 @@ func (r *RolloutManagerReconciler) restartRolloutsPod(ctx context.Context, names
 
 	podList := &corev1.PodList{}
 	listOpts := []client.ListOption{
-		client.InNamespace(namespace),
+		client.InNamespace(cr.Namespace),
 		client.MatchingLabels(deployment.Spec.Selector.MatchLabels),
 	}
 	if err := r.Client.List(ctx, podList, listOpts...); err != nil {
@@ -193,6 +257,9 @@ func (r *RolloutManagerReconciler) restartRolloutsPod(ctx context.Context, names
 				return fmt.Errorf("failed to delete Rollouts Pod %s: %w", pod.Name, err)
 			}
 			log.Info("Rollouts Pod deleted successfully", "podName", pod.Name)
+			controllerPodRestartsTotal.Inc()
+			r.recordEvent(&cr, corev1.EventTypeNormal, EventReasonPodRestarted,
+				fmt.Sprintf("Deleted Pod %s to restart it, since ConfigMap %s has changed", pod.Name, DefaultRolloutsConfigMapName))
 		}
 	}
 
@@ -183,6 +183,179 @@ var _ = Describe("ConfigMap Test", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(len(rolloutsPodList.Items)).To(BeNumerically("==", 0))
 	})
+
+	It("verifies that step plugins and plugin args are written to the ConfigMap, without duplicates and sorted by name", func() {
+		a.Spec.Plugins.Step = []v1alpha1.Plugin{
+			{Name: "z-step-plugin", Location: "https://custom-step-plugin-location/z", Args: []string{"--log-level", "debug"}},
+			{Name: "a-step-plugin", Location: "https://custom-step-plugin-location/a", SHA256: "sha256-test", Args: []string{}},
+			{Name: "z-step-plugin", Location: "https://custom-step-plugin-location/duplicate"},
+		}
+		a.Spec.Plugins.Metric = []v1alpha1.Plugin{
+			{Name: "custom-metric-plugin", Location: metricPluginLocation, Args: []string{"--port", "8080"}},
+		}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		By("Call reconcileConfigMap")
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		fetchedConfigMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+
+		Expect(fetchedConfigMap.Data[StepPluginConfigMapKey]).To(Equal("- name: a-step-plugin\n" +
+			"  location: https://custom-step-plugin-location/a\n" +
+			"  sha256: sha256-test\n" +
+			"- name: z-step-plugin\n" +
+			"  location: https://custom-step-plugin-location/z\n" +
+			"  sha256: \"\"\n" +
+			"  args:\n" +
+			"  - --log-level\n" +
+			"  - debug\n"))
+		Expect(fetchedConfigMap.Data[MetricPluginConfigMapKey]).To(ContainSubstring("args:\n  - --port\n  - \"8080\"\n"))
+
+		By("Verify that the ConfigMap is not updated when the plugins are unchanged")
+		resourceVersion := fetchedConfigMap.ResourceVersion
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+		Expect(fetchedConfigMap.ResourceVersion).To(Equal(resourceVersion))
+
+		By("Remove step plugins from RolloutManager spec should remove them from ConfigMap")
+		a.Spec.Plugins.Step = nil
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+		Expect(fetchedConfigMap.Data[StepPluginConfigMapKey]).To(Equal("[]\n"))
+	})
+
+	It("verifies that no remote plugin location is left in the ConfigMap when plugin prefetch is enabled", func() {
+		r.OpenShiftRoutePluginLocation = DefaultOpenShiftRoutePluginURL
+		a.Spec.Plugins = v1alpha1.Plugins{
+			TrafficManagement: []v1alpha1.Plugin{{Name: "custom-traffic-plugin", Location: trafficrouterPluginLocation}},
+			Metric:            []v1alpha1.Plugin{{Name: "custom-metric-plugin", Location: metricPluginLocation}},
+			Step:              []v1alpha1.Plugin{{Name: "custom-step-plugin", Location: "http://custom-step-plugin-location"}},
+			Prefetch:          &v1alpha1.PluginPrefetchSpec{Enabled: true},
+		}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		fetchedConfigMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+
+		for _, key := range []string{TrafficRouterPluginConfigMapKey, MetricPluginConfigMapKey, StepPluginConfigMapKey} {
+			Expect(fetchedConfigMap.Data[key]).ToNot(ContainSubstring("http://"), "plugins of %s should be read from the prefetched directory", key)
+			Expect(fetchedConfigMap.Data[key]).ToNot(ContainSubstring("https://"), "plugins of %s should be read from the prefetched directory", key)
+		}
+		Expect(fetchedConfigMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring("- name: " + OpenShiftRolloutPluginName + "\n" +
+			"  location: file://" + PrefetchedPluginsDirectory + "/" + OpenShiftRolloutPluginName + "\n"))
+
+		By("verifying that the OpenShift Route plugin is downloaded by the init container")
+		deployment := generateDesiredRolloutsDeployment(a, *sa, r.OpenShiftRoutePluginLocation)
+		Expect(deployment.Spec.Template.Spec.InitContainers[0].Args[:3]).To(Equal([]string{OpenShiftRolloutPluginName, DefaultOpenShiftRoutePluginURL, ""}))
+	})
+
+	It("verifies that the sha256 checksum of a traffic management plugin is written to the ConfigMap", func() {
+		a.Spec.Plugins.TrafficManagement = []v1alpha1.Plugin{
+			{Name: "custom-traffic-plugin", Location: trafficrouterPluginLocation, SHA256: "traffic-sha256-test"},
+		}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		Expect(r.reconcileConfigMap(ctx, a)).To(Succeed())
+
+		fetchedConfigMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsConfigMapName, fetchedConfigMap)).To(Succeed())
+		Expect(fetchedConfigMap.Data[TrafficRouterPluginConfigMapKey]).To(ContainSubstring("- name: custom-traffic-plugin\n" +
+			"  location: " + trafficrouterPluginLocation + "\n" +
+			"  sha256: traffic-sha256-test\n"))
+	})
+})
+
+var _ = Describe("getPluginLocation tests", func() {
+
+	DescribeTable("should return the location of the plugin to write to the ConfigMap", func(prefetch *v1alpha1.PluginPrefetchSpec, location string, expectedLocation string) {
+		cr := *makeTestRolloutManager()
+		cr.Spec.Plugins.Prefetch = prefetch
+		Expect(getPluginLocation(cr, v1alpha1.Plugin{Name: "argoproj-labs/gatewayAPI", Location: location})).To(Equal(expectedLocation))
+	},
+		Entry("prefetch is not set", nil, "https://example.com/gatewayapi", "https://example.com/gatewayapi"),
+		Entry("prefetch is disabled", &v1alpha1.PluginPrefetchSpec{Enabled: false}, "https://example.com/gatewayapi", "https://example.com/gatewayapi"),
+		Entry("prefetch is enabled", &v1alpha1.PluginPrefetchSpec{Enabled: true}, "https://example.com/gatewayapi", "file://"+PrefetchedPluginsDirectory+"/argoproj-labs/gatewayAPI"),
+		Entry("prefetch is enabled, with a file location", &v1alpha1.PluginPrefetchSpec{Enabled: true}, "file:///tmp/gatewayapi", "file:///tmp/gatewayapi"),
+	)
+
+	DescribeTable("should return the location of a plugin with a source", func(source *v1alpha1.PluginSource, expectedFile string) {
+		cr := *makeTestRolloutManager()
+		cr.Spec.Plugins.Prefetch = &v1alpha1.PluginPrefetchSpec{Enabled: true}
+		plugin := v1alpha1.Plugin{Name: "argoproj-labs/gatewayAPI", Source: source}
+		Expect(getPluginLocation(cr, plugin)).To(Equal("file://" + PluginSourcesDirectory + "/" + getPluginSourceName(plugin) + "/" + expectedFile))
+	},
+		Entry("image", &v1alpha1.PluginSource{Image: &v1alpha1.PluginImageSource{Reference: "quay.io/my-org/gatewayapi:v0.4.0", Path: "/bin/gatewayapi"}}, "gatewayapi"),
+		Entry("persistentVolumeClaim", &v1alpha1.PluginSource{PersistentVolumeClaim: &v1alpha1.PluginVolumeSource{Name: "plugins", Path: "/traffic/gatewayapi"}}, "traffic/gatewayapi"),
+		Entry("configMap", &v1alpha1.PluginSource{ConfigMap: &v1alpha1.PluginVolumeSource{Name: "plugins", Path: "gatewayapi"}}, "gatewayapi"),
+		Entry("secret", &v1alpha1.PluginSource{Secret: &v1alpha1.PluginVolumeSource{Name: "plugins", Path: "gatewayapi"}}, "gatewayapi"),
+	)
+})
+
+var _ = Describe("validateRolloutsPlugins tests", func() {
+
+	DescribeTable("should require a checksum on every plugin only when the checksum policy is Required", func(plugins v1alpha1.Plugins, expectedErr string) {
+		cr := *makeTestRolloutManager()
+		cr.Spec.Plugins = plugins
+
+		rr, err := validateRolloutsPlugins(cr)
+		if expectedErr == "" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rr).To(BeNil())
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(expectedErr))
+		Expect(invalidPluginConfiguration(err)).To(BeTrue())
+		Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+		Expect(*rr.rolloutController).To(Equal(v1alpha1.PhaseFailure))
+	},
+		Entry("default policy without checksums", v1alpha1.Plugins{
+			TrafficManagement: []v1alpha1.Plugin{{Name: "traffic", Location: "https://example.com/traffic"}},
+		}, ""),
+		Entry("Optional policy without checksums", v1alpha1.Plugins{
+			ChecksumPolicy: v1alpha1.PluginChecksumPolicyOptional,
+			Metric:         []v1alpha1.Plugin{{Name: "metric", Location: "https://example.com/metric"}},
+		}, ""),
+		Entry("Required policy with checksums", v1alpha1.Plugins{
+			ChecksumPolicy:    v1alpha1.PluginChecksumPolicyRequired,
+			TrafficManagement: []v1alpha1.Plugin{{Name: "traffic", Location: "https://example.com/traffic", SHA256: "abc"}},
+			Metric:            []v1alpha1.Plugin{{Name: "metric", Location: "https://example.com/metric", SHA256: "def"}},
+			Step:              []v1alpha1.Plugin{{Name: "step", Location: "https://example.com/step", SHA256: "ghi"}},
+		}, ""),
+		Entry("Required policy with a traffic management plugin without checksum", v1alpha1.Plugins{
+			ChecksumPolicy:    v1alpha1.PluginChecksumPolicyRequired,
+			TrafficManagement: []v1alpha1.Plugin{{Name: "traffic", Location: "https://example.com/traffic"}},
+		}, "the trafficManagement plugin traffic has no sha256 checksum"),
+		Entry("plugin with a source", v1alpha1.Plugins{
+			Metric: []v1alpha1.Plugin{{Name: "metric", Source: &v1alpha1.PluginSource{ConfigMap: &v1alpha1.PluginVolumeSource{Name: "plugins", Path: "metric"}}}},
+		}, ""),
+		Entry("plugin without a location or a source", v1alpha1.Plugins{
+			Metric: []v1alpha1.Plugin{{Name: "metric"}},
+		}, "the metric plugin metric has neither a location nor a source"),
+		Entry("plugin with both a location and a source", v1alpha1.Plugins{
+			Step: []v1alpha1.Plugin{{Name: "step", Location: "https://example.com/step", Source: &v1alpha1.PluginSource{Secret: &v1alpha1.PluginVolumeSource{Name: "plugins", Path: "step"}}}},
+		}, "the step plugin step may not have both a location and a source"),
+		Entry("plugin with more than one source", v1alpha1.Plugins{
+			TrafficManagement: []v1alpha1.Plugin{{Name: "traffic", Source: &v1alpha1.PluginSource{
+				Image:     &v1alpha1.PluginImageSource{Reference: "quay.io/my-org/traffic:v1", Path: "/traffic"},
+				ConfigMap: &v1alpha1.PluginVolumeSource{Name: "plugins", Path: "traffic"},
+			}}},
+		}, "must have exactly one of image, persistentVolumeClaim, configMap and secret in its source"),
+		Entry("plugin with a persistentVolumeClaim source without a path", v1alpha1.Plugins{
+			Metric: []v1alpha1.Plugin{{Name: "metric", Source: &v1alpha1.PluginSource{PersistentVolumeClaim: &v1alpha1.PluginVolumeSource{Name: "plugins"}}}},
+		}, "must have both a name and a path in its persistentVolumeClaim source"),
+		Entry("Required policy with a step plugin without checksum", v1alpha1.Plugins{
+			ChecksumPolicy: v1alpha1.PluginChecksumPolicyRequired,
+			Metric:         []v1alpha1.Plugin{{Name: "metric", Location: "https://example.com/metric", SHA256: "def"}},
+			Step:           []v1alpha1.Plugin{{Name: "step", Location: "https://example.com/step"}},
+		}, "the step plugin step has no sha256 checksum"),
+	)
 })
 
 func addTestPodToFakeClient(r *RolloutManagerReconciler, namespace string, deployment *appsv1.Deployment) {
@@ -0,0 +1,268 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"sync"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	"github.com/argoproj-labs/argo-rollouts-manager/config/crd"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// RolloutsCRDVersionAnnotation is set on the Argo Rollouts CRDs managed by the operator, to the version of Argo Rollouts they come from.
+	RolloutsCRDVersionAnnotation = "argo-rollouts-manager.argoproj.io/rollouts-version"
+
+	// RolloutsCRDManagedByLabel is set on the Argo Rollouts CRDs managed by the operator.
+	RolloutsCRDManagedByLabel = "app.kubernetes.io/managed-by"
+
+	// RolloutsCRDManagedByValue is the value of RolloutsCRDManagedByLabel on the Argo Rollouts CRDs managed by the operator.
+	RolloutsCRDManagedByValue = "argo-rollouts-manager"
+
+	// olmLabelPrefix is the prefix of the labels set by OLM on the CRDs it installs.
+	olmLabelPrefix = "operators.coreos.com/"
+
+	// UnsupportedCRDManagementConfiguration is the prefix of the errors returned when .spec.crdManagement of the RolloutManager is invalid.
+	UnsupportedCRDManagementConfiguration = "invalid CRD management configuration"
+)
+
+var (
+	embeddedRolloutsCRDs     []crdv1.CustomResourceDefinition
+	embeddedRolloutsCRDsErr  error
+	embeddedRolloutsCRDsOnce sync.Once
+)
+
+func isCRDManagementEnabled(cr rolloutsmanagerv1alpha1.RolloutManager) bool {
+	return cr.Spec.CRDManagement != nil && cr.Spec.CRDManagement.Enabled
+}
+
+// validateRolloutsCRDManagement verifies that the management of the Argo Rollouts CRDs is only enabled on a cluster-scoped RolloutManager.
+func validateRolloutsCRDManagement(cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	if errs := rolloutsmanagerv1alpha1.ValidateCRDManagement(field.NewPath("spec", "crdManagement"), cr.Spec); len(errs) > 0 {
+		return fieldValidationFailure(UnsupportedCRDManagementConfiguration, errs)
+	}
+
+	return nil, nil
+}
+
+func invalidCRDManagementConfiguration(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), UnsupportedCRDManagementConfiguration)
+}
+
+// getRolloutsCRDs returns the Argo Rollouts CRDs embedded in the operator, sorted by name.
+// They match the default version of the Argo Rollouts controller, DefaultArgoRolloutsVersion.
+func getRolloutsCRDs() ([]crdv1.CustomResourceDefinition, error) {
+
+	embeddedRolloutsCRDsOnce.Do(func() {
+		files, err := fs.Glob(crd.RolloutsCRDs, "bases/*-crd.yaml")
+		if err != nil {
+			embeddedRolloutsCRDsErr = err
+			return
+		}
+
+		for _, file := range files {
+			data, err := crd.RolloutsCRDs.ReadFile(file)
+			if err != nil {
+				embeddedRolloutsCRDsErr = err
+				return
+			}
+
+			var rolloutsCRD crdv1.CustomResourceDefinition
+			if err := yaml.Unmarshal(data, &rolloutsCRD); err != nil {
+				embeddedRolloutsCRDsErr = fmt.Errorf("unable to parse the Argo Rollouts CRD %s: %w", file, err)
+				return
+			}
+			embeddedRolloutsCRDs = append(embeddedRolloutsCRDs, rolloutsCRD)
+		}
+
+		sort.Slice(embeddedRolloutsCRDs, func(i, j int) bool {
+			return embeddedRolloutsCRDs[i].Name < embeddedRolloutsCRDs[j].Name
+		})
+	})
+
+	if embeddedRolloutsCRDsErr != nil {
+		return nil, embeddedRolloutsCRDsErr
+	}
+
+	// Return copies, so that callers may modify them
+	res := make([]crdv1.CustomResourceDefinition, 0, len(embeddedRolloutsCRDs))
+	for _, rolloutsCRD := range embeddedRolloutsCRDs {
+		res = append(res, *rolloutsCRD.DeepCopy())
+	}
+	return res, nil
+}
+
+// isRolloutsCRDName returns true if name is the name of one of the Argo Rollouts CRDs embedded in the operator.
+func isRolloutsCRDName(name string) bool {
+	rolloutsCRDs, err := getRolloutsCRDs()
+	if err != nil {
+		return false
+	}
+	for _, rolloutsCRD := range rolloutsCRDs {
+		if rolloutsCRD.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isCRDInstalledByOLM returns true if the CRD carries the labels that OLM sets on the CRDs of the operators it installs.
+func isCRDInstalledByOLM(liveCRD crdv1.CustomResourceDefinition) bool {
+	for label := range liveCRD.Labels {
+		if strings.HasPrefix(label, olmLabelPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// getRemovedStoredVersions returns the versions under which objects of the live CRD are stored, and which are no longer served by the expected CRD.
+// Updating the CRD would make these objects unreadable, so the CRD must not be updated while this list is non-empty.
+func getRemovedStoredVersions(liveCRD crdv1.CustomResourceDefinition, expectedCRD crdv1.CustomResourceDefinition) []string {
+	var removed []string
+	for _, storedVersion := range liveCRD.Status.StoredVersions {
+		served := false
+		for _, version := range expectedCRD.Spec.Versions {
+			if version.Name == storedVersion && version.Served {
+				served = true
+				break
+			}
+		}
+		if !served {
+			removed = append(removed, storedVersion)
+		}
+	}
+	return removed
+}
+
+// isCRDUpToDate returns true if the live CRD is marked as managed by the operator, at the version of the embedded CRDs, and if its spec matches
+// the spec of the expected CRD, so that changes made to the CRD by hand are reverted.
+func isCRDUpToDate(liveCRD crdv1.CustomResourceDefinition, expectedCRD crdv1.CustomResourceDefinition) bool {
+
+	if liveCRD.Annotations[RolloutsCRDVersionAnnotation] != DefaultArgoRolloutsVersion || liveCRD.Labels[RolloutsCRDManagedByLabel] != RolloutsCRDManagedByValue {
+		return false
+	}
+
+	// The API server sets defaults on the spec of the CRDs, such as .spec.conversion, which the embedded CRDs do not set: apply them to both
+	// CRDs before comparing them.
+	live := liveCRD.DeepCopy()
+	expected := expectedCRD.DeepCopy()
+	crdv1.SetObjectDefaults_CustomResourceDefinition(live)
+	crdv1.SetObjectDefaults_CustomResourceDefinition(expected)
+
+	return equality.Semantic.DeepEqual(live.Spec, expected.Spec)
+}
+
+// reconcileRolloutsCRDs installs, and upgrades, the Argo Rollouts CRDs when their management is enabled on the RolloutManager.
+// CRDs are never deleted, since that would delete all the objects they hold.
+// It returns the CRDVersionSkew condition to set on the RolloutManager, or nil when the management of the CRDs is not enabled.
+func (r *RolloutManagerReconciler) reconcileRolloutsCRDs(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (*metav1.Condition, error) {
+
+	if !isCRDManagementEnabled(cr) {
+		return nil, nil
+	}
+
+	expectedCRDs, err := getRolloutsCRDs()
+	if err != nil {
+		return nil, err
+	}
+
+	controllerVersion := cr.Spec.Version
+	if controllerVersion == "" {
+		controllerVersion = DefaultArgoRolloutsVersion
+	}
+
+	// The embedded CRDs may not match the Argo Rollouts controller when it runs another version: the live CRDs, which may have been installed
+	// for that version, are then left untouched.
+	if controllerVersion != DefaultArgoRolloutsVersion {
+		return &metav1.Condition{
+			Type:   rolloutsmanagerv1alpha1.RolloutManagerCRDVersionSkewConditionType,
+			Status: metav1.ConditionTrue,
+			Reason: rolloutsmanagerv1alpha1.RolloutManagerReasonCRDVersionSkew,
+			Message: fmt.Sprintf("the operator provides the Argo Rollouts CRDs of version %s, but the Argo Rollouts controller runs version %s, so the CRDs are neither installed nor upgraded",
+				DefaultArgoRolloutsVersion, controllerVersion),
+		}, nil
+	}
+
+	var skew []string
+
+	for idx := range expectedCRDs {
+		expectedCRD := expectedCRDs[idx]
+
+		liveCRD := &crdv1.CustomResourceDefinition{}
+		if err := fetchObject(ctx, r.Client, "", expectedCRD.Name, liveCRD); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get the CRD %s: %w", expectedCRD.Name, err)
+			}
+
+			setRolloutsCRDLabelsAndAnnotations(&expectedCRD.ObjectMeta)
+
+			log.Info(fmt.Sprintf("Creating CRD %s", expectedCRD.Name))
+			if err := r.createResource(ctx, &cr, &expectedCRD); err != nil {
+				return nil, fmt.Errorf("failed to create the CRD %s: %w", expectedCRD.Name, err)
+			}
+			continue
+		}
+
+		if isCRDInstalledByOLM(*liveCRD) {
+			skew = append(skew, fmt.Sprintf("the CRD %s is installed by OLM, and is not upgraded by the operator", liveCRD.Name))
+			continue
+		}
+
+		if isCRDUpToDate(*liveCRD, expectedCRD) {
+			continue
+		}
+
+		if removed := getRemovedStoredVersions(*liveCRD, expectedCRD); len(removed) > 0 {
+			skew = append(skew, fmt.Sprintf("the CRD %s is not upgraded, since it holds objects stored as version(s) %s, which are not served by version %s of the CRD", liveCRD.Name, strings.Join(removed, ", "), DefaultArgoRolloutsVersion))
+			continue
+		}
+
+		liveCRD.Spec = expectedCRD.Spec
+		setRolloutsCRDLabelsAndAnnotations(&liveCRD.ObjectMeta)
+
+		log.Info(fmt.Sprintf("Updating CRD %s", liveCRD.Name))
+		if err := r.updateResource(ctx, &cr, liveCRD); err != nil {
+			return nil, fmt.Errorf("failed to update the CRD %s: %w", liveCRD.Name, err)
+		}
+	}
+
+	if len(skew) > 0 {
+		return &metav1.Condition{
+			Type:    rolloutsmanagerv1alpha1.RolloutManagerCRDVersionSkewConditionType,
+			Status:  metav1.ConditionTrue,
+			Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonCRDVersionSkew,
+			Message: strings.Join(skew, "; "),
+		}, nil
+	}
+
+	return &metav1.Condition{
+		Type:    rolloutsmanagerv1alpha1.RolloutManagerCRDVersionSkewConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonCRDVersionMatch,
+		Message: fmt.Sprintf("the Argo Rollouts CRDs match version %s of the Argo Rollouts controller", controllerVersion),
+	}, nil
+}
+
+// setRolloutsCRDLabelsAndAnnotations marks a CRD as managed by the operator, at the version of the embedded Argo Rollouts CRDs.
+// Other labels and annotations of the CRD are preserved.
+func setRolloutsCRDLabelsAndAnnotations(obj *metav1.ObjectMeta) {
+	if obj.Labels == nil {
+		obj.Labels = map[string]string{}
+	}
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+	obj.Labels[RolloutsCRDManagedByLabel] = RolloutsCRDManagedByValue
+	obj.Annotations[RolloutsCRDVersionAnnotation] = DefaultArgoRolloutsVersion
+}
@@ -0,0 +1,256 @@
+package rollouts
+
+import (
+	"context"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Rollouts CRD tests", func() {
+	var (
+		ctx context.Context
+		a   v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager()
+		r = makeTestReconciler(&a)
+	})
+
+	It("should embed the Argo Rollouts CRDs", func() {
+		rolloutsCRDs, err := getRolloutsCRDs()
+		Expect(err).ToNot(HaveOccurred())
+
+		var names []string
+		for _, rolloutsCRD := range rolloutsCRDs {
+			names = append(names, rolloutsCRD.Name)
+		}
+		Expect(names).To(Equal([]string{
+			"analysisruns.argoproj.io",
+			"analysistemplates.argoproj.io",
+			"clusteranalysistemplates.argoproj.io",
+			"experiments.argoproj.io",
+			"rollouts.argoproj.io",
+		}))
+		Expect(isRolloutsCRDName("rollouts.argoproj.io")).To(BeTrue())
+		Expect(isRolloutsCRDName("rolloutmanagers.argoproj.io")).To(BeFalse())
+	})
+
+	It("should not manage the CRDs when CRD management is not enabled", func() {
+		condition, err := r.reconcileRolloutsCRDs(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(condition).To(BeNil())
+
+		Expect(fetchObject(ctx, r.Client, "", "rollouts.argoproj.io", &crdv1.CustomResourceDefinition{})).ToNot(Succeed())
+	})
+
+	It("should not allow CRD management on a namespace-scoped RolloutManager", func() {
+		a.Spec.CRDManagement = &v1alpha1.RolloutsCRDManagementSpec{Enabled: true}
+		a.Spec.NamespaceScoped = true
+
+		rr, err := validateRolloutsCRDManagement(a)
+		Expect(invalidCRDManagementConfiguration(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.crdManagement.enabled: the Argo Rollouts CRDs may only be managed by a cluster-scoped RolloutManager"))
+		Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+
+		By("reconciling the RolloutManager, and verifying that the CRDs are not installed")
+		r.NamespaceScopedArgoRolloutsController = true
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		_, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&a)})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&a), &a)).To(Succeed())
+		Expect(a.Status.Phase).To(Equal(v1alpha1.PhaseFailure))
+		Expect(a.Status.Conditions[0].Reason).To(Equal(v1alpha1.RolloutManagerReasonInvalidCRDManagementConfiguration))
+		Expect(fetchObject(ctx, r.Client, "", "rollouts.argoproj.io", &crdv1.CustomResourceDefinition{})).ToNot(Succeed())
+
+		By("verifying that CRD management is allowed on a cluster-scoped RolloutManager")
+		a.Spec.NamespaceScoped = false
+		rr, err = validateRolloutsCRDManagement(a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rr).To(BeNil())
+	})
+
+	When("CRD management is enabled", func() {
+
+		BeforeEach(func() {
+			a.Spec.CRDManagement = &v1alpha1.RolloutsCRDManagementSpec{Enabled: true}
+		})
+
+		It("should install the CRDs, and report that they match the version of the controller", func() {
+			condition, err := r.reconcileRolloutsCRDs(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Type).To(Equal(v1alpha1.RolloutManagerCRDVersionSkewConditionType))
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonCRDVersionMatch))
+
+			expectedCRDs, err := getRolloutsCRDs()
+			Expect(err).ToNot(HaveOccurred())
+			for _, expectedCRD := range expectedCRDs {
+				liveCRD := &crdv1.CustomResourceDefinition{}
+				Expect(fetchObject(ctx, r.Client, "", expectedCRD.Name, liveCRD)).To(Succeed())
+				Expect(liveCRD.Spec.Names).To(Equal(expectedCRD.Spec.Names))
+				Expect(liveCRD.Spec.Versions).To(Equal(expectedCRD.Spec.Versions))
+				Expect(liveCRD.Labels[RolloutsCRDManagedByLabel]).To(Equal(RolloutsCRDManagedByValue))
+				Expect(liveCRD.Annotations[RolloutsCRDVersionAnnotation]).To(Equal(DefaultArgoRolloutsVersion))
+			}
+		})
+
+		It("should report a version skew, and leave the CRDs untouched, when the controller runs another version", func() {
+			existingCRD := &crdv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "rollouts.argoproj.io"},
+				Spec: crdv1.CustomResourceDefinitionSpec{
+					Group:    "argoproj.io",
+					Names:    crdv1.CustomResourceDefinitionNames{Kind: "Rollout", Plural: "rollouts"},
+					Scope:    crdv1.NamespaceScoped,
+					Versions: []crdv1.CustomResourceDefinitionVersion{{Name: "v1alpha1", Served: true, Storage: true}},
+				},
+			}
+			Expect(r.Client.Create(ctx, existingCRD)).To(Succeed())
+
+			a.Spec.Version = "v0.0.1"
+
+			condition, err := r.reconcileRolloutsCRDs(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal(v1alpha1.RolloutManagerReasonCRDVersionSkew))
+			Expect(condition.Message).To(Equal("the operator provides the Argo Rollouts CRDs of version " + DefaultArgoRolloutsVersion +
+				", but the Argo Rollouts controller runs version v0.0.1, so the CRDs are neither installed nor upgraded"))
+
+			By("verifying that the existing CRD is not upgraded, and that the missing CRDs are not installed")
+			liveCRD := &crdv1.CustomResourceDefinition{}
+			Expect(fetchObject(ctx, r.Client, "", existingCRD.Name, liveCRD)).To(Succeed())
+			Expect(liveCRD.Spec.Versions).To(Equal(existingCRD.Spec.Versions))
+			Expect(liveCRD.Annotations).ToNot(HaveKey(RolloutsCRDVersionAnnotation))
+			Expect(fetchObject(ctx, r.Client, "", "analysisruns.argoproj.io", &crdv1.CustomResourceDefinition{})).ToNot(Succeed())
+		})
+
+		It("should revert the changes made to the spec of a CRD it manages", func() {
+			_, err := r.reconcileRolloutsCRDs(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+
+			liveCRD := &crdv1.CustomResourceDefinition{}
+			Expect(fetchObject(ctx, r.Client, "", "rollouts.argoproj.io", liveCRD)).To(Succeed())
+			expectedSpec := liveCRD.Spec.DeepCopy()
+
+			By("reconciling again, and verifying that the CRD is not updated")
+			resourceVersion := liveCRD.ResourceVersion
+			_, err = r.reconcileRolloutsCRDs(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fetchObject(ctx, r.Client, "", "rollouts.argoproj.io", liveCRD)).To(Succeed())
+			Expect(liveCRD.ResourceVersion).To(Equal(resourceVersion))
+
+			By("modifying the spec of the CRD, and verifying that the change is reverted")
+			liveCRD.Spec.Names.ShortNames = []string{"my-short-name"}
+			liveCRD.Spec.Versions[0].Schema = nil
+			Expect(r.Client.Update(ctx, liveCRD)).To(Succeed())
+
+			_, err = r.reconcileRolloutsCRDs(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fetchObject(ctx, r.Client, "", "rollouts.argoproj.io", liveCRD)).To(Succeed())
+			Expect(liveCRD.Spec).To(Equal(*expectedSpec))
+		})
+
+		It("should accept the defaults set by the API server on the spec of the CRDs", func() {
+			expectedCRDs, err := getRolloutsCRDs()
+			Expect(err).ToNot(HaveOccurred())
+
+			liveCRD := expectedCRDs[0].DeepCopy()
+			setRolloutsCRDLabelsAndAnnotations(&liveCRD.ObjectMeta)
+			crdv1.SetObjectDefaults_CustomResourceDefinition(liveCRD)
+			Expect(liveCRD.Spec.Conversion).ToNot(BeNil())
+			Expect(isCRDUpToDate(*liveCRD, expectedCRDs[0])).To(BeTrue())
+
+			liveCRD.Spec.Names.ShortNames = append(liveCRD.Spec.Names.ShortNames, "my-short-name")
+			Expect(isCRDUpToDate(*liveCRD, expectedCRDs[0])).To(BeFalse())
+		})
+
+		It("should upgrade an existing CRD, and keep its labels and annotations", func() {
+			existingCRD := &crdv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "rollouts.argoproj.io",
+					Labels:      map[string]string{"my-label": "my-value"},
+					Annotations: map[string]string{RolloutsCRDVersionAnnotation: "v0.0.1"},
+				},
+				Spec: crdv1.CustomResourceDefinitionSpec{
+					Group:    "argoproj.io",
+					Names:    crdv1.CustomResourceDefinitionNames{Kind: "Rollout", Plural: "rollouts"},
+					Scope:    crdv1.NamespaceScoped,
+					Versions: []crdv1.CustomResourceDefinitionVersion{{Name: "v1alpha1", Served: true, Storage: true}},
+				},
+				Status: crdv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v1alpha1"}},
+			}
+			Expect(r.Client.Create(ctx, existingCRD)).To(Succeed())
+
+			condition, err := r.reconcileRolloutsCRDs(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+
+			liveCRD := &crdv1.CustomResourceDefinition{}
+			Expect(fetchObject(ctx, r.Client, "", existingCRD.Name, liveCRD)).To(Succeed())
+			Expect(liveCRD.Spec.Versions).To(HaveLen(1))
+			Expect(liveCRD.Spec.Versions[0].Schema).ToNot(BeNil())
+			Expect(liveCRD.Labels).To(HaveKeyWithValue("my-label", "my-value"))
+			Expect(liveCRD.Labels).To(HaveKeyWithValue(RolloutsCRDManagedByLabel, RolloutsCRDManagedByValue))
+			Expect(liveCRD.Annotations).To(HaveKeyWithValue(RolloutsCRDVersionAnnotation, DefaultArgoRolloutsVersion))
+		})
+
+		It("should not upgrade a CRD that holds objects stored as a version which is no longer served", func() {
+			existingCRD := &crdv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "experiments.argoproj.io"},
+				Spec: crdv1.CustomResourceDefinitionSpec{
+					Group:    "argoproj.io",
+					Names:    crdv1.CustomResourceDefinitionNames{Kind: "Experiment", Plural: "experiments"},
+					Scope:    crdv1.NamespaceScoped,
+					Versions: []crdv1.CustomResourceDefinitionVersion{{Name: "v0", Served: true, Storage: true}},
+				},
+				Status: crdv1.CustomResourceDefinitionStatus{StoredVersions: []string{"v0"}},
+			}
+			Expect(r.Client.Create(ctx, existingCRD)).To(Succeed())
+
+			condition, err := r.reconcileRolloutsCRDs(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(ContainSubstring("the CRD experiments.argoproj.io is not upgraded, since it holds objects stored as version(s) v0"))
+
+			liveCRD := &crdv1.CustomResourceDefinition{}
+			Expect(fetchObject(ctx, r.Client, "", existingCRD.Name, liveCRD)).To(Succeed())
+			Expect(liveCRD.Spec.Versions).To(Equal(existingCRD.Spec.Versions))
+		})
+
+		It("should not modify a CRD that is installed by OLM", func() {
+			existingCRD := &crdv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "analysisruns.argoproj.io",
+					Labels: map[string]string{"operators.coreos.com/argo-rollouts-manager.openshift-operators": ""},
+				},
+				Spec: crdv1.CustomResourceDefinitionSpec{
+					Group:    "argoproj.io",
+					Names:    crdv1.CustomResourceDefinitionNames{Kind: "AnalysisRun", Plural: "analysisruns"},
+					Scope:    crdv1.NamespaceScoped,
+					Versions: []crdv1.CustomResourceDefinitionVersion{{Name: "v1alpha1", Served: true, Storage: true}},
+				},
+			}
+			Expect(r.Client.Create(ctx, existingCRD)).To(Succeed())
+
+			condition, err := r.reconcileRolloutsCRDs(ctx, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal("the CRD analysisruns.argoproj.io is installed by OLM, and is not upgraded by the operator"))
+
+			liveCRD := &crdv1.CustomResourceDefinition{}
+			Expect(fetchObject(ctx, r.Client, "", existingCRD.Name, liveCRD)).To(Succeed())
+			Expect(liveCRD.Spec.Versions).To(Equal(existingCRD.Spec.Versions))
+			Expect(liveCRD.Labels).ToNot(HaveKey(RolloutsCRDManagedByLabel))
+		})
+	})
+})
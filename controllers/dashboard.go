@@ -0,0 +1,741 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// reconcileRolloutsDashboard reconciles the resources of the Argo Rollouts dashboard, or removes them if the dashboard is not enabled.
+// The dashboard follows the scope of the Rollouts controller: a namespace-scoped dashboard uses a Role, while a cluster-scoped dashboard uses a ClusterRole.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboard(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	if !isRolloutsDashboardEnabled(cr) {
+		return r.removeRolloutsDashboardResources(ctx, cr)
+	}
+
+	sa, err := r.reconcileRolloutsDashboardServiceAccount(ctx, cr)
+	if err != nil {
+		return fmt.Errorf("unable to reconcile dashboard ServiceAccount: %w", err)
+	}
+
+	if cr.Spec.NamespaceScoped {
+		role, err := r.reconcileRolloutsDashboardRole(ctx, cr)
+		if err != nil {
+			return fmt.Errorf("unable to reconcile dashboard Role: %w", err)
+		}
+
+		if err := r.reconcileRolloutsDashboardRoleBinding(ctx, cr, role, sa); err != nil {
+			return fmt.Errorf("unable to reconcile dashboard RoleBinding: %w", err)
+		}
+	} else {
+		clusterRole, err := r.reconcileRolloutsDashboardClusterRole(ctx, cr)
+		if err != nil {
+			return fmt.Errorf("unable to reconcile dashboard ClusterRole: %w", err)
+		}
+
+		if err := r.reconcileRolloutsDashboardClusterRoleBinding(ctx, cr, clusterRole, sa); err != nil {
+			return fmt.Errorf("unable to reconcile dashboard ClusterRoleBinding: %w", err)
+		}
+	}
+
+	if err := r.reconcileRolloutsDashboardDeployment(ctx, cr, *sa); err != nil {
+		return fmt.Errorf("unable to reconcile dashboard Deployment: %w", err)
+	}
+
+	if err := r.reconcileRolloutsDashboardService(ctx, cr); err != nil {
+		return fmt.Errorf("unable to reconcile dashboard Service: %w", err)
+	}
+
+	return nil
+}
+
+// isRolloutsDashboardEnabled returns true if the RolloutManager requests the Argo Rollouts dashboard.
+func isRolloutsDashboardEnabled(cr rolloutsmanagerv1alpha1.RolloutManager) bool {
+	return cr.Spec.Dashboard != nil && cr.Spec.Dashboard.Enabled
+}
+
+// setRolloutsDashboardLabelsAndAnnotationsToObject sets the standard labels/annotations on a dashboard resource, overriding the name and component labels.
+func setRolloutsDashboardLabelsAndAnnotationsToObject(obj *metav1.ObjectMeta, cr rolloutsmanagerv1alpha1.RolloutManager) {
+	setRolloutsLabelsAndAnnotationsToObject(obj, cr)
+	obj.Labels["app.kubernetes.io/name"] = DefaultArgoRolloutsDashboardResourceName
+	obj.Labels["app.kubernetes.io/component"] = "rollouts-dashboard"
+}
+
+// Reconciles Rollouts dashboard ServiceAccount.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardServiceAccount(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (*corev1.ServiceAccount, error) {
+	expectedServiceAccount := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedServiceAccount.ObjectMeta, cr)
+
+	liveServiceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: expectedServiceAccount.Name, Namespace: expectedServiceAccount.Namespace}}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, liveServiceAccount.Name, liveServiceAccount); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get the ServiceAccount associated with %s: %w", liveServiceAccount.Name, err)
+		}
+
+		if err := controllerutil.SetControllerReference(&cr, expectedServiceAccount, r.Scheme); err != nil {
+			return nil, err
+		}
+
+		log.Info(fmt.Sprintf("Creating ServiceAccount %s", expectedServiceAccount.Name))
+		return expectedServiceAccount, r.createResource(ctx, &cr, expectedServiceAccount)
+	}
+
+	normalizedLiveServiceAccount := liveServiceAccount.DeepCopy()
+	removeUserLabelsAndAnnotations(&normalizedLiveServiceAccount.ObjectMeta, cr)
+
+	if !reflect.DeepEqual(normalizedLiveServiceAccount.Labels, expectedServiceAccount.Labels) || !reflect.DeepEqual(normalizedLiveServiceAccount.Annotations, expectedServiceAccount.Annotations) {
+		log.Info(fmt.Sprintf("Labels/Annotations of ServiceAccount %s do not match the expected state, hence updating it", liveServiceAccount.Name))
+
+		liveServiceAccount.Labels = combineStringMaps(liveServiceAccount.Labels, expectedServiceAccount.Labels)
+		liveServiceAccount.Annotations = combineStringMaps(liveServiceAccount.Annotations, expectedServiceAccount.Annotations)
+
+		return liveServiceAccount, r.updateResource(ctx, &cr, liveServiceAccount)
+	}
+
+	return liveServiceAccount, nil
+}
+
+// Reconciles Rollouts dashboard Role.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardRole(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (*rbacv1.Role, error) {
+	expectedPolicyRules := GetDashboardPolicyRules()
+
+	expectedRole := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedRole.ObjectMeta, cr)
+
+	liveRole := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: expectedRole.Name, Namespace: expectedRole.Namespace}}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, liveRole.Name, liveRole); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to reconcile the Role for the ServiceAccount associated with %s: %w", liveRole.Name, err)
+		}
+
+		if err = controllerutil.SetControllerReference(&cr, expectedRole, r.Scheme); err != nil {
+			return nil, err
+		}
+
+		log.Info(fmt.Sprintf("Creating Role %s", expectedRole.Name))
+		expectedRole.Rules = expectedPolicyRules
+		return expectedRole, r.createResource(ctx, &cr, expectedRole)
+	}
+
+	updateNeeded := false
+
+	if !reflect.DeepEqual(liveRole.Rules, expectedPolicyRules) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("PolicyRules of Role %s do not match the expected state, hence updating it", liveRole.Name))
+		liveRole.Rules = expectedPolicyRules
+	}
+
+	normalizedLiveRole := liveRole.DeepCopy()
+	removeUserLabelsAndAnnotations(&normalizedLiveRole.ObjectMeta, cr)
+
+	if !reflect.DeepEqual(normalizedLiveRole.Labels, expectedRole.Labels) || !reflect.DeepEqual(normalizedLiveRole.Annotations, expectedRole.Annotations) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Labels/Annotations of Role %s do not match the expected state, hence updating it", liveRole.Name))
+
+		liveRole.Labels = combineStringMaps(liveRole.Labels, expectedRole.Labels)
+		liveRole.Annotations = combineStringMaps(liveRole.Annotations, expectedRole.Annotations)
+	}
+
+	if updateNeeded {
+		return liveRole, r.updateResource(ctx, &cr, liveRole)
+	}
+
+	return liveRole, nil
+}
+
+// Reconciles Rollouts dashboard ClusterRole.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardClusterRole(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (*rbacv1.ClusterRole, error) {
+	expectedPolicyRules := GetDashboardPolicyRules()
+
+	expectedClusterRole := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultArgoRolloutsDashboardResourceName,
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedClusterRole.ObjectMeta, cr)
+
+	liveClusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: expectedClusterRole.Name}}
+	if err := fetchObject(ctx, r.Client, "", liveClusterRole.Name, liveClusterRole); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to Reconcile the ClusterRole for the ServiceAccount associated with %s: %w", liveClusterRole.Name, err)
+		}
+
+		log.Info(fmt.Sprintf("Creating ClusterRole %s", liveClusterRole.Name))
+		expectedClusterRole.Rules = expectedPolicyRules
+		return expectedClusterRole, r.createResource(ctx, &cr, expectedClusterRole)
+	}
+
+	updateNeeded := false
+
+	if !reflect.DeepEqual(liveClusterRole.Rules, expectedPolicyRules) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("PolicyRules of ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
+		liveClusterRole.Rules = expectedPolicyRules
+	}
+
+	normalizedLiveClusterRole := liveClusterRole.DeepCopy()
+	removeUserLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, cr)
+
+	if !reflect.DeepEqual(normalizedLiveClusterRole.Labels, expectedClusterRole.Labels) || !reflect.DeepEqual(normalizedLiveClusterRole.Annotations, expectedClusterRole.Annotations) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Labels/Annotations of ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
+
+		liveClusterRole.Labels = combineStringMaps(liveClusterRole.Labels, expectedClusterRole.Labels)
+		liveClusterRole.Annotations = combineStringMaps(liveClusterRole.Annotations, expectedClusterRole.Annotations)
+	}
+
+	if updateNeeded {
+		return liveClusterRole, r.updateResource(ctx, &cr, liveClusterRole)
+	}
+
+	return liveClusterRole, nil
+}
+
+// Reconciles Rollouts dashboard RoleBinding.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardRoleBinding(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, role *rbacv1.Role, sa *corev1.ServiceAccount) error {
+
+	expectedRoleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			},
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedRoleBinding.ObjectMeta, cr)
+
+	liveRoleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: expectedRoleBinding.Name, Namespace: expectedRoleBinding.Namespace}}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, liveRoleBinding.Name, liveRoleBinding); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the RoleBinding associated with %s: %w", expectedRoleBinding.Name, err)
+		}
+
+		if err := controllerutil.SetControllerReference(&cr, expectedRoleBinding, r.Scheme); err != nil {
+			return err
+		}
+
+		log.Info(fmt.Sprintf("Creating RoleBinding %s", expectedRoleBinding.Name))
+		return r.createResource(ctx, &cr, expectedRoleBinding)
+	}
+
+	updateNeeded := false
+
+	if !reflect.DeepEqual(expectedRoleBinding.Subjects, liveRoleBinding.Subjects) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Subjects of RoleBinding %s do not match the expected state, hence updating it", liveRoleBinding.Name))
+		liveRoleBinding.Subjects = expectedRoleBinding.Subjects
+	}
+
+	normalizedLiveRoleBinding := liveRoleBinding.DeepCopy()
+	removeUserLabelsAndAnnotations(&normalizedLiveRoleBinding.ObjectMeta, cr)
+	if !reflect.DeepEqual(normalizedLiveRoleBinding.Labels, expectedRoleBinding.Labels) || !reflect.DeepEqual(normalizedLiveRoleBinding.Annotations, expectedRoleBinding.Annotations) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Labels/Annotations of RoleBinding %s do not match the expected state, hence updating it", liveRoleBinding.Name))
+
+		liveRoleBinding.Labels = combineStringMaps(liveRoleBinding.Labels, expectedRoleBinding.Labels)
+		liveRoleBinding.Annotations = combineStringMaps(liveRoleBinding.Annotations, expectedRoleBinding.Annotations)
+	}
+
+	if updateNeeded {
+		return r.updateResource(ctx, &cr, liveRoleBinding)
+	}
+
+	return nil
+}
+
+// Reconciles Rollouts dashboard ClusterRoleBinding.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardClusterRoleBinding(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, clusterRole *rbacv1.ClusterRole, sa *corev1.ServiceAccount) error {
+
+	expectedClusterRoleBinding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: DefaultArgoRolloutsDashboardResourceName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole.Name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa.Name,
+				Namespace: sa.Namespace,
+			},
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedClusterRoleBinding.ObjectMeta, cr)
+
+	liveClusterRoleBinding := &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: expectedClusterRoleBinding.Name}}
+	if err := fetchObject(ctx, r.Client, "", liveClusterRoleBinding.Name, liveClusterRoleBinding); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the ClusterRoleBinding associated with %s: %w", expectedClusterRoleBinding.Name, err)
+		}
+
+		log.Info(fmt.Sprintf("Creating ClusterRoleBinding %s", expectedClusterRoleBinding.Name))
+		return r.createResource(ctx, &cr, expectedClusterRoleBinding)
+	}
+
+	updateNeeded := false
+
+	if !reflect.DeepEqual(expectedClusterRoleBinding.Subjects, liveClusterRoleBinding.Subjects) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Subjects of ClusterRoleBinding %s do not match the expected state, hence updating it", expectedClusterRoleBinding.Name))
+		liveClusterRoleBinding.Subjects = expectedClusterRoleBinding.Subjects
+	}
+
+	normalizedLiveClusterRoleBinding := liveClusterRoleBinding.DeepCopy()
+	removeUserLabelsAndAnnotations(&normalizedLiveClusterRoleBinding.ObjectMeta, cr)
+	if !reflect.DeepEqual(normalizedLiveClusterRoleBinding.Labels, expectedClusterRoleBinding.Labels) || !reflect.DeepEqual(normalizedLiveClusterRoleBinding.Annotations, expectedClusterRoleBinding.Annotations) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Labels/Annotations of ClusterRoleBinding %s do not match the expected state, hence updating it", liveClusterRoleBinding.Name))
+
+		liveClusterRoleBinding.Labels = combineStringMaps(liveClusterRoleBinding.Labels, expectedClusterRoleBinding.Labels)
+		liveClusterRoleBinding.Annotations = combineStringMaps(liveClusterRoleBinding.Annotations, expectedClusterRoleBinding.Annotations)
+	}
+
+	if updateNeeded {
+		return r.updateResource(ctx, &cr, liveClusterRoleBinding)
+	}
+
+	return nil
+}
+
+func generateDesiredRolloutsDashboardDeployment(cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount) appsv1.Deployment {
+
+	desiredDeployment := appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&desiredDeployment.ObjectMeta, cr)
+
+	labels := map[string]string{
+		DefaultRolloutsSelectorKey: DefaultArgoRolloutsDashboardResourceName,
+	}
+
+	args := []string{"dashboard"}
+	if cr.Spec.NamespaceScoped {
+		args = append(args, "--namespace", cr.Namespace)
+	}
+
+	containerResources := defaultRolloutsContainerResources()
+	if cr.Spec.Dashboard != nil && cr.Spec.Dashboard.Resources != nil {
+		containerResources = *cr.Spec.Dashboard.Resources
+	}
+
+	desiredDeployment.Spec = appsv1.DeploymentSpec{
+		Selector: &metav1.LabelSelector{
+			MatchLabels: labels,
+		},
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: labels,
+			},
+			Spec: corev1.PodSpec{
+				NodeSelector: map[string]string{
+					"kubernetes.io/os": "linux",
+				},
+				SecurityContext: &corev1.PodSecurityContext{
+					RunAsNonRoot: boolPtr(true),
+				},
+				ServiceAccountName: sa.Name,
+				Containers: []corev1.Container{
+					{
+						Args:            args,
+						Image:           getRolloutsDashboardContainerImage(cr),
+						ImagePullPolicy: corev1.PullAlways,
+						Name:            DefaultArgoRolloutsDashboardResourceName,
+						Ports: []corev1.ContainerPort{
+							{
+								ContainerPort: DefaultArgoRolloutsDashboardPort,
+								Name:          "dashboard",
+							},
+						},
+						Resources: containerResources,
+						SecurityContext: &corev1.SecurityContext{
+							Capabilities: &corev1.Capabilities{
+								Drop: []corev1.Capability{
+									"ALL",
+								},
+							},
+							AllowPrivilegeEscalation: boolPtr(false),
+							ReadOnlyRootFilesystem:   boolPtr(true),
+							RunAsNonRoot:             boolPtr(true),
+							SeccompProfile: &corev1.SeccompProfile{
+								Type: corev1.SeccompProfileTypeRuntimeDefault,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	if cr.Spec.NodePlacement != nil {
+		desiredDeployment.Spec.Template.Spec.NodeSelector = appendStringMap(
+			desiredDeployment.Spec.Template.Spec.NodeSelector, cr.Spec.NodePlacement.NodeSelector)
+		desiredDeployment.Spec.Template.Spec.Tolerations = cr.Spec.NodePlacement.Tolerations
+	}
+
+	return desiredDeployment
+}
+
+// Reconciles Rollouts dashboard Deployment.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardDeployment(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount) error {
+
+	desiredDeployment := generateDesiredRolloutsDashboardDeployment(cr, sa)
+
+	liveDeployment := &appsv1.Deployment{}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, desiredDeployment.Name, liveDeployment); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the Deployment %s: %w", desiredDeployment.Name, err)
+		}
+
+		if err := controllerutil.SetControllerReference(&cr, &desiredDeployment, r.Scheme); err != nil {
+			return err
+		}
+
+		log.Info(fmt.Sprintf("Creating Deployment %s", desiredDeployment.Name))
+		return r.createResource(ctx, &cr, &desiredDeployment)
+	}
+
+	if !reflect.DeepEqual(liveDeployment.Spec.Selector, desiredDeployment.Spec.Selector) {
+		// .spec.selector is immutable, so the Deployment needs to be recreated
+		log.Info(fmt.Sprintf("deleting and recreating Deployment %s, as the .spec.selector field of the Deployment has changed", liveDeployment.Name))
+
+		if err := r.Client.Delete(ctx, liveDeployment); err != nil {
+			return fmt.Errorf("unable to delete dashboard Deployment after .spec.selector change: %w", err)
+		}
+
+		r.recordEvent(&cr, corev1.EventTypeNormal, EventReasonDeploymentRecreated,
+			fmt.Sprintf("Deleted Deployment %s to recreate it, since its immutable .spec.selector has changed", liveDeployment.Name))
+
+		if err := controllerutil.SetControllerReference(&cr, &desiredDeployment, r.Scheme); err != nil {
+			return err
+		}
+		return r.createResource(ctx, &cr, &desiredDeployment)
+	}
+
+	updateNeeded := false
+
+	if diff := identifyDashboardDeploymentDifference(*liveDeployment, desiredDeployment); diff != "" {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("%s of Deployment %s does not match the expected state, hence updating it", diff, liveDeployment.Name))
+
+		liveDeployment.Spec.Template.Labels = desiredDeployment.Spec.Template.Labels
+		liveDeployment.Spec.Template.Spec.ServiceAccountName = desiredDeployment.Spec.Template.Spec.ServiceAccountName
+		liveDeployment.Spec.Template.Spec.NodeSelector = desiredDeployment.Spec.Template.Spec.NodeSelector
+		liveDeployment.Spec.Template.Spec.Tolerations = desiredDeployment.Spec.Template.Spec.Tolerations
+		liveDeployment.Spec.Template.Spec.SecurityContext = desiredDeployment.Spec.Template.Spec.SecurityContext
+		liveDeployment.Spec.Template.Spec.Containers = desiredDeployment.Spec.Template.Spec.Containers
+	}
+
+	normalizedLiveDeployment := liveDeployment.DeepCopy()
+	removeUserLabelsAndAnnotations(&normalizedLiveDeployment.ObjectMeta, cr)
+	if !reflect.DeepEqual(normalizedLiveDeployment.Labels, desiredDeployment.Labels) || !reflect.DeepEqual(normalizedLiveDeployment.Annotations, desiredDeployment.Annotations) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Labels/Annotations of Deployment %s do not match the expected state, hence updating it", liveDeployment.Name))
+
+		liveDeployment.Labels = combineStringMaps(liveDeployment.Labels, desiredDeployment.Labels)
+		liveDeployment.Annotations = combineStringMaps(liveDeployment.Annotations, desiredDeployment.Annotations)
+	}
+
+	if updateNeeded {
+		return r.updateResource(ctx, &cr, liveDeployment)
+	}
+
+	return nil
+}
+
+// identifyDashboardDeploymentDifference compares only the fields of the dashboard Deployment that are set by the operator, returning "" if they are the same, otherwise returning the name of the field that changed.
+func identifyDashboardDeploymentDifference(live appsv1.Deployment, desired appsv1.Deployment) string {
+
+	livePodSpec := live.Spec.Template.Spec
+	desiredPodSpec := desired.Spec.Template.Spec
+
+	if !reflect.DeepEqual(live.Spec.Template.Labels, desired.Spec.Template.Labels) {
+		return ".Spec.Template.Labels"
+	}
+
+	if livePodSpec.ServiceAccountName != desiredPodSpec.ServiceAccountName {
+		return "ServiceAccountName"
+	}
+
+	if !reflect.DeepEqual(livePodSpec.NodeSelector, desiredPodSpec.NodeSelector) {
+		return "Spec.Template.Spec.NodeSelector"
+	}
+
+	if !reflect.DeepEqual(livePodSpec.Tolerations, desiredPodSpec.Tolerations) {
+		return "Spec.Template.Spec.Tolerations"
+	}
+
+	if livePodSpec.SecurityContext == nil || !reflect.DeepEqual(livePodSpec.SecurityContext.RunAsNonRoot, desiredPodSpec.SecurityContext.RunAsNonRoot) {
+		return "Spec.Template.Spec.SecurityContext"
+	}
+
+	if len(livePodSpec.Containers) != len(desiredPodSpec.Containers) {
+		return "Spec.Template.Spec.Containers"
+	}
+
+	for i := range desiredPodSpec.Containers {
+		liveContainer := livePodSpec.Containers[i]
+		desiredContainer := desiredPodSpec.Containers[i]
+
+		if liveContainer.Name != desiredContainer.Name ||
+			liveContainer.Image != desiredContainer.Image ||
+			liveContainer.ImagePullPolicy != desiredContainer.ImagePullPolicy ||
+			!reflect.DeepEqual(liveContainer.Args, desiredContainer.Args) ||
+			!reflect.DeepEqual(liveContainer.Resources, desiredContainer.Resources) ||
+			!reflect.DeepEqual(liveContainer.SecurityContext, desiredContainer.SecurityContext) {
+			return "Spec.Template.Spec.Containers"
+		}
+
+		if len(liveContainer.Ports) != len(desiredContainer.Ports) {
+			return "Spec.Template.Spec.Containers.Ports"
+		}
+		for j := range desiredContainer.Ports {
+			if liveContainer.Ports[j].Name != desiredContainer.Ports[j].Name || liveContainer.Ports[j].ContainerPort != desiredContainer.Ports[j].ContainerPort {
+				return "Spec.Template.Spec.Containers.Ports"
+			}
+		}
+	}
+
+	return ""
+}
+
+// Reconciles Rollouts dashboard Service.
+func (r *RolloutManagerReconciler) reconcileRolloutsDashboardService(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	expectedSvc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsDashboardResourceName,
+			Namespace: cr.Namespace,
+		},
+	}
+	setRolloutsDashboardLabelsAndAnnotationsToObject(&expectedSvc.ObjectMeta, cr)
+
+	expectedSvc.Spec.Ports = []corev1.ServicePort{
+		{
+			Name:       "dashboard",
+			Port:       DefaultArgoRolloutsDashboardPort,
+			Protocol:   corev1.ProtocolTCP,
+			TargetPort: intstr.FromInt(DefaultArgoRolloutsDashboardPort),
+		},
+	}
+
+	expectedSvc.Spec.Selector = map[string]string{
+		DefaultRolloutsSelectorKey: DefaultArgoRolloutsDashboardResourceName,
+	}
+
+	liveService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: expectedSvc.Name, Namespace: expectedSvc.Namespace}}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, liveService.Name, liveService); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the Service %s: %w", expectedSvc.Name, err)
+		}
+
+		if err := controllerutil.SetControllerReference(&cr, expectedSvc, r.Scheme); err != nil {
+			return err
+		}
+
+		log.Info(fmt.Sprintf("Creating Service %s", expectedSvc.Name))
+		return r.createResource(ctx, &cr, expectedSvc)
+	}
+
+	updateNeeded := false
+
+	if !reflect.DeepEqual(liveService.Spec.Ports, expectedSvc.Spec.Ports) || !reflect.DeepEqual(liveService.Spec.Selector, expectedSvc.Spec.Selector) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Ports/Selector of dashboard Service %s do not match the expected state, hence updating it", liveService.Name))
+		liveService.Spec.Ports = expectedSvc.Spec.Ports
+		liveService.Spec.Selector = expectedSvc.Spec.Selector
+	}
+
+	normalizedLiveService := liveService.DeepCopy()
+	removeUserLabelsAndAnnotations(&normalizedLiveService.ObjectMeta, cr)
+	if !reflect.DeepEqual(normalizedLiveService.Labels, expectedSvc.Labels) || !reflect.DeepEqual(normalizedLiveService.Annotations, expectedSvc.Annotations) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Labels/Annotations of dashboard Service %s do not match the expected state, hence updating it", liveService.Name))
+
+		liveService.Labels = combineStringMaps(liveService.Labels, expectedSvc.Labels)
+		liveService.Annotations = combineStringMaps(liveService.Annotations, expectedSvc.Annotations)
+	}
+
+	if updateNeeded {
+		return r.updateResource(ctx, &cr, liveService)
+	}
+
+	return nil
+}
+
+// removeRolloutsDashboardResources deletes the dashboard resources created for the RolloutManager, if they exist.
+func (r *RolloutManagerReconciler) removeRolloutsDashboardResources(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	namespacedResources := []client.Object{
+		&appsv1.Deployment{},
+		&corev1.Service{},
+		&rbacv1.RoleBinding{},
+		&rbacv1.Role{},
+		&corev1.ServiceAccount{},
+	}
+
+	for _, obj := range namespacedResources {
+		if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsDashboardResourceName, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to get the dashboard %s: %w", reflect.TypeOf(obj).Elem().Name(), err)
+		}
+
+		// Only delete the resources that were created for this RolloutManager
+		controller := metav1.GetControllerOf(obj)
+		if controller == nil || controller.Name != cr.Name {
+			continue
+		}
+
+		log.Info(fmt.Sprintf("Dashboard is not enabled, deleting %s %s", reflect.TypeOf(obj).Elem().Name(), obj.GetName()))
+		if err := r.deleteResource(ctx, &cr, obj); err != nil {
+			return err
+		}
+	}
+
+	// Cluster-scoped dashboard resources can only have been created by a cluster-scoped RolloutManager.
+	if cr.Spec.NamespaceScoped {
+		return nil
+	}
+
+	return r.removeRolloutsDashboardClusterScopedResources(ctx, &cr)
+}
+
+// removeRolloutsDashboardClusterScopedResources deletes the dashboard ClusterRole and ClusterRoleBinding, if they exist.
+// The deletions are recorded as events on cr, unless it is nil.
+func (r *RolloutManagerReconciler) removeRolloutsDashboardClusterScopedResources(ctx context.Context, cr *rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	clusterResources := []client.Object{
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsDashboardResourceName}},
+		&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsDashboardResourceName}},
+	}
+
+	for _, obj := range clusterResources {
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			log.Error(err, fmt.Sprintf("error on retrieving dashboard %s", reflect.TypeOf(obj).Elem().Name()))
+			return err
+		}
+
+		log.Info(fmt.Sprintf("deleting dashboard %s %s", reflect.TypeOf(obj).Elem().Name(), obj.GetName()))
+		if err := r.deleteResource(ctx, cr, obj); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the container image for the Rollouts dashboard.
+func getRolloutsDashboardContainerImage(cr rolloutsmanagerv1alpha1.RolloutManager) string {
+
+	img := DefaultArgoRolloutsDashboardImage
+	tag := DefaultArgoRolloutsVersion
+
+	// The dashboard is released together with the Rollouts controller, so it defaults to the same version
+	if cr.Spec.Version != "" {
+		tag = cr.Spec.Version
+	}
+
+	if cr.Spec.Dashboard != nil {
+		if cr.Spec.Dashboard.Image != "" {
+			img = cr.Spec.Dashboard.Image
+		}
+		if cr.Spec.Dashboard.Version != "" {
+			tag = cr.Spec.Dashboard.Version
+		}
+	}
+
+	return combineImageTag(img, tag)
+}
+
+// GetDashboardPolicyRules returns the read-only policy rules used by the Rollouts dashboard.
+func GetDashboardPolicyRules() []rbacv1.PolicyRule {
+	return []rbacv1.PolicyRule{
+		{
+			APIGroups: []string{
+				"argoproj.io",
+			},
+			Resources: []string{
+				"rollouts",
+				"rollouts/status",
+				"analysisruns",
+				"analysistemplates",
+				"clusteranalysistemplates",
+				"experiments",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"apps",
+			},
+			Resources: []string{
+				"deployments",
+				"replicasets",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+		{
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"pods",
+				"services",
+			},
+			Verbs: []string{
+				"get",
+				"list",
+				"watch",
+			},
+		},
+	}
+}
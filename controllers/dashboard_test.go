@@ -0,0 +1,147 @@
+package rollouts
+
+import (
+	"context"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("Rollouts dashboard tests", func() {
+	var (
+		ctx context.Context
+		a   v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager()
+		r = makeTestReconciler(&a)
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+	})
+
+	It("should not create any dashboard resources when the dashboard is not enabled", func() {
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, &corev1.ServiceAccount{})).ToNot(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, &appsv1.Deployment{})).ToNot(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, &corev1.Service{})).ToNot(Succeed())
+		Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, &rbacv1.ClusterRole{})).ToNot(Succeed())
+	})
+
+	DescribeTable("should create, and then remove, the dashboard resources using the scope of the RolloutManager", func(namespaceScoped bool) {
+
+		By("enabling the dashboard")
+		a.Spec.NamespaceScoped = namespaceScoped
+		a.Spec.Dashboard = &v1alpha1.RolloutsDashboardSpec{Enabled: true}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+
+		sa := &corev1.ServiceAccount{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, sa)).To(Succeed())
+
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal(sa.Name))
+		Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal(DefaultArgoRolloutsDashboardImage + ":" + DefaultArgoRolloutsVersion))
+
+		service := &corev1.Service{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, service)).To(Succeed())
+		Expect(service.Spec.Selector).To(Equal(deployment.Spec.Template.Labels))
+
+		role := &rbacv1.Role{}
+		roleBinding := &rbacv1.RoleBinding{}
+		clusterRole := &rbacv1.ClusterRole{}
+		clusterRoleBinding := &rbacv1.ClusterRoleBinding{}
+
+		if namespaceScoped {
+			Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"dashboard", "--namespace", a.Namespace}))
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, role)).To(Succeed())
+			Expect(role.Rules).To(Equal(GetDashboardPolicyRules()))
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, roleBinding)).To(Succeed())
+			Expect(roleBinding.RoleRef.Name).To(Equal(role.Name))
+
+			Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, clusterRole)).ToNot(Succeed())
+			Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, clusterRoleBinding)).ToNot(Succeed())
+		} else {
+			Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"dashboard"}))
+
+			Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, clusterRole)).To(Succeed())
+			Expect(clusterRole.Rules).To(Equal(GetDashboardPolicyRules()))
+			Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, clusterRoleBinding)).To(Succeed())
+			Expect(clusterRoleBinding.RoleRef.Name).To(Equal(clusterRole.Name))
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, role)).ToNot(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, roleBinding)).ToNot(Succeed())
+		}
+
+		By("disabling the dashboard")
+		a.Spec.Dashboard.Enabled = false
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+
+		for _, obj := range []client.Object{sa, deployment, service, role, roleBinding} {
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, obj)).ToNot(Succeed())
+		}
+		Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, clusterRole)).ToNot(Succeed())
+		Expect(fetchObject(ctx, r.Client, "", DefaultArgoRolloutsDashboardResourceName, clusterRoleBinding)).ToNot(Succeed())
+	},
+		Entry("namespace-scoped RolloutManager", true),
+		Entry("cluster-scoped RolloutManager", false),
+	)
+
+	It("should revert changes made to the dashboard Deployment outside of the operator", func() {
+		a.Spec.Dashboard = &v1alpha1.RolloutsDashboardSpec{Enabled: true}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, deployment)).To(Succeed())
+		expectedContainers := deployment.DeepCopy().Spec.Template.Spec.Containers
+
+		By("modifying the dashboard container")
+		deployment.Spec.Template.Spec.Containers[0].Image = "my-image:latest"
+		deployment.Spec.Template.Spec.Containers[0].Args = []string{"dashboard", "--port", "1234"}
+		Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers).To(Equal(expectedContainers))
+	})
+
+	It("should not delete dashboard resources that are not owned by the RolloutManager", func() {
+		sa := &corev1.ServiceAccount{}
+		sa.Name = DefaultArgoRolloutsDashboardResourceName
+		sa.Namespace = a.Namespace
+		Expect(r.Client.Create(ctx, sa)).To(Succeed())
+
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, sa)).To(Succeed())
+	})
+})
+
+var _ = Describe("getRolloutsDashboardContainerImage tests", func() {
+
+	DescribeTable("should return the expected dashboard image", func(spec v1alpha1.RolloutManagerSpec, expectedImage string) {
+		cr := v1alpha1.RolloutManager{Spec: spec}
+		Expect(getRolloutsDashboardContainerImage(cr)).To(Equal(expectedImage))
+	},
+		Entry("default spec", v1alpha1.RolloutManagerSpec{}, DefaultArgoRolloutsDashboardImage+":"+DefaultArgoRolloutsVersion),
+		Entry("controller version is set", v1alpha1.RolloutManagerSpec{
+			Version: "v1.6.0",
+		}, DefaultArgoRolloutsDashboardImage+":v1.6.0"),
+		Entry("dashboard image and version are set", v1alpha1.RolloutManagerSpec{
+			Version:   "v1.6.0",
+			Dashboard: &v1alpha1.RolloutsDashboardSpec{Image: "quay.io/my/dashboard", Version: "v1.7.0"},
+		}, "quay.io/my/dashboard:v1.7.0"),
+	)
+})
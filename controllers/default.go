@@ -1,35 +1,102 @@
 package rollouts
 
+import (
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+)
+
 const (
 	// ArgoRolloutsImageEnvName is an environment variable that can be used to deploy a
 	// Custom Image of rollouts controller.
-	ArgoRolloutsImageEnvName = "ARGO_ROLLOUTS_IMAGE"
+	ArgoRolloutsImageEnvName = rolloutsmanagerv1alpha1.ArgoRolloutsImageEnvName
 
 	// DefaultArgoRolloutsMetricsServiceName is the default name for rollouts metrics Service.
-	DefaultArgoRolloutsMetricsServiceName = "argo-rollouts-metrics"
+	DefaultArgoRolloutsMetricsServiceName = rolloutsmanagerv1alpha1.DefaultArgoRolloutsMetricsServiceName
 
 	// ArgoRolloutsDefaultImage is the default image for rollouts controller.
-	DefaultArgoRolloutsImage = "quay.io/argoproj/argo-rollouts"
+	DefaultArgoRolloutsImage = rolloutsmanagerv1alpha1.DefaultArgoRolloutsImage
 
 	// ArgoRolloutsDefaultVersion is the default version for the Rollouts controller.
-	DefaultArgoRolloutsVersion = "v1.7.1" // v1.7.1
+	DefaultArgoRolloutsVersion = rolloutsmanagerv1alpha1.DefaultArgoRolloutsVersion
+
+	// DefaultArgoRolloutsDashboardImage is the default image for the Rollouts dashboard.
+	DefaultArgoRolloutsDashboardImage = "quay.io/argoproj/kubectl-argo-rollouts"
+
+	// DefaultArgoRolloutsDashboardResourceName is the default name for Rollouts dashboard resources such as
+	// deployment, service, role, rolebinding and serviceaccount.
+	DefaultArgoRolloutsDashboardResourceName = "argo-rollouts-dashboard"
+
+	// DefaultArgoRolloutsDashboardPort is the port on which the Rollouts dashboard is served.
+	DefaultArgoRolloutsDashboardPort = 3100
 
 	// DefaultArgoRolloutsResourceName is the default name for Rollouts controller resources such as
 	// deployment, service, role, rolebinding and serviceaccount.
-	DefaultArgoRolloutsResourceName = "argo-rollouts"
+	DefaultArgoRolloutsResourceName = rolloutsmanagerv1alpha1.DefaultArgoRolloutsResourceName
 
 	// DefaultRolloutsNotificationSecretName is the default name for rollout controller secret resource.
 	DefaultRolloutsNotificationSecretName = "argo-rollouts-notification-secret" // #nosec G101
 
+	// DefaultRolloutsNotificationConfigMapName is the default name of the ConfigMap that contains the Rollouts notification engine configuration
+	DefaultRolloutsNotificationConfigMapName = "argo-rollouts-notification-configmap"
+
 	// DefaultRolloutsServiceSelectorKey is key used by selector
 	DefaultRolloutsSelectorKey = "app.kubernetes.io/name"
 
 	// OpenShiftRolloutPluginName is the plugin name for Openshift Route Plugin
-	OpenShiftRolloutPluginName = "argoproj-labs/openshift"
+	OpenShiftRolloutPluginName = rolloutsmanagerv1alpha1.OpenShiftRolloutPluginName
+
+	// NamespacedCommandArg is the Rollouts controller argument that is managed by the operator, based on .spec.namespaceScoped
+	NamespacedCommandArg = rolloutsmanagerv1alpha1.NamespacedCommandArg
+
+	// LeaderElectCommandArg is the Rollouts controller argument that enables leader election, set by .spec.leaderElection.enabled,
+	// and managed by the operator when more than one replica is requested
+	LeaderElectCommandArg = rolloutsmanagerv1alpha1.LeaderElectCommandArg
+
+	// LeaderElectionLeaseDurationCommandArg is the Rollouts controller argument set by .spec.leaderElection.leaseDuration
+	LeaderElectionLeaseDurationCommandArg = rolloutsmanagerv1alpha1.LeaderElectionLeaseDurationCommandArg
+
+	// LeaderElectionRenewDeadlineCommandArg is the Rollouts controller argument set by .spec.leaderElection.renewDeadline
+	LeaderElectionRenewDeadlineCommandArg = rolloutsmanagerv1alpha1.LeaderElectionRenewDeadlineCommandArg
+
+	// LeaderElectionRetryPeriodCommandArg is the Rollouts controller argument set by .spec.leaderElection.retryPeriod
+	LeaderElectionRetryPeriodCommandArg = rolloutsmanagerv1alpha1.LeaderElectionRetryPeriodCommandArg
+
+	// LogLevelCommandArg is the Rollouts controller argument set by .spec.logging.level
+	LogLevelCommandArg = rolloutsmanagerv1alpha1.LogLevelCommandArg
+
+	// LogFormatCommandArg is the Rollouts controller argument set by .spec.logging.format
+	LogFormatCommandArg = rolloutsmanagerv1alpha1.LogFormatCommandArg
+
+	// KlogLevelCommandArg is the Rollouts controller argument set by .spec.logging.klogLevel
+	KlogLevelCommandArg = rolloutsmanagerv1alpha1.KlogLevelCommandArg
+
+	// RolloutThreadsCommandArg is the Rollouts controller argument set by .spec.tuning.rolloutThreads
+	RolloutThreadsCommandArg = rolloutsmanagerv1alpha1.RolloutThreadsCommandArg
+
+	// AnalysisThreadsCommandArg is the Rollouts controller argument set by .spec.tuning.analysisThreads
+	AnalysisThreadsCommandArg = rolloutsmanagerv1alpha1.AnalysisThreadsCommandArg
+
+	// QPSCommandArg is the Rollouts controller argument set by .spec.tuning.qps
+	QPSCommandArg = rolloutsmanagerv1alpha1.QPSCommandArg
+
+	// BurstCommandArg is the Rollouts controller argument set by .spec.tuning.burst
+	BurstCommandArg = rolloutsmanagerv1alpha1.BurstCommandArg
+
+	// RolloutsContainerName is the name of the Argo Rollouts controller container, which is managed by the operator
+	RolloutsContainerName = rolloutsmanagerv1alpha1.RolloutsContainerName
+
+	// DefaultRolloutsDeploymentStrategyParameter is the value set by the Deployment API for the maxSurge and maxUnavailable parameters of a rolling update, when not specified
+	DefaultRolloutsDeploymentStrategyParameter = "25%"
 
 	// DefaultRolloutsConfigMapName is the default name of the ConfigMap that contains the Rollouts controller configuration
 	DefaultRolloutsConfigMapName = "argo-rollouts-config"
 
+	// DefaultPluginFetcherImage is the default image of the init container which downloads the plugins, when plugin prefetch is enabled.
+	DefaultPluginFetcherImage = "registry.access.redhat.com/ubi9/ubi-minimal:9.4"
+
+	// DefaultPluginInitContainerUserID is the user and group ID of the init containers which load the plugins: the images they use, such as
+	// DefaultPluginFetcherImage, may run as root by default, which runAsNonRoot would reject. It matches the user of the Argo Rollouts image.
+	DefaultPluginInitContainerUserID = 999
+
 	DefaultOpenShiftRoutePluginURL = "https://github.com/argoproj-labs/rollouts-plugin-trafficrouter-openshift/releases/download/commit-8d0b3c6c5c18341f9f019cf1015b56b0d0c6085b/rollouts-plugin-trafficrouter-openshift-linux-amd64"
 
 	// NamespaceScopedArgoRolloutsController is an environment variable that can be used to configure scope of Argo Rollouts controller
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"reflect"
+	"strings"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -13,10 +15,11 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
-func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount) appsv1.Deployment {
+func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount, openShiftRoutePluginLocation string) appsv1.Deployment {
 
 	// NOTE: When updating this function, ensure that normalizeDeployment is updated as well. See that function for details.
 
@@ -43,7 +46,10 @@ func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager
 		}
 	}
 
+	replicas := getRolloutsReplicas(cr)
+
 	desiredDeployment.Spec = appsv1.DeploymentSpec{
+		Replicas: &replicas,
 		Selector: &metav1.LabelSelector{
 			MatchLabels: labels,
 		},
@@ -58,19 +64,22 @@ func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager
 				},
 			},
 		},
-		Strategy: appsv1.DeploymentStrategy{
-			Type: appsv1.RollingUpdateDeploymentStrategyType,
-		},
+		Strategy: getRolloutsDeploymentStrategy(cr),
 	}
 
 	if cr.Spec.NodePlacement != nil {
 		desiredDeployment.Spec.Template.Spec.NodeSelector = appendStringMap(
 			desiredDeployment.Spec.Template.Spec.NodeSelector, cr.Spec.NodePlacement.NodeSelector)
 		desiredDeployment.Spec.Template.Spec.Tolerations = cr.Spec.NodePlacement.Tolerations
+		desiredDeployment.Spec.Template.Spec.TopologySpreadConstraints = cr.Spec.NodePlacement.TopologySpreadConstraints
+		desiredDeployment.Spec.Template.Spec.PriorityClassName = cr.Spec.NodePlacement.PriorityClassName
+		desiredDeployment.Spec.Template.Spec.RuntimeClassName = cr.Spec.NodePlacement.RuntimeClassName
 	}
 
 	desiredPodSpec := &desiredDeployment.Spec.Template.Spec
 
+	desiredPodSpec.Affinity = getRolloutsAffinity(cr, replicas)
+
 	runAsNonRoot := true
 	desiredPodSpec.SecurityContext = &corev1.PodSecurityContext{
 		RunAsNonRoot: &runAsNonRoot,
@@ -78,9 +87,20 @@ func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager
 
 	desiredPodSpec.ServiceAccountName = sa.ObjectMeta.Name
 
+	desiredPodSpec.ImagePullSecrets = cr.Spec.ImagePullSecrets
+
 	desiredPodSpec.Containers = []corev1.Container{
 		rolloutsContainer(cr),
 	}
+	// Extra containers, such as sidecars, follow the Argo Rollouts controller container.
+	desiredPodSpec.Containers = append(desiredPodSpec.Containers, cr.Spec.ExtraContainers...)
+
+	if isPluginPrefetchEnabled(cr) {
+		desiredPodSpec.InitContainers = []corev1.Container{
+			pluginFetcherContainer(cr, openShiftRoutePluginLocation),
+		}
+	}
+	desiredPodSpec.InitContainers = append(desiredPodSpec.InitContainers, pluginSourceInitContainers(cr)...)
 
 	desiredPodSpec.Volumes = []corev1.Volume{
 		{
@@ -96,6 +116,8 @@ func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager
 			},
 		},
 	}
+	desiredPodSpec.Volumes = append(desiredPodSpec.Volumes, pluginSourceVolumes(cr)...)
+	desiredPodSpec.Volumes = append(desiredPodSpec.Volumes, cr.Spec.ExtraVolumes...)
 
 	return desiredDeployment
 }
@@ -103,7 +125,7 @@ func generateDesiredRolloutsDeployment(cr rolloutsmanagerv1alpha1.RolloutManager
 // Reconcile the Rollouts controller deployment.
 func (r *RolloutManagerReconciler) reconcileRolloutsDeployment(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager, sa corev1.ServiceAccount) error {
 
-	desiredDeployment := generateDesiredRolloutsDeployment(cr, sa)
+	desiredDeployment := generateDesiredRolloutsDeployment(cr, sa, r.OpenShiftRoutePluginLocation)
 
 	normalizedDesiredDeployment, err := normalizeDeployment(desiredDeployment, cr)
 	if err != nil {
@@ -119,6 +141,17 @@ func (r *RolloutManagerReconciler) reconcileRolloutsDeployment(ctx context.Conte
 		// We intentionally continue without returning, as the error is non-fatal at runtime
 	}
 
+	// The overrides are applied after the consistency check above, since they may set fields which are not part of the normalized form.
+	overridesPatch, err := applyResourceOverrides(cr, "Deployment", &desiredDeployment)
+	if err != nil {
+		return err
+	}
+	if overridesPatch != nil {
+		if normalizedDesiredDeployment, err = normalizeDeployment(desiredDeployment, cr); err != nil {
+			return fmt.Errorf("unable to normalize the Deployment after applying .spec.overrides: %w", err)
+		}
+	}
+
 	// If the deployment for rollouts does not exist, create one.
 	actualDeployment := &appsv1.Deployment{}
 
@@ -130,11 +163,22 @@ func (r *RolloutManagerReconciler) reconcileRolloutsDeployment(ctx context.Conte
 		return r.createNewRolloutsDeployment(ctx, cr, desiredDeployment)
 	}
 
+	// A field set by an override, which is not part of the normalized form, was changed if applying the overrides again changes the Deployment.
+	overriddenFieldsChanged := false
+	if overridesPatch != nil {
+		if overriddenFieldsChanged, err = applyResourceOverridesToLiveObject(actualDeployment, actualDeployment.DeepCopy(), overridesPatch); err != nil {
+			return err
+		}
+	}
+
 	normalizedActualDeployment, err := normalizeDeployment(*actualDeployment, cr)
 
-	if err != nil || !reflect.DeepEqual(normalizedActualDeployment, normalizedDesiredDeployment) {
+	if err != nil || !reflect.DeepEqual(normalizedActualDeployment, normalizedDesiredDeployment) || overriddenFieldsChanged {
 
 		deploymentsDifferent := identifyDeploymentDifference(normalizedActualDeployment, normalizedDesiredDeployment)
+		if deploymentsDifferent == "" && overriddenFieldsChanged {
+			deploymentsDifferent = ".spec.overrides"
+		}
 
 		log.Info("updating Deployment due to detected difference: " + deploymentsDifferent)
 
@@ -147,6 +191,9 @@ func (r *RolloutManagerReconciler) reconcileRolloutsDeployment(ctx context.Conte
 				return fmt.Errorf("unable to delete Rollouts Deployment after .spec.selector change: %w", err)
 			}
 
+			r.recordEvent(&cr, corev1.EventTypeNormal, EventReasonDeploymentRecreated,
+				fmt.Sprintf("Deleted Deployment %s to recreate it, since its immutable .spec.selector has changed", desiredDeployment.Name))
+
 			return r.createNewRolloutsDeployment(ctx, cr, desiredDeployment)
 		}
 
@@ -155,9 +202,12 @@ func (r *RolloutManagerReconciler) reconcileRolloutsDeployment(ctx context.Conte
 			// this error is a warning, only. Continue.
 		}
 
+		actualDeployment.Spec.Replicas = desiredDeployment.Spec.Replicas
 		actualDeployment.Spec.Strategy = desiredDeployment.Spec.Strategy
 		actualDeployment.Spec.Template.Spec.Containers = desiredDeployment.Spec.Template.Spec.Containers
+		actualDeployment.Spec.Template.Spec.InitContainers = desiredDeployment.Spec.Template.Spec.InitContainers
 		actualDeployment.Spec.Template.Spec.ServiceAccountName = desiredDeployment.Spec.Template.Spec.ServiceAccountName
+		actualDeployment.Spec.Template.Spec.ImagePullSecrets = desiredDeployment.Spec.Template.Spec.ImagePullSecrets
 
 		actualDeployment.Labels = combineStringMaps(actualDeployment.Labels, desiredDeployment.Labels)
 		actualDeployment.Annotations = combineStringMaps(actualDeployment.Annotations, desiredDeployment.Annotations)
@@ -167,9 +217,21 @@ func (r *RolloutManagerReconciler) reconcileRolloutsDeployment(ctx context.Conte
 		actualDeployment.Spec.Selector = desiredDeployment.Spec.Selector
 		actualDeployment.Spec.Template.Spec.NodeSelector = desiredDeployment.Spec.Template.Spec.NodeSelector
 		actualDeployment.Spec.Template.Spec.Tolerations = desiredDeployment.Spec.Template.Spec.Tolerations
+		actualDeployment.Spec.Template.Spec.Affinity = desiredDeployment.Spec.Template.Spec.Affinity
+		actualDeployment.Spec.Template.Spec.TopologySpreadConstraints = desiredDeployment.Spec.Template.Spec.TopologySpreadConstraints
+		actualDeployment.Spec.Template.Spec.PriorityClassName = desiredDeployment.Spec.Template.Spec.PriorityClassName
+		actualDeployment.Spec.Template.Spec.RuntimeClassName = desiredDeployment.Spec.Template.Spec.RuntimeClassName
 		actualDeployment.Spec.Template.Spec.SecurityContext = desiredDeployment.Spec.Template.Spec.SecurityContext
 		actualDeployment.Spec.Template.Spec.Volumes = desiredDeployment.Spec.Template.Spec.Volumes
-		return r.Client.Update(ctx, actualDeployment)
+
+		// The overrides are applied last, so that the fields they set which are not copied above are also updated.
+		if overridesPatch != nil {
+			if err := applyResourceOverridesPatch(actualDeployment, overridesPatch); err != nil {
+				return err
+			}
+		}
+
+		return r.updateResource(ctx, &cr, actualDeployment)
 	}
 	return nil
 }
@@ -179,7 +241,7 @@ func (r *RolloutManagerReconciler) createNewRolloutsDeployment(ctx context.Conte
 		return err
 	}
 	log.Info(fmt.Sprintf("Creating Deployment %s", DefaultArgoRolloutsResourceName))
-	return r.Client.Create(ctx, &desiredDeployment)
+	return r.createResource(ctx, &cr, &desiredDeployment)
 }
 
 // identifyDeploymentDifference is a simple comparison of the contents of two deployments, returning "" if they are the same, otherwise returning the name of the field that changed.
@@ -192,10 +254,22 @@ func identifyDeploymentDifference(x appsv1.Deployment, y appsv1.Deployment) stri
 		return "Spec.Template.Spec.Containers"
 	}
 
+	if !reflect.DeepEqual(xPodSpec.InitContainers, yPodSpec.InitContainers) {
+		return "Spec.Template.Spec.InitContainers"
+	}
+
 	if xPodSpec.ServiceAccountName != yPodSpec.ServiceAccountName {
 		return "ServiceAccountName"
 	}
 
+	if !reflect.DeepEqual(xPodSpec.ImagePullSecrets, yPodSpec.ImagePullSecrets) {
+		return "Spec.Template.Spec.ImagePullSecrets"
+	}
+
+	if !reflect.DeepEqual(x.Spec.Replicas, y.Spec.Replicas) {
+		return ".Spec.Replicas"
+	}
+
 	if !reflect.DeepEqual(x.Spec.Strategy, y.Spec.Strategy) {
 		return ".Spec.Strategy"
 	}
@@ -228,6 +302,22 @@ func identifyDeploymentDifference(x appsv1.Deployment, y appsv1.Deployment) stri
 		return "Spec.Template.Spec.Tolerations"
 	}
 
+	if !reflect.DeepEqual(xPodSpec.Affinity, yPodSpec.Affinity) {
+		return "Spec.Template.Spec.Affinity"
+	}
+
+	if !reflect.DeepEqual(xPodSpec.TopologySpreadConstraints, yPodSpec.TopologySpreadConstraints) {
+		return "Spec.Template.Spec.TopologySpreadConstraints"
+	}
+
+	if xPodSpec.PriorityClassName != yPodSpec.PriorityClassName {
+		return "Spec.Template.Spec.PriorityClassName"
+	}
+
+	if !reflect.DeepEqual(xPodSpec.RuntimeClassName, yPodSpec.RuntimeClassName) {
+		return "Spec.Template.Spec.RuntimeClassName"
+	}
+
 	if !reflect.DeepEqual(xPodSpec.SecurityContext, yPodSpec.SecurityContext) {
 		return "Spec.Template.Spec.SecurityContext"
 	}
@@ -264,25 +354,29 @@ func rolloutsContainer(cr rolloutsmanagerv1alpha1.RolloutManager) corev1.Contain
 		containerResources = &defaultContainerResources
 	}
 
+	volumeMounts := []corev1.VolumeMount{
+		{
+			MountPath: "/home/argo-rollouts/plugin-bin",
+			Name:      "plugin-bin",
+		},
+		{
+			MountPath: "/tmp",
+			Name:      "tmp",
+		},
+	}
+	volumeMounts = append(volumeMounts, pluginSourceVolumeMounts(cr)...)
+	volumeMounts = append(volumeMounts, cr.Spec.ExtraVolumeMounts...)
+
+	livenessProbe, readinessProbe, startupProbe := getRolloutsContainerProbes(cr)
+
 	return corev1.Container{
 		Args:            getRolloutsCommandArgs(cr),
 		Env:             rolloutsEnv,
+		EnvFrom:         cr.Spec.EnvFrom,
 		Image:           getRolloutsContainerImage(cr),
-		ImagePullPolicy: corev1.PullAlways,
-		LivenessProbe: &corev1.Probe{
-			FailureThreshold: 3,
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path: "/healthz",
-					Port: intstr.FromString("healthz"),
-				},
-			},
-			InitialDelaySeconds: int32(30),
-			PeriodSeconds:       int32(20),
-			SuccessThreshold:    int32(1),
-			TimeoutSeconds:      int32(10),
-		},
-		Name: "argo-rollouts",
+		ImagePullPolicy: getRolloutsImagePullPolicy(cr),
+		LivenessProbe:   livenessProbe,
+		Name:            RolloutsContainerName,
 		Ports: []corev1.ContainerPort{
 			{
 				ContainerPort: 8080,
@@ -293,19 +387,8 @@ func rolloutsContainer(cr rolloutsmanagerv1alpha1.RolloutManager) corev1.Contain
 				Name:          "metrics",
 			},
 		},
-		ReadinessProbe: &corev1.Probe{
-			FailureThreshold: int32(5),
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path: "/metrics",
-					Port: intstr.FromString("metrics"),
-				},
-			},
-			InitialDelaySeconds: int32(10),
-			PeriodSeconds:       int32(5),
-			SuccessThreshold:    int32(1),
-			TimeoutSeconds:      int32(4),
-		},
+		ReadinessProbe: readinessProbe,
+		StartupProbe:   startupProbe,
 		SecurityContext: &corev1.SecurityContext{
 			Capabilities: &corev1.Capabilities{
 				Drop: []corev1.Capability{
@@ -319,19 +402,163 @@ func rolloutsContainer(cr rolloutsmanagerv1alpha1.RolloutManager) corev1.Contain
 				Type: corev1.SeccompProfileTypeRuntimeDefault,
 			},
 		},
-		VolumeMounts: []corev1.VolumeMount{
-			{
-				MountPath: "/home/argo-rollouts/plugin-bin",
-				Name:      "plugin-bin",
+		VolumeMounts: volumeMounts,
+		Resources:    *containerResources,
+	}
+
+}
+
+// getRolloutsDeploymentStrategy returns the strategy of the Deployment of the Argo Rollouts controller, from .spec.strategy.
+// The rolling update parameters are only set when specified, otherwise the defaults of the Deployment API apply.
+func getRolloutsDeploymentStrategy(cr rolloutsmanagerv1alpha1.RolloutManager) appsv1.DeploymentStrategy {
+	strategy := appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+	}
+
+	spec := cr.Spec.Strategy
+	if spec == nil {
+		return strategy
+	}
+
+	if spec.Type != "" {
+		strategy.Type = spec.Type
+	}
+
+	if strategy.Type == appsv1.RollingUpdateDeploymentStrategyType && (spec.MaxSurge != nil || spec.MaxUnavailable != nil) {
+		strategy.RollingUpdate = &appsv1.RollingUpdateDeployment{}
+		if spec.MaxSurge != nil {
+			maxSurge := *spec.MaxSurge
+			strategy.RollingUpdate.MaxSurge = &maxSurge
+		}
+		if spec.MaxUnavailable != nil {
+			maxUnavailable := *spec.MaxUnavailable
+			strategy.RollingUpdate.MaxUnavailable = &maxUnavailable
+		}
+	}
+
+	return strategy
+}
+
+// normalizeRolloutsDeploymentStrategy keeps the rolling update parameters of the strategy, so that changes to them are detected, but
+// ignores the default value which is set by the Deployment API for the parameters that are not specified in .spec.strategy.
+func normalizeRolloutsDeploymentStrategy(input appsv1.DeploymentStrategy, cr rolloutsmanagerv1alpha1.RolloutManager) appsv1.DeploymentStrategy {
+	res := appsv1.DeploymentStrategy{
+		Type: input.Type,
+	}
+
+	if input.RollingUpdate == nil {
+		return res
+	}
+
+	var specMaxSurge, specMaxUnavailable *intstr.IntOrString
+	if cr.Spec.Strategy != nil {
+		specMaxSurge, specMaxUnavailable = cr.Spec.Strategy.MaxSurge, cr.Spec.Strategy.MaxUnavailable
+	}
+
+	defaultValue := intstr.FromString(DefaultRolloutsDeploymentStrategyParameter)
+
+	maxSurge := input.RollingUpdate.MaxSurge
+	if maxSurge != nil && specMaxSurge == nil && *maxSurge == defaultValue {
+		maxSurge = nil
+	}
+
+	maxUnavailable := input.RollingUpdate.MaxUnavailable
+	if maxUnavailable != nil && specMaxUnavailable == nil && *maxUnavailable == defaultValue {
+		maxUnavailable = nil
+	}
+
+	if maxSurge != nil || maxUnavailable != nil {
+		res.RollingUpdate = &appsv1.RollingUpdateDeployment{
+			MaxSurge:       maxSurge,
+			MaxUnavailable: maxUnavailable,
+		}
+	}
+
+	return res
+}
+
+// getRolloutsContainerProbes returns the liveness, readiness and startup probes of the Argo Rollouts controller container, with the overrides of
+// .spec.probes applied. The startup probe is nil unless .spec.probes.startup is set.
+func getRolloutsContainerProbes(cr rolloutsmanagerv1alpha1.RolloutManager) (*corev1.Probe, *corev1.Probe, *corev1.Probe) {
+
+	livenessProbe := &corev1.Probe{
+		FailureThreshold: 3,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/healthz",
+				Port: intstr.FromString("healthz"),
 			},
-			{
-				MountPath: "/tmp",
-				Name:      "tmp",
+		},
+		InitialDelaySeconds: int32(30),
+		PeriodSeconds:       int32(20),
+		SuccessThreshold:    int32(1),
+		TimeoutSeconds:      int32(10),
+	}
+
+	readinessProbe := &corev1.Probe{
+		FailureThreshold: int32(5),
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: "/metrics",
+				Port: intstr.FromString("metrics"),
 			},
 		},
-		Resources: *containerResources,
+		InitialDelaySeconds: int32(10),
+		PeriodSeconds:       int32(5),
+		SuccessThreshold:    int32(1),
+		TimeoutSeconds:      int32(4),
+	}
+
+	probes := cr.Spec.Probes
+	if probes == nil {
+		return livenessProbe, readinessProbe, nil
 	}
 
+	applyRolloutsProbeSpec(livenessProbe, probes.Liveness)
+	applyRolloutsProbeSpec(readinessProbe, probes.Readiness)
+
+	var startupProbe *corev1.Probe
+	if probes.Startup != nil {
+		// By default, the controller is given 30 * 10 seconds to start
+		startupProbe = &corev1.Probe{
+			FailureThreshold: int32(30),
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/healthz",
+					Port: intstr.FromString("healthz"),
+				},
+			},
+			InitialDelaySeconds: int32(0),
+			PeriodSeconds:       int32(10),
+			SuccessThreshold:    int32(1),
+			TimeoutSeconds:      int32(10),
+		}
+		applyRolloutsProbeSpec(startupProbe, probes.Startup)
+	}
+
+	return livenessProbe, readinessProbe, startupProbe
+}
+
+// applyRolloutsProbeSpec overrides, in place, the timings and thresholds of the probe with those which are set in spec.
+func applyRolloutsProbeSpec(probe *corev1.Probe, spec *rolloutsmanagerv1alpha1.RolloutsProbeSpec) {
+	if spec == nil {
+		return
+	}
+	if spec.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *spec.InitialDelaySeconds
+	}
+	if spec.PeriodSeconds != nil {
+		probe.PeriodSeconds = *spec.PeriodSeconds
+	}
+	if spec.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *spec.TimeoutSeconds
+	}
+	if spec.SuccessThreshold != nil {
+		probe.SuccessThreshold = *spec.SuccessThreshold
+	}
+	if spec.FailureThreshold != nil {
+		probe.FailureThreshold = *spec.FailureThreshold
+	}
 }
 
 // One of the goals of an operator is to reconcile the live state of a resource on the cluster, with a target state for that resource. However, one of the challenges in doing so is that some fields of the resource will naturally differ from the values that are generated: for example, some field have default values which are only set after creation. This can make it challenging to compare the live/target status. Various strategies exist to handle.
@@ -373,6 +600,9 @@ func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha
 			delete(res.Annotations, k)
 		}
 	}
+	if res.Annotations == nil {
+		res.Annotations = map[string]string{}
+	}
 
 	if input.Spec.Selector == nil {
 		return appsv1.Deployment{}, fmt.Errorf("missing .spec.selector")
@@ -384,12 +614,24 @@ func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha
 		return appsv1.Deployment{}, fmt.Errorf("missing .spec.template.spec.securityContext")
 	}
 
-	inputSpecVolumes := input.Spec.Template.Spec.Volumes
-	if inputSpecVolumes == nil || len(inputSpecVolumes) != 2 {
+	// The plugin-bin and tmp volumes are always present, followed by the volumes of the plugin sources and the extra volumes, if any.
+	if len(input.Spec.Template.Spec.Volumes) < 2 {
 		return appsv1.Deployment{}, fmt.Errorf("missing .spec.template.spec.volumes")
 	}
 
+	inputSpecVolumes := make([]corev1.Volume, 0, len(input.Spec.Template.Spec.Volumes))
+	for _, volume := range input.Spec.Template.Spec.Volumes {
+		inputSpecVolumes = append(inputSpecVolumes, normalizeExtraVolume(volume, cr))
+	}
+
+	// A nil .spec.replicas is defaulted to 1 by the API server.
+	replicas := int32(1)
+	if input.Spec.Replicas != nil {
+		replicas = *input.Spec.Replicas
+	}
+
 	res.Spec = appsv1.DeploymentSpec{
+		Replicas: &replicas,
 		Selector: &metav1.LabelSelector{
 			MatchLabels: normalizeMap(input.Spec.Selector.MatchLabels),
 		},
@@ -399,22 +641,25 @@ func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha
 				Annotations: normalizeMap(input.Spec.Template.Annotations),
 			},
 			Spec: corev1.PodSpec{
-				NodeSelector:       input.Spec.Template.Spec.NodeSelector,
-				Tolerations:        input.Spec.Template.Spec.Tolerations,
-				ServiceAccountName: input.Spec.Template.Spec.ServiceAccountName,
+				NodeSelector:              input.Spec.Template.Spec.NodeSelector,
+				Tolerations:               input.Spec.Template.Spec.Tolerations,
+				Affinity:                  input.Spec.Template.Spec.Affinity,
+				TopologySpreadConstraints: input.Spec.Template.Spec.TopologySpreadConstraints,
+				PriorityClassName:         input.Spec.Template.Spec.PriorityClassName,
+				RuntimeClassName:          input.Spec.Template.Spec.RuntimeClassName,
+				ServiceAccountName:        input.Spec.Template.Spec.ServiceAccountName,
+				ImagePullSecrets:          input.Spec.Template.Spec.ImagePullSecrets,
 				SecurityContext: &corev1.PodSecurityContext{
 					RunAsNonRoot: input.Spec.Template.Spec.SecurityContext.RunAsNonRoot,
 				},
-				Volumes: []corev1.Volume{inputSpecVolumes[0], inputSpecVolumes[1]},
+				Volumes: inputSpecVolumes,
 			},
 		},
-		Strategy: appsv1.DeploymentStrategy{
-			Type: input.Spec.Strategy.Type,
-			// we ignore the default values set in RollingUpdate:
-		},
+		Strategy: normalizeRolloutsDeploymentStrategy(input.Spec.Strategy, cr),
 	}
 
-	if len(input.Spec.Template.Spec.Containers) != 1 {
+	// The Argo Rollouts controller container is always first, followed by the extra containers, if any.
+	if len(input.Spec.Template.Spec.Containers) < 1 {
 		return appsv1.Deployment{}, fmt.Errorf("incorrect number of .spec.template.spec.containers")
 	}
 
@@ -422,6 +667,7 @@ func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha
 	inputLivenessProbe := inputContainer.LivenessProbe
 	inputPorts := inputContainer.Ports
 	inputReadinessProbe := inputContainer.ReadinessProbe
+	inputStartupProbe := inputContainer.StartupProbe
 	inputSecurityContext := inputContainer.SecurityContext
 	inputVolumeMounts := inputContainer.VolumeMounts
 
@@ -441,6 +687,11 @@ func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha
 		return appsv1.Deployment{}, fmt.Errorf("incorrect http get in readiness probe")
 	}
 
+	// The startup probe is only present when .spec.probes.startup is set.
+	if inputStartupProbe != nil && inputStartupProbe.ProbeHandler.HTTPGet == nil {
+		return appsv1.Deployment{}, fmt.Errorf("incorrect http get in startup probe")
+	}
+
 	if inputPorts == nil || len(inputPorts) != 2 {
 		return appsv1.Deployment{}, fmt.Errorf("incorrect input ports")
 	}
@@ -449,10 +700,22 @@ func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha
 		return appsv1.Deployment{}, fmt.Errorf("incorrect security context")
 	}
 
-	if inputVolumeMounts == nil || len(inputVolumeMounts) != 2 {
+	if len(inputVolumeMounts) < 2 {
 		return appsv1.Deployment{}, fmt.Errorf("incorrect volume mounts")
 	}
 
+	normalizedVolumeMounts := make([]corev1.VolumeMount, 0, len(inputVolumeMounts))
+	for _, volumeMount := range inputVolumeMounts {
+		normalizedVolumeMounts = append(normalizedVolumeMounts, corev1.VolumeMount{
+			Name:             volumeMount.Name,
+			MountPath:        volumeMount.MountPath,
+			ReadOnly:         volumeMount.ReadOnly,
+			SubPath:          volumeMount.SubPath,
+			SubPathExpr:      volumeMount.SubPathExpr,
+			MountPropagation: volumeMount.MountPropagation,
+		})
+	}
+
 	// Nil string slices need to be converted to empty string slices, because  reflect.DeepEqual(nil, []string{}) is false, despite being functionally the same, here.
 	if len(inputContainer.Args) == 0 {
 		inputContainer.Args = make([]string, 0)
@@ -465,22 +728,11 @@ func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha
 	res.Spec.Template.Spec.Containers = []corev1.Container{{
 		Args:            inputContainer.Args,
 		Env:             inputContainer.Env,
+		EnvFrom:         inputContainer.EnvFrom,
 		Image:           inputContainer.Image,
 		ImagePullPolicy: inputContainer.ImagePullPolicy,
-		LivenessProbe: &corev1.Probe{
-			FailureThreshold: inputLivenessProbe.FailureThreshold,
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path: inputLivenessProbe.ProbeHandler.HTTPGet.Path,
-					Port: inputLivenessProbe.ProbeHandler.HTTPGet.Port,
-				},
-			},
-			InitialDelaySeconds: inputLivenessProbe.InitialDelaySeconds,
-			PeriodSeconds:       inputLivenessProbe.PeriodSeconds,
-			SuccessThreshold:    inputLivenessProbe.SuccessThreshold,
-			TimeoutSeconds:      inputLivenessProbe.TimeoutSeconds,
-		},
-		Name: inputContainer.Name,
+		LivenessProbe:   normalizeRolloutsContainerProbe(inputLivenessProbe),
+		Name:            inputContainer.Name,
 		Ports: []corev1.ContainerPort{
 			{
 				ContainerPort: inputPorts[0].ContainerPort,
@@ -491,20 +743,9 @@ func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha
 				Name:          inputPorts[1].Name,
 			},
 		},
-		ReadinessProbe: &corev1.Probe{
-			FailureThreshold: inputReadinessProbe.FailureThreshold,
-			ProbeHandler: corev1.ProbeHandler{
-				HTTPGet: &corev1.HTTPGetAction{
-					Path: inputReadinessProbe.ProbeHandler.HTTPGet.Path,
-					Port: inputReadinessProbe.ProbeHandler.HTTPGet.Port,
-				},
-			},
-			InitialDelaySeconds: inputReadinessProbe.InitialDelaySeconds,
-			PeriodSeconds:       inputReadinessProbe.PeriodSeconds,
-			SuccessThreshold:    inputReadinessProbe.SuccessThreshold,
-			TimeoutSeconds:      inputReadinessProbe.TimeoutSeconds,
-		},
-		Resources: inputContainer.Resources,
+		ReadinessProbe: normalizeRolloutsContainerProbe(inputReadinessProbe),
+		StartupProbe:   normalizeRolloutsContainerProbe(inputStartupProbe),
+		Resources:      inputContainer.Resources,
 		SecurityContext: &corev1.SecurityContext{
 			Capabilities: &corev1.Capabilities{
 				Drop: inputSecurityContext.Capabilities.Drop,
@@ -514,20 +755,194 @@ func normalizeDeployment(inputParam appsv1.Deployment, cr rolloutsmanagerv1alpha
 			RunAsNonRoot:             inputSecurityContext.RunAsNonRoot,
 			SeccompProfile:           inputSecurityContext.SeccompProfile,
 		},
-		VolumeMounts: []corev1.VolumeMount{
-			{
-				Name:      inputVolumeMounts[0].Name,
-				MountPath: inputVolumeMounts[0].MountPath,
+		VolumeMounts: normalizedVolumeMounts,
+	}}
+
+	for _, inputExtraContainer := range input.Spec.Template.Spec.Containers[1:] {
+		res.Spec.Template.Spec.Containers = append(res.Spec.Template.Spec.Containers, normalizeExtraContainer(inputExtraContainer, cr))
+	}
+
+	// The plugin fetcher init container is only present when plugin prefetch is enabled.
+	for _, inputInitContainer := range input.Spec.Template.Spec.InitContainers {
+		if inputInitContainer.SecurityContext == nil || inputInitContainer.SecurityContext.Capabilities == nil {
+			return appsv1.Deployment{}, fmt.Errorf("incorrect init container security context")
+		}
+
+		if len(inputInitContainer.Args) == 0 {
+			inputInitContainer.Args = make([]string, 0)
+		}
+
+		initContainerVolumeMounts := make([]corev1.VolumeMount, 0, len(inputInitContainer.VolumeMounts))
+		for _, volumeMount := range inputInitContainer.VolumeMounts {
+			initContainerVolumeMounts = append(initContainerVolumeMounts, corev1.VolumeMount{
+				Name:      volumeMount.Name,
+				MountPath: volumeMount.MountPath,
+			})
+		}
+
+		res.Spec.Template.Spec.InitContainers = append(res.Spec.Template.Spec.InitContainers, corev1.Container{
+			Name:            inputInitContainer.Name,
+			Image:           inputInitContainer.Image,
+			ImagePullPolicy: inputInitContainer.ImagePullPolicy,
+			Command:         inputInitContainer.Command,
+			Args:            inputInitContainer.Args,
+			Resources:       inputInitContainer.Resources,
+			SecurityContext: &corev1.SecurityContext{
+				Capabilities: &corev1.Capabilities{
+					Drop: inputInitContainer.SecurityContext.Capabilities.Drop,
+				},
+				AllowPrivilegeEscalation: inputInitContainer.SecurityContext.AllowPrivilegeEscalation,
+				ReadOnlyRootFilesystem:   inputInitContainer.SecurityContext.ReadOnlyRootFilesystem,
+				RunAsNonRoot:             inputInitContainer.SecurityContext.RunAsNonRoot,
+				RunAsUser:                inputInitContainer.SecurityContext.RunAsUser,
+				RunAsGroup:               inputInitContainer.SecurityContext.RunAsGroup,
+				SeccompProfile:           inputInitContainer.SecurityContext.SeccompProfile,
 			},
-			{
-				Name:      inputVolumeMounts[1].Name,
-				MountPath: inputVolumeMounts[1].MountPath,
+			VolumeMounts: initContainerVolumeMounts,
+		})
+	}
+
+	return res, nil
+
+}
+
+// normalizeExtraContainer removes the fields that are defaulted by the API server from an extra container of the Deployment, when they are not set
+// on the matching container of .spec.extraContainers. The extra containers are otherwise owned by the user, so every other field is kept, and compared.
+// A container which is not in .spec.extraContainers is returned as is.
+func normalizeExtraContainer(input corev1.Container, cr rolloutsmanagerv1alpha1.RolloutManager) corev1.Container {
+
+	var expected *corev1.Container
+	for idx := range cr.Spec.ExtraContainers {
+		if cr.Spec.ExtraContainers[idx].Name == input.Name {
+			expected = &cr.Spec.ExtraContainers[idx]
+			break
+		}
+	}
+	if expected == nil {
+		return input
+	}
+
+	res := *input.DeepCopy()
+
+	if expected.ImagePullPolicy == "" {
+		res.ImagePullPolicy = ""
+	}
+	if expected.TerminationMessagePath == "" {
+		res.TerminationMessagePath = ""
+	}
+	if expected.TerminationMessagePolicy == "" {
+		res.TerminationMessagePolicy = ""
+	}
+
+	if len(res.Ports) == len(expected.Ports) {
+		for idx := range res.Ports {
+			if expected.Ports[idx].Protocol == "" {
+				res.Ports[idx].Protocol = ""
+			}
+		}
+	}
+
+	if len(res.Env) == len(expected.Env) {
+		for idx := range res.Env {
+			valueFrom, expectedValueFrom := res.Env[idx].ValueFrom, expected.Env[idx].ValueFrom
+			if valueFrom != nil && valueFrom.FieldRef != nil && expectedValueFrom != nil && expectedValueFrom.FieldRef != nil && expectedValueFrom.FieldRef.APIVersion == "" {
+				valueFrom.FieldRef.APIVersion = ""
+			}
+		}
+	}
+
+	normalizeExtraContainerProbe(res.LivenessProbe, expected.LivenessProbe)
+	normalizeExtraContainerProbe(res.ReadinessProbe, expected.ReadinessProbe)
+	normalizeExtraContainerProbe(res.StartupProbe, expected.StartupProbe)
+
+	return res
+}
+
+// normalizeRolloutsContainerProbe returns the fields of an HTTP probe of the Argo Rollouts controller container which are set by the operator,
+// or nil if the probe is nil.
+func normalizeRolloutsContainerProbe(input *corev1.Probe) *corev1.Probe {
+	if input == nil {
+		return nil
+	}
+	return &corev1.Probe{
+		FailureThreshold: input.FailureThreshold,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: input.ProbeHandler.HTTPGet.Path,
+				Port: input.ProbeHandler.HTTPGet.Port,
 			},
 		},
-	}}
+		InitialDelaySeconds: input.InitialDelaySeconds,
+		PeriodSeconds:       input.PeriodSeconds,
+		SuccessThreshold:    input.SuccessThreshold,
+		TimeoutSeconds:      input.TimeoutSeconds,
+	}
+}
 
-	return res, nil
+// normalizeExtraContainerProbe removes, in place, the fields that are defaulted by the API server from a probe of an extra container, when they are not set on the expected probe.
+func normalizeExtraContainerProbe(input *corev1.Probe, expected *corev1.Probe) {
+	if input == nil || expected == nil {
+		return
+	}
+
+	if expected.TimeoutSeconds == 0 {
+		input.TimeoutSeconds = 0
+	}
+	if expected.PeriodSeconds == 0 {
+		input.PeriodSeconds = 0
+	}
+	if expected.SuccessThreshold == 0 {
+		input.SuccessThreshold = 0
+	}
+	if expected.FailureThreshold == 0 {
+		input.FailureThreshold = 0
+	}
+	if input.HTTPGet != nil && expected.HTTPGet != nil && expected.HTTPGet.Scheme == "" {
+		input.HTTPGet.Scheme = ""
+	}
+}
+
+// normalizeExtraVolume removes the fields that are defaulted by the API server from a volume of the Deployment, when they are not set on the matching
+// volume of .spec.extraVolumes. A volume which is not in .spec.extraVolumes, such as the volumes managed by the operator, is returned as is.
+func normalizeExtraVolume(input corev1.Volume, cr rolloutsmanagerv1alpha1.RolloutManager) corev1.Volume {
+
+	var expected *corev1.Volume
+	for idx := range cr.Spec.ExtraVolumes {
+		if cr.Spec.ExtraVolumes[idx].Name == input.Name {
+			expected = &cr.Spec.ExtraVolumes[idx]
+			break
+		}
+	}
+	if expected == nil {
+		return input
+	}
+
+	res := *input.DeepCopy()
+	source, expectedSource := &res.VolumeSource, expected.VolumeSource
+
+	// A volume without a source is defaulted to an emptyDir volume.
+	if reflect.DeepEqual(expectedSource, corev1.VolumeSource{}) && reflect.DeepEqual(*source, corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}) {
+		res.VolumeSource = corev1.VolumeSource{}
+		return res
+	}
+
+	if source.ConfigMap != nil && expectedSource.ConfigMap != nil && expectedSource.ConfigMap.DefaultMode == nil {
+		source.ConfigMap.DefaultMode = nil
+	}
+	if source.Secret != nil && expectedSource.Secret != nil && expectedSource.Secret.DefaultMode == nil {
+		source.Secret.DefaultMode = nil
+	}
+	if source.Projected != nil && expectedSource.Projected != nil && expectedSource.Projected.DefaultMode == nil {
+		source.Projected.DefaultMode = nil
+	}
+	if source.DownwardAPI != nil && expectedSource.DownwardAPI != nil && expectedSource.DownwardAPI.DefaultMode == nil {
+		source.DownwardAPI.DefaultMode = nil
+	}
+	if source.HostPath != nil && expectedSource.HostPath != nil && expectedSource.HostPath.Type == nil {
+		source.HostPath.Type = nil
+	}
 
+	return res
 }
 
 // Confirm nil maps to empty map, to allow them to be compared by reflect.DeepEqual()
@@ -538,11 +953,83 @@ func normalizeMap(in map[string]string) map[string]string {
 	return in
 }
 
+// getRolloutsReplicas returns the number of Rollouts controller replicas requested by the RolloutManager, defaulting to 1.
+func getRolloutsReplicas(cr rolloutsmanagerv1alpha1.RolloutManager) int32 {
+	if cr.Spec.Replicas == nil {
+		return 1
+	}
+	return *cr.Spec.Replicas
+}
+
+// getRolloutsAffinity returns the affinity of the NodePlacement of the RolloutManager, if any. When more than one replica is requested,
+// the pod anti-affinity which spreads the controller pods across nodes is added to it, so that draining a single node does not take down every replica.
+func getRolloutsAffinity(cr rolloutsmanagerv1alpha1.RolloutManager, replicas int32) *corev1.Affinity {
+
+	var affinity *corev1.Affinity
+	if cr.Spec.NodePlacement != nil && cr.Spec.NodePlacement.Affinity != nil {
+		affinity = cr.Spec.NodePlacement.Affinity.DeepCopy()
+	}
+
+	if replicas <= 1 {
+		return affinity
+	}
+
+	haAffinity := getRolloutsPodAntiAffinity()
+	if affinity == nil {
+		return haAffinity
+	}
+
+	if affinity.PodAntiAffinity == nil {
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+
+	for _, haTerm := range haAffinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+		exists := false
+		for _, term := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			if reflect.DeepEqual(term, haTerm) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, haTerm)
+		}
+	}
+
+	return affinity
+}
+
+// getRolloutsPodAntiAffinity returns a preferred pod anti-affinity that spreads the Rollouts controller pods across nodes.
+func getRolloutsPodAntiAffinity() *corev1.Affinity {
+	return &corev1.Affinity{
+		PodAntiAffinity: &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+				{
+					Weight: 100,
+					PodAffinityTerm: corev1.PodAffinityTerm{
+						LabelSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName,
+							},
+						},
+						TopologyKey: "kubernetes.io/hostname",
+					},
+				},
+			},
+		},
+	}
+}
+
 // boolPtr returns a pointer to val
 func boolPtr(val bool) *bool {
 	return &val
 }
 
+// int64Ptr returns a pointer to val
+func int64Ptr(val int64) *int64 {
+	return &val
+}
+
 // Returns the container image for rollouts controller.
 func getRolloutsContainerImage(cr rolloutsmanagerv1alpha1.RolloutManager) string {
 	defaultImg, defaultTag := false, false
@@ -567,20 +1054,214 @@ func getRolloutsContainerImage(cr rolloutsmanagerv1alpha1.RolloutManager) string
 	return combineImageTag(img, tag)
 }
 
+// getRolloutsImagePullPolicy returns the pull policy of the Rollouts controller container, which defaults to Always.
+func getRolloutsImagePullPolicy(cr rolloutsmanagerv1alpha1.RolloutManager) corev1.PullPolicy {
+	if cr.Spec.ImagePullPolicy != "" {
+		return cr.Spec.ImagePullPolicy
+	}
+	return corev1.PullAlways
+}
+
 // getRolloutsCommand will return the command for the Rollouts controller component.
 func getRolloutsCommandArgs(cr rolloutsmanagerv1alpha1.RolloutManager) []string {
 	args := make([]string, 0)
 
 	if cr.Spec.NamespaceScoped {
-		args = append(args, "--namespaced")
+		args = append(args, NamespacedCommandArg)
 	}
 
-	extraArgs := cr.Spec.ExtraCommandArgs
-	err := isMergable(extraArgs, args)
-	if err != nil {
-		return args
+	leaderElection := cr.Spec.LeaderElection
+
+	// Leader election is always enabled when running more than one replica, otherwise the upstream default is used unless set explicitly.
+	if getRolloutsReplicas(cr) > 1 {
+		args = append(args, LeaderElectCommandArg+"=true")
+	} else if leaderElection != nil && leaderElection.Enabled != nil {
+		args = append(args, fmt.Sprintf("%s=%t", LeaderElectCommandArg, *leaderElection.Enabled))
+	}
+
+	if leaderElection != nil {
+		if leaderElection.LeaseDuration != nil {
+			args = append(args, LeaderElectionLeaseDurationCommandArg, leaderElection.LeaseDuration.Duration.String())
+		}
+		if leaderElection.RenewDeadline != nil {
+			args = append(args, LeaderElectionRenewDeadlineCommandArg, leaderElection.RenewDeadline.Duration.String())
+		}
+		if leaderElection.RetryPeriod != nil {
+			args = append(args, LeaderElectionRetryPeriodCommandArg, leaderElection.RetryPeriod.Duration.String())
+		}
+	}
+
+	if logging := cr.Spec.Logging; logging != nil {
+		if logging.Level != "" {
+			args = append(args, LogLevelCommandArg, logging.Level)
+		}
+		if logging.Format != "" {
+			args = append(args, LogFormatCommandArg, logging.Format)
+		}
+		if logging.KlogLevel != nil {
+			args = append(args, KlogLevelCommandArg, fmt.Sprint(*logging.KlogLevel))
+		}
+	}
+
+	if tuning := cr.Spec.Tuning; tuning != nil {
+		if tuning.RolloutThreads != nil {
+			args = append(args, RolloutThreadsCommandArg, fmt.Sprint(*tuning.RolloutThreads))
+		}
+		if tuning.AnalysisThreads != nil {
+			args = append(args, AnalysisThreadsCommandArg, fmt.Sprint(*tuning.AnalysisThreads))
+		}
+		if tuning.QPS != nil {
+			args = append(args, QPSCommandArg, fmt.Sprint(*tuning.QPS))
+		}
+		if tuning.Burst != nil {
+			args = append(args, BurstCommandArg, fmt.Sprint(*tuning.Burst))
+		}
 	}
 
-	args = append(args, extraArgs...)
-	return args
+	// The extra arguments take precedence over the flags set by the operator: those which the operator must own, or which are set by
+	// another field of the RolloutManager, are rejected by validateRolloutsExtraCommandArgs.
+	return mergeCommandArgs(args, cr.Spec.ExtraCommandArgs)
+}
+
+// UnsupportedControllerConfiguration is the prefix of the errors returned when the logging or tuning configuration of the RolloutManager is invalid.
+const UnsupportedControllerConfiguration = "invalid controller configuration"
+
+// validateRolloutsControllerConfiguration verifies the logging and tuning configuration of the RolloutManager.
+func validateRolloutsControllerConfiguration(cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	if logging := cr.Spec.Logging; logging != nil {
+		if logging.Level != "" {
+			if !contains([]string{"debug", "info", "warn", "error"}, logging.Level) {
+				return validationFailure(fmt.Errorf("%s: log level %s is not one of debug, info, warn or error", UnsupportedControllerConfiguration, logging.Level))
+			}
+		}
+		if logging.Format != "" {
+			if !contains([]string{"text", "json"}, logging.Format) {
+				return validationFailure(fmt.Errorf("%s: log format %s is not one of text or json", UnsupportedControllerConfiguration, logging.Format))
+			}
+		}
+		if logging.KlogLevel != nil {
+			if *logging.KlogLevel < 0 {
+				return validationFailure(fmt.Errorf("%s: klog level must not be negative, but is %d", UnsupportedControllerConfiguration, *logging.KlogLevel))
+			}
+		}
+	}
+
+	if tuning := cr.Spec.Tuning; tuning != nil {
+		positiveFields := []struct {
+			value *int32
+			field string
+		}{
+			{tuning.RolloutThreads, ".spec.tuning.rolloutThreads"},
+			{tuning.AnalysisThreads, ".spec.tuning.analysisThreads"},
+			{tuning.QPS, ".spec.tuning.qps"},
+			{tuning.Burst, ".spec.tuning.burst"},
+		}
+		for _, positiveField := range positiveFields {
+			if positiveField.value == nil {
+				continue
+			}
+			if *positiveField.value < 1 {
+				return validationFailure(fmt.Errorf("%s: %s must be at least 1, but is %d", UnsupportedControllerConfiguration, positiveField.field, *positiveField.value))
+			}
+		}
+
+		if tuning.QPS != nil && tuning.Burst != nil && *tuning.Burst < *tuning.QPS {
+			return validationFailure(fmt.Errorf("%s: burst (%d) must not be lower than qps (%d)", UnsupportedControllerConfiguration, *tuning.Burst, *tuning.QPS))
+		}
+	}
+
+	return nil, nil
+}
+
+func invalidControllerConfiguration(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), UnsupportedControllerConfiguration)
+}
+
+// UnsupportedExtraCommandArgs is the prefix of the errors returned when .spec.extraCommandArgs of the RolloutManager conflicts with the
+// flags set by the operator.
+const UnsupportedExtraCommandArgs = "invalid extra command arguments"
+
+// validateRolloutsExtraCommandArgs verifies that .spec.extraCommandArgs of the RolloutManager can be merged into the Rollouts controller command:
+// it must not set the flags owned by the operator, nor those set by another field of the RolloutManager, nor set a flag more than once.
+// The other flags of the extra arguments take precedence over those set by the operator, see mergeCommandArgs.
+func validateRolloutsExtraCommandArgs(cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	if errs := rolloutsmanagerv1alpha1.ValidateExtraCommandArgs(field.NewPath("spec", "extraCommandArgs"), cr.Spec); len(errs) > 0 {
+		return fieldValidationFailure(UnsupportedExtraCommandArgs, errs)
+	}
+
+	return nil, nil
+}
+
+func invalidExtraCommandArgs(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), UnsupportedExtraCommandArgs)
+}
+
+// UnsupportedPodConfiguration is the prefix of the errors returned when the extra containers, volumes or volume mounts of the RolloutManager are invalid.
+const UnsupportedPodConfiguration = "invalid pod configuration"
+
+// validateRolloutsPodConfiguration verifies that the extra containers, volumes and volume mounts of the RolloutManager do not collide with those managed
+// by the operator, or with each other, that every volume mount refers to a volume of the pod, and that the probes of the Argo Rollouts controller container are valid.
+func validateRolloutsPodConfiguration(cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	specPath := field.NewPath("spec")
+	if errs := rolloutsmanagerv1alpha1.ValidateExtraContainers(specPath.Child("extraContainers"), cr.Spec.ExtraContainers); len(errs) > 0 {
+		return fieldValidationFailure(UnsupportedPodConfiguration, errs)
+	}
+	if errs := rolloutsmanagerv1alpha1.ValidateExtraVolumes(specPath.Child("extraVolumes"), cr.Spec.ExtraVolumes); len(errs) > 0 {
+		return fieldValidationFailure(UnsupportedPodConfiguration, errs)
+	}
+
+	// The mount paths of the Argo Rollouts controller container that are managed by the operator.
+	operatorMountPaths := []string{"/home/argo-rollouts/plugin-bin", "/tmp"}
+
+	volumeNames := map[string]bool{}
+	for _, volume := range cr.Spec.ExtraVolumes {
+		volumeNames[volume.Name] = true
+	}
+
+	mountPaths := map[string]bool{}
+	for _, volumeMount := range cr.Spec.ExtraVolumeMounts {
+		if !volumeNames[volumeMount.Name] {
+			return validationFailure(fmt.Errorf("%s: extra volume mount %s refers to a volume that is not in .spec.extraVolumes", UnsupportedPodConfiguration, volumeMount.Name))
+		}
+
+		mountPath := path.Clean(volumeMount.MountPath)
+		for _, operatorMountPath := range operatorMountPaths {
+			if mountPath == operatorMountPath || strings.HasPrefix(mountPath, operatorMountPath+"/") {
+				return validationFailure(fmt.Errorf("%s: the path %s of extra volume mount %s collides with the path %s, which is managed by the operator", UnsupportedPodConfiguration, volumeMount.MountPath, volumeMount.Name, operatorMountPath))
+			}
+		}
+		if mountPaths[mountPath] {
+			return validationFailure(fmt.Errorf("%s: the path %s is mounted more than once", UnsupportedPodConfiguration, volumeMount.MountPath))
+		}
+		mountPaths[mountPath] = true
+	}
+
+	// The extra containers may also mount the volumes managed by the operator, such as tmp.
+	operatorVolumeNames := map[string]bool{rolloutsmanagerv1alpha1.PluginBinVolumeName: true, rolloutsmanagerv1alpha1.TmpVolumeName: true}
+	for _, container := range cr.Spec.ExtraContainers {
+		for _, volumeMount := range container.VolumeMounts {
+			if !operatorVolumeNames[volumeMount.Name] && !volumeNames[volumeMount.Name] {
+				return validationFailure(fmt.Errorf("%s: volume mount %s of extra container %s refers to a volume that is not in .spec.extraVolumes", UnsupportedPodConfiguration, volumeMount.Name, container.Name))
+			}
+		}
+	}
+
+	// Kubernetes requires a success threshold of 1 for liveness and startup probes.
+	if probes := cr.Spec.Probes; probes != nil {
+		if probes.Liveness != nil && probes.Liveness.SuccessThreshold != nil && *probes.Liveness.SuccessThreshold != 1 {
+			return validationFailure(fmt.Errorf("%s: the success threshold of the liveness probe must be 1", UnsupportedPodConfiguration))
+		}
+		if probes.Startup != nil && probes.Startup.SuccessThreshold != nil && *probes.Startup.SuccessThreshold != 1 {
+			return validationFailure(fmt.Errorf("%s: the success threshold of the startup probe must be 1", UnsupportedPodConfiguration))
+		}
+	}
+
+	return nil, nil
+}
+
+func invalidPodConfiguration(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), UnsupportedPodConfiguration)
 }
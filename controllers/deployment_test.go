@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"time"
 
 	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 
@@ -15,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -104,6 +106,27 @@ var _ = Describe("Deployment Test", func() {
 		})
 	})
 
+	When("the image pull policy or image pull secrets of the RolloutManager change", func() {
+		It("should update the Deployment", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			a.Spec.ImagePullPolicy = corev1.PullIfNotPresent
+			a.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "my-mirror-credentials"}}
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			fetchedDeployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, fetchedDeployment)).To(Succeed())
+			Expect(fetchedDeployment.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+			Expect(fetchedDeployment.Spec.Template.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "my-mirror-credentials"}}))
+
+			By("removing the image pull secrets")
+			a.Spec.ImagePullSecrets = nil
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, fetchedDeployment)).To(Succeed())
+			Expect(fetchedDeployment.Spec.Template.Spec.ImagePullSecrets).To(BeEmpty())
+		})
+	})
+
 	When("RolloutManagerCR has custom controller resources defined", func() {
 
 		It("should create a Deployment that uses those controller resources", func() {
@@ -387,6 +410,12 @@ var _ = Describe("Deployment Test", func() {
 			Entry(".spec.template.spec.serviceAccountName", func(deployment *appsv1.Deployment) {
 				deployment.Spec.Template.Spec.ServiceAccountName = "different-service-account-name"
 			}),
+			Entry(".spec.template.spec.imagePullSecrets", func(deployment *appsv1.Deployment) {
+				deployment.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "my-pull-secret"}}
+			}),
+			Entry(".spec.template.spec.containers.imagePullPolicy", func(deployment *appsv1.Deployment) {
+				deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy = corev1.PullNever
+			}),
 			Entry(".spec.template.labels", func(deployment *appsv1.Deployment) {
 				deployment.Spec.Template.Labels = map[string]string{"new": "label"}
 			}),
@@ -404,6 +433,24 @@ var _ = Describe("Deployment Test", func() {
 			Entry(".spec.template.spec.volumes", func(deployment *appsv1.Deployment) {
 				deployment.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: "my-volume"}}
 			}),
+			Entry("spec.replicas", func(deployment *appsv1.Deployment) {
+				deployment.Spec.Replicas = int32Ptr(5)
+			}),
+			Entry(".spec.template.spec.affinity", func(deployment *appsv1.Deployment) {
+				deployment.Spec.Template.Spec.Affinity = getRolloutsPodAntiAffinity()
+			}),
+			Entry(".spec.template.spec.topologySpreadConstraints", func(deployment *appsv1.Deployment) {
+				deployment.Spec.Template.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{
+					MaxSkew: 1, TopologyKey: "topology.kubernetes.io/zone", WhenUnsatisfiable: corev1.DoNotSchedule,
+				}}
+			}),
+			Entry(".spec.template.spec.priorityClassName", func(deployment *appsv1.Deployment) {
+				deployment.Spec.Template.Spec.PriorityClassName = "system-cluster-critical"
+			}),
+			Entry(".spec.template.spec.runtimeClassName", func(deployment *appsv1.Deployment) {
+				runtimeClassName := "gvisor"
+				deployment.Spec.Template.Spec.RuntimeClassName = &runtimeClassName
+			}),
 			Entry(".spec.template.spec.containers.resources", func(deployment *appsv1.Deployment) {
 				deployment.Spec.Template.Spec.Containers[0].Resources = corev1.ResourceRequirements{
 					Requests: corev1.ResourceList{
@@ -416,10 +463,219 @@ var _ = Describe("Deployment Test", func() {
 					},
 				}
 			}),
+			Entry(".spec.template.spec.containers, with an added container", func(deployment *appsv1.Deployment) {
+				deployment.Spec.Template.Spec.Containers = append(deployment.Spec.Template.Spec.Containers, corev1.Container{Name: "sidecar", Image: "quay.io/my/sidecar:v1"})
+			}),
+			Entry(".spec.template.spec.volumes, with an added volume", func(deployment *appsv1.Deployment) {
+				deployment.Spec.Template.Spec.Volumes = append(deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+					Name: "my-volume", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				})
+			}),
 		)
 
 	})
 
+	When("the RolloutManager has extra containers, volumes and volume mounts", func() {
+
+		BeforeEach(func() {
+			a.Spec.ExtraContainers = []corev1.Container{{
+				Name:  "sidecar",
+				Image: "quay.io/my/sidecar:v1",
+				Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 9000}},
+				Env: []corev1.EnvVar{{
+					Name:      "POD_NAME",
+					ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"}},
+				}},
+				ReadinessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/ready", Port: intstr.FromString("http")}}},
+				VolumeMounts:   []corev1.VolumeMount{{Name: "sidecar-config", MountPath: "/etc/sidecar"}, {Name: "tmp", MountPath: "/tmp"}},
+			}}
+			a.Spec.ExtraVolumes = []corev1.Volume{
+				{Name: "sidecar-config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "sidecar-config"}}}},
+				{Name: "ca-bundle", VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "ca-bundle"}}},
+			}
+			a.Spec.ExtraVolumeMounts = []corev1.VolumeMount{{Name: "ca-bundle", MountPath: "/etc/ssl/certs/custom", ReadOnly: true}}
+		})
+
+		It("should not report a difference for the fields that are defaulted by the API server", func() {
+			desired := generateDesiredRolloutsDeployment(a, *sa, r.OpenShiftRoutePluginLocation)
+
+			By("setting the default values of the API server on a copy of the Deployment")
+			live := desired.DeepCopy()
+			defaultMode := int32(0644)
+			sidecar := &live.Spec.Template.Spec.Containers[1]
+			sidecar.ImagePullPolicy = corev1.PullIfNotPresent
+			sidecar.TerminationMessagePath = corev1.TerminationMessagePathDefault
+			sidecar.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+			sidecar.Ports[0].Protocol = corev1.ProtocolTCP
+			sidecar.Env[0].ValueFrom.FieldRef.APIVersion = "v1"
+			sidecar.ReadinessProbe.TimeoutSeconds = 1
+			sidecar.ReadinessProbe.PeriodSeconds = 10
+			sidecar.ReadinessProbe.SuccessThreshold = 1
+			sidecar.ReadinessProbe.FailureThreshold = 3
+			sidecar.ReadinessProbe.HTTPGet.Scheme = corev1.URISchemeHTTP
+			live.Spec.Template.Spec.Volumes[2].ConfigMap.DefaultMode = &defaultMode
+			live.Spec.Template.Spec.Volumes[3].Secret.DefaultMode = &defaultMode
+
+			Expect(identifyDeploymentDifference(desired, *live)).ToNot(BeEmpty())
+
+			normalizedDesired, err := normalizeDeployment(desired, a)
+			Expect(err).ToNot(HaveOccurred())
+			normalizedLive, err := normalizeDeployment(*live, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalizedLive).To(Equal(normalizedDesired))
+		})
+
+		It("should revert changes to the extra containers and volumes, and to the Argo Rollouts controller container", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(2))
+
+			By("modifying the extra container, the extra volume and the Argo Rollouts controller container")
+			deployment.Spec.Template.Spec.Containers[0].Args = []string{"--loglevel", "debug"}
+			deployment.Spec.Template.Spec.Containers[1].Image = "quay.io/my/sidecar:v2"
+			deployment.Spec.Template.Spec.Volumes[3].Secret.SecretName = "other-ca-bundle"
+			Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(BeEmpty())
+			Expect(deployment.Spec.Template.Spec.Containers[1].Image).To(Equal("quay.io/my/sidecar:v1"))
+			Expect(deployment.Spec.Template.Spec.Volumes[3].Secret.SecretName).To(Equal("ca-bundle"))
+
+			By("removing the extra container from the RolloutManager")
+			a.Spec.ExtraContainers = nil
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers).To(HaveLen(1))
+			Expect(deployment.Spec.Template.Spec.Containers[0].Name).To(Equal(RolloutsContainerName))
+		})
+	})
+
+	When("the RolloutManager overrides the probes of the Argo Rollouts controller container", func() {
+
+		BeforeEach(func() {
+			a.Spec.Probes = &v1alpha1.RolloutsProbesSpec{
+				Liveness:  &v1alpha1.RolloutsProbeSpec{InitialDelaySeconds: int32Ptr(60), FailureThreshold: int32Ptr(6)},
+				Readiness: &v1alpha1.RolloutsProbeSpec{TimeoutSeconds: int32Ptr(8)},
+				Startup:   &v1alpha1.RolloutsProbeSpec{FailureThreshold: int32Ptr(60)},
+			}
+		})
+
+		It("should apply the overrides, add the startup probe, and revert changes to the probes", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.LivenessProbe.InitialDelaySeconds).To(Equal(int32(60)))
+			Expect(container.LivenessProbe.FailureThreshold).To(Equal(int32(6)))
+			Expect(container.LivenessProbe.PeriodSeconds).To(Equal(int32(20)))
+			Expect(container.ReadinessProbe.TimeoutSeconds).To(Equal(int32(8)))
+			Expect(container.ReadinessProbe.FailureThreshold).To(Equal(int32(5)))
+			Expect(container.StartupProbe).ToNot(BeNil())
+			Expect(container.StartupProbe.HTTPGet.Path).To(Equal("/healthz"))
+			Expect(container.StartupProbe.FailureThreshold).To(Equal(int32(60)))
+			Expect(container.StartupProbe.PeriodSeconds).To(Equal(int32(10)))
+
+			By("modifying the probes of the Deployment")
+			deployment.Spec.Template.Spec.Containers[0].LivenessProbe.InitialDelaySeconds = 5
+			deployment.Spec.Template.Spec.Containers[0].StartupProbe = nil
+			Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[0].LivenessProbe.InitialDelaySeconds).To(Equal(int32(60)))
+			Expect(deployment.Spec.Template.Spec.Containers[0].StartupProbe).ToNot(BeNil())
+
+			By("removing the probe overrides from the RolloutManager")
+			a.Spec.Probes = nil
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Template.Spec.Containers[0].LivenessProbe.InitialDelaySeconds).To(Equal(int32(30)))
+			Expect(deployment.Spec.Template.Spec.Containers[0].StartupProbe).To(BeNil())
+		})
+
+		It("should not report a difference for the HTTP scheme defaulted by the API server on the startup probe", func() {
+			desired := generateDesiredRolloutsDeployment(a, *sa, r.OpenShiftRoutePluginLocation)
+
+			live := desired.DeepCopy()
+			live.Spec.Template.Spec.Containers[0].StartupProbe.HTTPGet.Scheme = corev1.URISchemeHTTP
+
+			normalizedDesired, err := normalizeDeployment(desired, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalizedDesired).To(Equal(desired))
+			normalizedLive, err := normalizeDeployment(*live, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalizedLive).To(Equal(normalizedDesired))
+		})
+	})
+
+	When("the RolloutManager configures the strategy of the Deployment", func() {
+
+		BeforeEach(func() {
+			a.Spec.Strategy = &v1alpha1.RolloutsDeploymentStrategySpec{
+				MaxSurge: intOrStringPtr(intstr.FromInt(0)),
+			}
+		})
+
+		It("should set the rolling update parameters, revert changes to them, and switch to the Recreate strategy", func() {
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			deployment := &appsv1.Deployment{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RollingUpdateDeploymentStrategyType))
+			Expect(deployment.Spec.Strategy.RollingUpdate.MaxSurge).To(Equal(intOrStringPtr(intstr.FromInt(0))))
+			Expect(deployment.Spec.Strategy.RollingUpdate.MaxUnavailable).To(BeNil())
+
+			By("modifying the rolling update parameters of the Deployment")
+			deployment.Spec.Strategy.RollingUpdate.MaxSurge = intOrStringPtr(intstr.FromInt(1))
+			Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Strategy.RollingUpdate.MaxSurge).To(Equal(intOrStringPtr(intstr.FromInt(0))))
+
+			By("switching the RolloutManager to the Recreate strategy")
+			a.Spec.Strategy = &v1alpha1.RolloutsDeploymentStrategySpec{Type: appsv1.RecreateDeploymentStrategyType}
+			Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+			Expect(deployment.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+			Expect(deployment.Spec.Strategy.RollingUpdate).To(BeNil())
+		})
+
+		It("should not report a difference for the rolling update parameters defaulted by the API server", func() {
+			desired := generateDesiredRolloutsDeployment(a, *sa, r.OpenShiftRoutePluginLocation)
+
+			live := desired.DeepCopy()
+			live.Spec.Strategy.RollingUpdate.MaxUnavailable = intOrStringPtr(intstr.FromString(DefaultRolloutsDeploymentStrategyParameter))
+
+			normalizedDesired, err := normalizeDeployment(desired, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalizedDesired).To(Equal(desired))
+			normalizedLive, err := normalizeDeployment(*live, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalizedLive).To(Equal(normalizedDesired))
+
+			By("reporting a difference once the parameter is set to another value in the RolloutManager")
+			a.Spec.Strategy.MaxUnavailable = intOrStringPtr(intstr.FromString("50%"))
+
+			normalizedDesired, err = normalizeDeployment(generateDesiredRolloutsDeployment(a, *sa, r.OpenShiftRoutePluginLocation), a)
+			Expect(err).ToNot(HaveOccurred())
+			normalizedLive, err = normalizeDeployment(*live, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identifyDeploymentDifference(normalizedDesired, normalizedLive)).To(Equal(".Spec.Strategy"))
+		})
+	})
+
 })
 
 var _ = Describe("generateDesiredRolloutsDeployment tests", func() {
@@ -466,7 +722,7 @@ var _ = Describe("generateDesiredRolloutsDeployment tests", func() {
 
 	Context("when generating the desired deployment", func() {
 		It("should set the correct metadata on the deployment", func() {
-			deployment := generateDesiredRolloutsDeployment(cr, sa)
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
 			Expect(deployment.ObjectMeta.Name).To(Equal(DefaultArgoRolloutsResourceName))
 			Expect(deployment.ObjectMeta.Namespace).To(Equal(cr.Namespace))
 
@@ -476,7 +732,7 @@ var _ = Describe("generateDesiredRolloutsDeployment tests", func() {
 		})
 
 		It("should set the NodeSelector and tolerations if NodePlacement is provided", func() {
-			deployment := generateDesiredRolloutsDeployment(cr, sa)
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
 			Expect(deployment.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux", "key1": "value1"}))
 			Expect(deployment.Spec.Template.Spec.Tolerations).To(ContainElement(corev1.Toleration{
 				Key:      "key1",
@@ -484,20 +740,126 @@ var _ = Describe("generateDesiredRolloutsDeployment tests", func() {
 			}))
 		})
 
+		It("should set the affinity, topology spread constraints, priority class and runtime class if NodePlacement is provided", func() {
+			runtimeClassName := "gvisor"
+			nodeAffinity := &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "node-role.kubernetes.io/infra",
+							Operator: corev1.NodeSelectorOpExists,
+						}},
+					}},
+				},
+			}
+			topologySpreadConstraints := []corev1.TopologySpreadConstraint{{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName},
+				},
+			}}
+			cr.Spec.NodePlacement.Affinity = &corev1.Affinity{NodeAffinity: nodeAffinity}
+			cr.Spec.NodePlacement.TopologySpreadConstraints = topologySpreadConstraints
+			cr.Spec.NodePlacement.PriorityClassName = "system-cluster-critical"
+			cr.Spec.NodePlacement.RuntimeClassName = &runtimeClassName
+
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			podSpec := deployment.Spec.Template.Spec
+			Expect(podSpec.Affinity).To(Equal(&corev1.Affinity{NodeAffinity: nodeAffinity}))
+			Expect(podSpec.TopologySpreadConstraints).To(Equal(topologySpreadConstraints))
+			Expect(podSpec.PriorityClassName).To(Equal("system-cluster-critical"))
+			Expect(podSpec.RuntimeClassName).To(Equal(&runtimeClassName))
+
+			normalized, err := normalizeDeployment(deployment, cr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalized).To(Equal(deployment), "normalizeDeployment should be consistent with generateDesiredRolloutsDeployment")
+
+			By("requesting more than one replica, the pod anti-affinity should be merged with the affinity of the NodePlacement")
+			cr.Spec.Replicas = int32Ptr(2)
+			deployment = generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Template.Spec.Affinity).To(Equal(&corev1.Affinity{
+				NodeAffinity:    nodeAffinity,
+				PodAntiAffinity: getRolloutsPodAntiAffinity().PodAntiAffinity,
+			}))
+			Expect(cr.Spec.NodePlacement.Affinity.PodAntiAffinity).To(BeNil(), "the affinity of the RolloutManager should not be modified")
+		})
+
+		It("should keep the pod anti-affinity terms of the NodePlacement, and not duplicate the one added for more than one replica", func() {
+			userTerm := corev1.WeightedPodAffinityTerm{
+				Weight: 50,
+				PodAffinityTerm: corev1.PodAffinityTerm{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName}},
+					TopologyKey:   "topology.kubernetes.io/zone",
+				},
+			}
+			haTerm := getRolloutsPodAntiAffinity().PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0]
+			cr.Spec.Replicas = int32Ptr(2)
+			cr.Spec.NodePlacement.Affinity = &corev1.Affinity{
+				PodAntiAffinity: &corev1.PodAntiAffinity{
+					PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{userTerm},
+				},
+			}
+
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(Equal([]corev1.WeightedPodAffinityTerm{userTerm, haTerm}))
+
+			cr.Spec.NodePlacement.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = []corev1.WeightedPodAffinityTerm{haTerm}
+			deployment = generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(Equal([]corev1.WeightedPodAffinityTerm{haTerm}))
+		})
+
 		It("should set the default node selector if NodePlacement is not provided", func() {
 			cr.Spec.NodePlacement = nil
-			deployment := generateDesiredRolloutsDeployment(cr, sa)
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
 			Expect(deployment.Spec.Template.Spec.NodeSelector).To(Equal(map[string]string{"kubernetes.io/os": "linux"}))
 			Expect(deployment.Spec.Template.Spec.Tolerations).To(BeNil())
 		})
 
 		It("should set the service account name", func() {
-			deployment := generateDesiredRolloutsDeployment(cr, sa)
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
 			Expect(deployment.Spec.Template.Spec.ServiceAccountName).To(Equal(sa.ObjectMeta.Name))
 		})
 
+		It("should default to the Always pull policy, without image pull secrets", func() {
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullAlways))
+			Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(BeEmpty())
+		})
+
+		It("should set the image pull policy and image pull secrets of the RolloutManager", func() {
+			cr.Spec.ImagePullPolicy = corev1.PullIfNotPresent
+			cr.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "my-mirror-credentials"}}
+
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Template.Spec.Containers[0].ImagePullPolicy).To(Equal(corev1.PullIfNotPresent))
+			Expect(deployment.Spec.Template.Spec.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "my-mirror-credentials"}}))
+
+			normalized, err := normalizeDeployment(deployment, cr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalized).To(Equal(deployment), "normalizeDeployment should be consistent with generateDesiredRolloutsDeployment")
+		})
+
+		It("should default to a single replica without pod anti-affinity", func() {
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Replicas).To(Equal(int32Ptr(1)))
+			Expect(deployment.Spec.Template.Spec.Affinity).To(BeNil())
+		})
+
+		It("should set replicas and pod anti-affinity when more than one replica is requested", func() {
+			cr.Spec.Replicas = int32Ptr(3)
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Replicas).To(Equal(int32Ptr(3)))
+			Expect(deployment.Spec.Template.Spec.Affinity).To(Equal(getRolloutsPodAntiAffinity()))
+
+			normalized, err := normalizeDeployment(deployment, cr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalized).To(Equal(deployment), "normalizeDeployment should be consistent with generateDesiredRolloutsDeployment")
+		})
+
 		It("should add the correct volumes", func() {
-			deployment := generateDesiredRolloutsDeployment(cr, sa)
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
 			Expect(deployment.Spec.Template.Spec.Volumes).To(HaveLen(2))
 			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
 				Name: "plugin-bin",
@@ -512,9 +874,228 @@ var _ = Describe("generateDesiredRolloutsDeployment tests", func() {
 				},
 			}))
 		})
+
+		It("should not add an init container when plugin prefetch is not enabled", func() {
+			cr.Spec.Plugins.Metric = []v1alpha1.Plugin{{Name: "argoproj-labs/sample-prometheus", Location: "https://example.com/prometheus"}}
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Template.Spec.InitContainers).To(BeEmpty())
+		})
+
+		It("should add the plugin fetcher init container when plugin prefetch is enabled", func() {
+			cr.Spec.Plugins = v1alpha1.Plugins{
+				TrafficManagement: []v1alpha1.Plugin{
+					{Name: "argoproj-labs/gatewayAPI", Location: "https://example.com/gatewayapi", SHA256: "abc"},
+					{Name: "argoproj-labs/local", Location: "file:///tmp/local-plugin"},
+				},
+				Metric: []v1alpha1.Plugin{
+					{Name: "argoproj-labs/sample-prometheus", Location: "http://example.com/prometheus"},
+					{Name: "argoproj-labs/sample-prometheus", Location: "http://example.com/duplicate"},
+				},
+				Prefetch: &v1alpha1.PluginPrefetchSpec{Enabled: true},
+			}
+
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Template.Spec.InitContainers).To(HaveLen(1))
+
+			initContainer := deployment.Spec.Template.Spec.InitContainers[0]
+			Expect(initContainer.Name).To(Equal(PluginFetcherContainerName))
+			Expect(initContainer.Image).To(Equal(DefaultPluginFetcherImage))
+			Expect(initContainer.Args).To(Equal([]string{
+				OpenShiftRolloutPluginName, DefaultOpenShiftRoutePluginURL, "",
+				"argoproj-labs/gatewayAPI", "https://example.com/gatewayapi", "abc",
+				"argoproj-labs/sample-prometheus", "http://example.com/prometheus", "",
+			}))
+			Expect(initContainer.VolumeMounts).To(Equal([]corev1.VolumeMount{{Name: "plugin-bin", MountPath: "/home/argo-rollouts/plugin-bin"}}))
+
+			By("verifying that the init container runs as a non-root user, since the default image runs as root")
+			userID := int64(DefaultPluginInitContainerUserID)
+			Expect(initContainer.SecurityContext).To(Equal(&corev1.SecurityContext{
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				AllowPrivilegeEscalation: boolPtr(false),
+				ReadOnlyRootFilesystem:   boolPtr(true),
+				RunAsNonRoot:             boolPtr(true),
+				RunAsUser:                &userID,
+				RunAsGroup:               &userID,
+				SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			}))
+
+			normalized, err := normalizeDeployment(deployment, cr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalized).To(Equal(deployment), "normalizeDeployment should be consistent with generateDesiredRolloutsDeployment")
+
+			By("using the image from the RolloutManager")
+			cr.Spec.Plugins.Prefetch.Image = "quay.io/my/fetcher:v1"
+			deployment = generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			Expect(deployment.Spec.Template.Spec.InitContainers[0].Image).To(Equal("quay.io/my/fetcher:v1"))
+		})
+
+		It("should add the volumes, volume mounts and init containers of the plugin sources", func() {
+			imagePlugin := v1alpha1.Plugin{Name: "argoproj-labs/gatewayAPI", Source: &v1alpha1.PluginSource{
+				Image: &v1alpha1.PluginImageSource{Reference: "quay.io/my-org/gatewayapi:v0.4.0", Path: "/bin/gatewayapi-plugin"},
+			}}
+			pvcPlugin := v1alpha1.Plugin{Name: "argoproj-labs/sample-prometheus", Source: &v1alpha1.PluginSource{
+				PersistentVolumeClaim: &v1alpha1.PluginVolumeSource{Name: "plugins", Path: "/metric/prometheus-plugin"},
+			}}
+			configMapPlugin := v1alpha1.Plugin{Name: "argoproj-labs/sample-step", Source: &v1alpha1.PluginSource{
+				ConfigMap: &v1alpha1.PluginVolumeSource{Name: "step-plugin", Path: "step-plugin"},
+			}}
+			secretPlugin := v1alpha1.Plugin{Name: "argoproj-labs/secret-step", Source: &v1alpha1.PluginSource{
+				Secret: &v1alpha1.PluginVolumeSource{Name: "secret-step-plugin", Path: "plugin"},
+			}}
+			cr.Spec.Plugins = v1alpha1.Plugins{
+				TrafficManagement: []v1alpha1.Plugin{imagePlugin},
+				Metric:            []v1alpha1.Plugin{pvcPlugin, {Name: "argoproj-labs/http", Location: "https://example.com/http"}},
+				Step:              []v1alpha1.Plugin{secretPlugin, configMapPlugin},
+			}
+
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			podSpec := deployment.Spec.Template.Spec
+
+			executableMode := int32(0555)
+			Expect(podSpec.Volumes).To(HaveLen(6))
+			Expect(podSpec.Volumes[2:]).To(Equal([]corev1.Volume{
+				{
+					Name:         getPluginSourceName(imagePlugin),
+					VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+				},
+				{
+					Name: getPluginSourceName(pvcPlugin),
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: "plugins",
+						ReadOnly:  true,
+					}},
+				},
+				{
+					Name: getPluginSourceName(configMapPlugin),
+					VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "step-plugin"},
+						Items:                []corev1.KeyToPath{{Key: "step-plugin", Path: "step-plugin"}},
+						DefaultMode:          &executableMode,
+					}},
+				},
+				{
+					Name: getPluginSourceName(secretPlugin),
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+						SecretName:  "secret-step-plugin",
+						Items:       []corev1.KeyToPath{{Key: "plugin", Path: "plugin"}},
+						DefaultMode: &executableMode,
+					}},
+				},
+			}))
+
+			volumeMounts := podSpec.Containers[0].VolumeMounts
+			Expect(volumeMounts).To(HaveLen(6))
+			for i, volume := range podSpec.Volumes[2:] {
+				Expect(volumeMounts[i+2]).To(Equal(corev1.VolumeMount{Name: volume.Name, MountPath: PluginSourcesDirectory + "/" + volume.Name, ReadOnly: true}))
+			}
+
+			Expect(podSpec.InitContainers).To(HaveLen(1))
+			Expect(podSpec.InitContainers[0].Name).To(Equal(getPluginSourceName(imagePlugin)))
+			Expect(podSpec.InitContainers[0].Image).To(Equal("quay.io/my-org/gatewayapi:v0.4.0"))
+			Expect(podSpec.InitContainers[0].Command).To(Equal([]string{"cp", "/bin/gatewayapi-plugin", getPluginSourceMountPath(imagePlugin) + "/gatewayapi-plugin"}))
+			Expect(podSpec.InitContainers[0].VolumeMounts).To(Equal([]corev1.VolumeMount{{Name: getPluginSourceName(imagePlugin), MountPath: getPluginSourceMountPath(imagePlugin)}}))
+			Expect(podSpec.InitContainers[0].SecurityContext).To(Equal(pluginInitContainerSecurityContext()))
+
+			normalized, err := normalizeDeployment(deployment, cr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalized).To(Equal(deployment), "normalizeDeployment should be consistent with generateDesiredRolloutsDeployment")
+		})
+
+		It("should add the extra containers, volumes and volume mounts after those of the operator", func() {
+			cr.Spec.ExtraContainers = []corev1.Container{{Name: "sidecar", Image: "quay.io/my/sidecar:v1"}}
+			cr.Spec.ExtraVolumes = []corev1.Volume{{Name: "config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}}
+			cr.Spec.ExtraVolumeMounts = []corev1.VolumeMount{{Name: "config", MountPath: "/etc/config", SubPath: "rollouts"}}
+
+			deployment := generateDesiredRolloutsDeployment(cr, sa, DefaultOpenShiftRoutePluginURL)
+			podSpec := deployment.Spec.Template.Spec
+
+			Expect(podSpec.Containers).To(HaveLen(2))
+			Expect(podSpec.Containers[0].Name).To(Equal(RolloutsContainerName))
+			Expect(podSpec.Containers[1]).To(Equal(cr.Spec.ExtraContainers[0]))
+			Expect(podSpec.Volumes).To(HaveLen(3))
+			Expect(podSpec.Volumes[2]).To(Equal(cr.Spec.ExtraVolumes[0]))
+			Expect(podSpec.Containers[0].VolumeMounts).To(HaveLen(3))
+			Expect(podSpec.Containers[0].VolumeMounts[2]).To(Equal(cr.Spec.ExtraVolumeMounts[0]))
+
+			normalized, err := normalizeDeployment(deployment, cr)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(normalized).To(Equal(deployment), "normalizeDeployment should be consistent with generateDesiredRolloutsDeployment")
+		})
 	})
 })
 
+var _ = Describe("validateRolloutsPodConfiguration tests", func() {
+
+	DescribeTable("should validate the extra containers, volumes and volume mounts", func(spec v1alpha1.RolloutManagerSpec, expectedErr string) {
+		cr := *makeTestRolloutManager()
+		cr.Spec.ExtraContainers = spec.ExtraContainers
+		cr.Spec.ExtraVolumes = spec.ExtraVolumes
+		cr.Spec.ExtraVolumeMounts = spec.ExtraVolumeMounts
+		cr.Spec.Probes = spec.Probes
+
+		rr, err := validateRolloutsPodConfiguration(cr)
+		if expectedErr == "" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rr).To(BeNil())
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(expectedErr))
+		Expect(invalidPodConfiguration(err)).To(BeTrue())
+		Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+		Expect(*rr.rolloutController).To(Equal(v1alpha1.PhaseFailure))
+	},
+		Entry("nothing is set", v1alpha1.RolloutManagerSpec{}, ""),
+		Entry("valid extra containers, volumes and volume mounts", v1alpha1.RolloutManagerSpec{
+			ExtraContainers:   []corev1.Container{{Name: "sidecar", VolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/etc/config"}, {Name: "tmp", MountPath: "/tmp"}}}},
+			ExtraVolumes:      []corev1.Volume{{Name: "config"}},
+			ExtraVolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/etc/config"}},
+		}, ""),
+		Entry("extra container named like the Argo Rollouts controller container", v1alpha1.RolloutManagerSpec{
+			ExtraContainers: []corev1.Container{{Name: RolloutsContainerName}},
+		}, "the name of extra container argo-rollouts is reserved by the operator"),
+		Entry("extra container named like a plugin source init container", v1alpha1.RolloutManagerSpec{
+			ExtraContainers: []corev1.Container{{Name: PluginSourceNamePrefix + "a"}},
+		}, "the name of extra container plugin-source-a is reserved by the operator"),
+		Entry("duplicate extra container", v1alpha1.RolloutManagerSpec{
+			ExtraContainers: []corev1.Container{{Name: "sidecar"}, {Name: "sidecar"}},
+		}, "extra container sidecar is defined more than once"),
+		Entry("extra volume named like a volume of the operator", v1alpha1.RolloutManagerSpec{
+			ExtraVolumes: []corev1.Volume{{Name: "plugin-bin"}},
+		}, "the name of extra volume plugin-bin is reserved by the operator"),
+		Entry("duplicate extra volume", v1alpha1.RolloutManagerSpec{
+			ExtraVolumes: []corev1.Volume{{Name: "config"}, {Name: "config"}},
+		}, "extra volume config is defined more than once"),
+		Entry("extra volume mount of an unknown volume", v1alpha1.RolloutManagerSpec{
+			ExtraVolumeMounts: []corev1.VolumeMount{{Name: "tmp", MountPath: "/etc/config"}},
+		}, "extra volume mount tmp refers to a volume that is not in .spec.extraVolumes"),
+		Entry("extra volume mount under the plugin-bin directory", v1alpha1.RolloutManagerSpec{
+			ExtraVolumes:      []corev1.Volume{{Name: "config"}},
+			ExtraVolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/home/argo-rollouts/plugin-bin/config/"}},
+		}, "collides with the path /home/argo-rollouts/plugin-bin"),
+		Entry("extra volume mounts with the same path", v1alpha1.RolloutManagerSpec{
+			ExtraVolumes:      []corev1.Volume{{Name: "config"}, {Name: "other"}},
+			ExtraVolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/etc/config"}, {Name: "other", MountPath: "/etc/config"}},
+		}, "the path /etc/config is mounted more than once"),
+		Entry("extra container volume mount of an unknown volume", v1alpha1.RolloutManagerSpec{
+			ExtraContainers: []corev1.Container{{Name: "sidecar", VolumeMounts: []corev1.VolumeMount{{Name: "config", MountPath: "/etc/config"}}}},
+		}, "volume mount config of extra container sidecar refers to a volume that is not in .spec.extraVolumes"),
+		Entry("valid probes", v1alpha1.RolloutManagerSpec{
+			Probes: &v1alpha1.RolloutsProbesSpec{
+				Liveness:  &v1alpha1.RolloutsProbeSpec{SuccessThreshold: int32Ptr(1)},
+				Readiness: &v1alpha1.RolloutsProbeSpec{SuccessThreshold: int32Ptr(3)},
+			},
+		}, ""),
+		Entry("liveness probe with a success threshold other than 1", v1alpha1.RolloutManagerSpec{
+			Probes: &v1alpha1.RolloutsProbesSpec{Liveness: &v1alpha1.RolloutsProbeSpec{SuccessThreshold: int32Ptr(2)}},
+		}, "the success threshold of the liveness probe must be 1"),
+		Entry("startup probe with a success threshold other than 1", v1alpha1.RolloutManagerSpec{
+			Probes: &v1alpha1.RolloutsProbesSpec{Startup: &v1alpha1.RolloutsProbeSpec{SuccessThreshold: int32Ptr(2)}},
+		}, "the success threshold of the startup probe must be 1"),
+	)
+})
+
 var _ = Describe("normalizeDeployment tests to verify that an error is returned", func() {
 	var (
 		a          v1alpha1.RolloutManager
@@ -554,11 +1135,8 @@ var _ = Describe("normalizeDeployment tests to verify that an error is returned"
 			}
 		}, "missing .spec.template.spec.volumes"),
 
-		Entry("spec.template.spec.containers has incorrect length", func() {
-			deployment.Spec.Template.Spec.Containers = []corev1.Container{
-				{Name: "test-1"},
-				{Name: "test-2"},
-			}
+		Entry("spec.template.spec.containers is empty", func() {
+			deployment.Spec.Template.Spec.Containers = nil
 		}, "incorrect number of .spec.template.spec.containers"),
 
 		Entry("liveness probe is nil", func() {
@@ -577,6 +1155,10 @@ var _ = Describe("normalizeDeployment tests to verify that an error is returned"
 			deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.ProbeHandler.HTTPGet = nil
 		}, "incorrect http get in readiness probe"),
 
+		Entry("startup probe http get is nil", func() {
+			deployment.Spec.Template.Spec.Containers[0].StartupProbe = &corev1.Probe{ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{}}}
+		}, "incorrect http get in startup probe"),
+
 		Entry("input ports is nil", func() {
 			deployment.Spec.Template.Spec.Containers[0].Ports = nil
 		}, "incorrect input ports"),
@@ -669,6 +1251,146 @@ var _ = Describe("getRolloutsContainerImage tests", func() {
 	})
 })
 
+var _ = Describe("getRolloutsCommandArgs tests", func() {
+
+	DescribeTable("should add the leader election arguments", func(spec v1alpha1.RolloutManagerSpec, expectedArgs []string) {
+		cr := v1alpha1.RolloutManager{Spec: spec}
+		Expect(getRolloutsCommandArgs(cr)).To(Equal(expectedArgs))
+	},
+		Entry("default spec", v1alpha1.RolloutManagerSpec{}, []string{}),
+		Entry("multiple replicas", v1alpha1.RolloutManagerSpec{
+			Replicas: int32Ptr(2),
+		}, []string{"--leader-elect=true"}),
+		Entry("single replica with leader election disabled", v1alpha1.RolloutManagerSpec{
+			LeaderElection: &v1alpha1.RolloutsLeaderElectionSpec{Enabled: boolPtr(false)},
+		}, []string{"--leader-elect=false"}),
+		Entry("multiple replicas with leader election timings", v1alpha1.RolloutManagerSpec{
+			NamespaceScoped: true,
+			Replicas:        int32Ptr(2),
+			LeaderElection: &v1alpha1.RolloutsLeaderElectionSpec{
+				LeaseDuration: &metav1.Duration{Duration: 30 * time.Second},
+				RenewDeadline: &metav1.Duration{Duration: 20 * time.Second},
+				RetryPeriod:   &metav1.Duration{Duration: 5 * time.Second},
+			},
+		}, []string{"--namespaced", "--leader-elect=true",
+			"--leader-election-lease-duration", "30s",
+			"--leader-election-renew-deadline", "20s",
+			"--leader-election-retry-period", "5s"}),
+		Entry("logging and tuning", v1alpha1.RolloutManagerSpec{
+			Logging: &v1alpha1.RolloutsLoggingSpec{Level: "debug", Format: "json", KlogLevel: int32Ptr(4)},
+			Tuning: &v1alpha1.RolloutsTuningSpec{
+				RolloutThreads:  int32Ptr(20),
+				AnalysisThreads: int32Ptr(15),
+				QPS:             int32Ptr(50),
+				Burst:           int32Ptr(100),
+			},
+			ExtraCommandArgs: []string{"--experiment-threads", "5"},
+		}, []string{"--loglevel", "debug", "--logformat", "json", "--kloglevel", "4",
+			"--rollout-threads", "20", "--analysis-threads", "15", "--qps", "50", "--burst", "100",
+			"--experiment-threads", "5"}),
+		Entry("extra command arguments alongside leader election", v1alpha1.RolloutManagerSpec{
+			LeaderElection: &v1alpha1.RolloutsLeaderElectionSpec{
+				Enabled: boolPtr(true),
+			},
+			ExtraCommandArgs: []string{"--leader-election-lease-duration=1m", "--loglevel", "debug"},
+		}, []string{"--leader-elect=true", "--leader-election-lease-duration=1m", "--loglevel", "debug"}),
+	)
+})
+
+var _ = Describe("validateRolloutsControllerConfiguration tests", func() {
+
+	DescribeTable("should validate the logging and tuning configuration", func(spec v1alpha1.RolloutManagerSpec, expectedErr string) {
+		cr := *makeTestRolloutManager()
+		cr.Spec.Logging = spec.Logging
+		cr.Spec.Tuning = spec.Tuning
+
+		rr, err := validateRolloutsControllerConfiguration(cr)
+		if expectedErr == "" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rr).To(BeNil())
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(expectedErr))
+		Expect(invalidControllerConfiguration(err)).To(BeTrue())
+		Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+		Expect(*rr.rolloutController).To(Equal(v1alpha1.PhaseFailure))
+	},
+		Entry("nothing is set", v1alpha1.RolloutManagerSpec{}, ""),
+		Entry("valid logging and tuning", v1alpha1.RolloutManagerSpec{
+			Logging: &v1alpha1.RolloutsLoggingSpec{Level: "warn", Format: "text", KlogLevel: int32Ptr(0)},
+			Tuning:  &v1alpha1.RolloutsTuningSpec{RolloutThreads: int32Ptr(1), QPS: int32Ptr(40), Burst: int32Ptr(40)},
+		}, ""),
+		Entry("unknown log level", v1alpha1.RolloutManagerSpec{
+			Logging: &v1alpha1.RolloutsLoggingSpec{Level: "trace"},
+		}, "log level trace is not one of debug, info, warn or error"),
+		Entry("unknown log format", v1alpha1.RolloutManagerSpec{
+			Logging: &v1alpha1.RolloutsLoggingSpec{Format: "xml"},
+		}, "log format xml is not one of text or json"),
+		Entry("negative klog level", v1alpha1.RolloutManagerSpec{
+			Logging: &v1alpha1.RolloutsLoggingSpec{KlogLevel: int32Ptr(-1)},
+		}, "klog level must not be negative"),
+		Entry("no rollout threads", v1alpha1.RolloutManagerSpec{
+			Tuning: &v1alpha1.RolloutsTuningSpec{RolloutThreads: int32Ptr(0)},
+		}, ".spec.tuning.rolloutThreads must be at least 1"),
+		Entry("burst lower than qps", v1alpha1.RolloutManagerSpec{
+			Tuning: &v1alpha1.RolloutsTuningSpec{QPS: int32Ptr(50), Burst: int32Ptr(20)},
+		}, "burst (20) must not be lower than qps (50)"),
+	)
+})
+
+var _ = Describe("validateRolloutsExtraCommandArgs tests", func() {
+
+	DescribeTable("should validate the extra command arguments", func(spec v1alpha1.RolloutManagerSpec, expectedErr string) {
+		cr := *makeTestRolloutManager()
+		cr.Spec.Logging = spec.Logging
+		cr.Spec.Tuning = spec.Tuning
+		cr.Spec.Replicas = spec.Replicas
+		cr.Spec.ExtraCommandArgs = spec.ExtraCommandArgs
+
+		rr, err := validateRolloutsExtraCommandArgs(cr)
+		if expectedErr == "" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rr).To(BeNil())
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(expectedErr))
+		Expect(invalidExtraCommandArgs(err)).To(BeTrue())
+		Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+		Expect(*rr.rolloutController).To(Equal(v1alpha1.PhaseFailure))
+	},
+		Entry("nothing is set", v1alpha1.RolloutManagerSpec{}, ""),
+		Entry("extra command arguments which do not conflict", v1alpha1.RolloutManagerSpec{
+			Logging:          &v1alpha1.RolloutsLoggingSpec{Level: "debug"},
+			ExtraCommandArgs: []string{"--experiment-threads", "5", "--leader-elect=false", "--leader-election-lease-duration", "30s"},
+		}, ""),
+		Entry("argument which does not follow a flag", v1alpha1.RolloutManagerSpec{
+			ExtraCommandArgs: []string{"debug", "--loglevel"},
+		}, "debug does not follow a flag"),
+		Entry("flag owned by the operator", v1alpha1.RolloutManagerSpec{
+			ExtraCommandArgs: []string{"--namespaced=false"},
+		}, "--namespaced is owned by the operator"),
+		Entry("leader election with multiple replicas", v1alpha1.RolloutManagerSpec{
+			Replicas:         int32Ptr(2),
+			ExtraCommandArgs: []string{"--leader-elect", "false"},
+		}, "--leader-elect is owned by the operator when more than one replica is requested"),
+		Entry("log level also set by .spec.logging", v1alpha1.RolloutManagerSpec{
+			Logging:          &v1alpha1.RolloutsLoggingSpec{Level: "debug"},
+			ExtraCommandArgs: []string{"--loglevel", "info"},
+		}, "--loglevel is set by .spec.logging.level, and must not also be set in .spec.extraCommandArgs"),
+		Entry("qps also set by .spec.tuning", v1alpha1.RolloutManagerSpec{
+			Tuning:           &v1alpha1.RolloutsTuningSpec{QPS: int32Ptr(50)},
+			ExtraCommandArgs: []string{"--qps=10"},
+		}, "--qps is set by .spec.tuning.qps"),
+		Entry("flag set more than once", v1alpha1.RolloutManagerSpec{
+			ExtraCommandArgs: []string{"--rollout-threads", "5", "--rollout-threads=10"},
+		}, "--rollout-threads is set more than once"),
+	)
+})
+
 var _ = Describe("rolloutsContainer tests", func() {
 	It("should include HTTP_PROXY, HTTPS_PROXY, and NO_PROXY environment variables in the container", func() {
 		By("Set environment variables")
@@ -728,7 +1450,9 @@ func deploymentCR(name string, namespace string, rolloutsSelectorLabel string, v
 		},
 	}
 	setRolloutsLabelsAndAnnotationsToObject(&deploymentCR.ObjectMeta, rolloutManager)
+	replicas := getRolloutsReplicas(rolloutManager)
 	deploymentCR.Spec = appsv1.DeploymentSpec{
+		Replicas: &replicas,
 		Selector: &metav1.LabelSelector{
 			MatchLabels: map[string]string{
 				DefaultRolloutsSelectorKey: rolloutsSelectorLabel,
@@ -0,0 +1,127 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// MissingEnvSource is the prefix of the errors returned when a Secret or ConfigMap referenced by .spec.env or .spec.envFrom of the RolloutManager does not exist.
+const MissingEnvSource = "missing environment variable source"
+
+// envSourceReference is a Secret or ConfigMap referenced by the environment of the Argo Rollouts controller container
+type envSourceReference struct {
+	// kind is either Secret or ConfigMap
+	kind string
+	// name of the Secret or ConfigMap, in the namespace of the RolloutManager
+	name string
+	// key of the Secret or ConfigMap, or empty when all of its keys are referenced by .spec.envFrom
+	key string
+	// field of the RolloutManager which holds the reference
+	field string
+}
+
+// getEnvSourceReferences returns the Secrets and ConfigMaps which are referenced by .spec.env and .spec.envFrom of the RolloutManager, in order.
+// Optional references are skipped, since the container starts without them.
+func getEnvSourceReferences(cr rolloutsmanagerv1alpha1.RolloutManager) []envSourceReference {
+	var res []envSourceReference
+
+	for idx, env := range cr.Spec.Env {
+		if env.ValueFrom == nil {
+			continue
+		}
+		field := fmt.Sprintf(".spec.env[%d]", idx)
+		if ref := env.ValueFrom.SecretKeyRef; ref != nil && (ref.Optional == nil || !*ref.Optional) {
+			res = append(res, envSourceReference{kind: "Secret", name: ref.Name, key: ref.Key, field: field})
+		}
+		if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil && (ref.Optional == nil || !*ref.Optional) {
+			res = append(res, envSourceReference{kind: "ConfigMap", name: ref.Name, key: ref.Key, field: field})
+		}
+	}
+
+	for idx, envFrom := range cr.Spec.EnvFrom {
+		field := fmt.Sprintf(".spec.envFrom[%d]", idx)
+		if ref := envFrom.SecretRef; ref != nil && (ref.Optional == nil || !*ref.Optional) {
+			res = append(res, envSourceReference{kind: "Secret", name: ref.Name, field: field})
+		}
+		if ref := envFrom.ConfigMapRef; ref != nil && (ref.Optional == nil || !*ref.Optional) {
+			res = append(res, envSourceReference{kind: "ConfigMap", name: ref.Name, field: field})
+		}
+	}
+
+	return res
+}
+
+// validateRolloutsEnvSources verifies that the Secrets and ConfigMaps referenced by the environment of the Argo Rollouts controller container exist,
+// along with the keys that are referenced. Otherwise, the pod of the Argo Rollouts controller would fail to start with CreateContainerConfigError.
+func validateRolloutsEnvSources(ctx context.Context, k8sClient client.Client, cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	phasePending := rolloutsmanagerv1alpha1.PhasePending
+
+	pending := func(err error) (*reconcileStatusResult, error) {
+		return &reconcileStatusResult{
+			rolloutController: &phasePending,
+			phase:             &phasePending,
+		}, err
+	}
+
+	for _, ref := range getEnvSourceReferences(cr) {
+
+		var keys []string
+
+		switch ref.kind {
+		case "Secret":
+			secret := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: ref.name}, secret); err != nil {
+				if apierrors.IsNotFound(err) {
+					return pending(fmt.Errorf("%s: Secret %s, referenced by %s, does not exist", MissingEnvSource, ref.name, ref.field))
+				}
+				return nil, fmt.Errorf("failed to get the Secret %s, referenced by %s: %w", ref.name, ref.field, err)
+			}
+			for key := range secret.Data {
+				keys = append(keys, key)
+			}
+
+		case "ConfigMap":
+			configMap := &corev1.ConfigMap{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: ref.name}, configMap); err != nil {
+				if apierrors.IsNotFound(err) {
+					return pending(fmt.Errorf("%s: ConfigMap %s, referenced by %s, does not exist", MissingEnvSource, ref.name, ref.field))
+				}
+				return nil, fmt.Errorf("failed to get the ConfigMap %s, referenced by %s: %w", ref.name, ref.field, err)
+			}
+			for key := range configMap.Data {
+				keys = append(keys, key)
+			}
+			for key := range configMap.BinaryData {
+				keys = append(keys, key)
+			}
+		}
+
+		if ref.key != "" && !contains(keys, ref.key) {
+			return pending(fmt.Errorf("%s: key %s of %s %s, referenced by %s, does not exist", MissingEnvSource, ref.key, ref.kind, ref.name, ref.field))
+		}
+	}
+
+	return nil, nil
+}
+
+func missingEnvSource(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), MissingEnvSource)
+}
+
+// referencesEnvSource returns true if the environment of the Argo Rollouts controller container of the RolloutManager references the Secret or ConfigMap.
+func referencesEnvSource(cr rolloutsmanagerv1alpha1.RolloutManager, kind string, name string) bool {
+	for _, ref := range getEnvSourceReferences(cr) {
+		if ref.kind == kind && ref.name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package rollouts
+
+import (
+	"context"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Rollouts environment variable source tests", func() {
+	var (
+		ctx context.Context
+		a   v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager()
+		r = makeTestReconciler(&a)
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+	})
+
+	It("should report the Secrets and ConfigMaps referenced by .spec.env and .spec.envFrom which do not exist", func() {
+		a.Spec.Env = []corev1.EnvVar{
+			{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "rollouts-token"},
+				Key:                  "token",
+			}}},
+			{Name: "OPTIONAL", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "does-not-exist"},
+				Key:                  "value",
+				Optional:             boolPtr(true),
+			}}},
+		}
+		a.Spec.EnvFrom = []corev1.EnvFromSource{
+			{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "rollouts-env"}}},
+		}
+
+		By("verifying that a missing Secret is reported")
+		rr, err := validateRolloutsEnvSources(ctx, r.Client, a)
+		Expect(missingEnvSource(err)).To(BeTrue())
+		Expect(err.Error()).To(Equal("missing environment variable source: Secret rollouts-token, referenced by .spec.env[0], does not exist"))
+		Expect(*rr.phase).To(Equal(v1alpha1.PhasePending))
+		Expect(*rr.rolloutController).To(Equal(v1alpha1.PhasePending))
+
+		By("verifying that a missing key of the Secret is reported")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "rollouts-token", Namespace: a.Namespace},
+			Data:       map[string][]byte{"other": []byte("value")},
+		}
+		Expect(r.Client.Create(ctx, secret)).To(Succeed())
+
+		_, err = validateRolloutsEnvSources(ctx, r.Client, a)
+		Expect(missingEnvSource(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("key token of Secret rollouts-token, referenced by .spec.env[0], does not exist"))
+
+		By("verifying that a missing ConfigMap of .spec.envFrom is reported, while the optional reference is skipped")
+		secret.Data["token"] = []byte("value")
+		Expect(r.Client.Update(ctx, secret)).To(Succeed())
+
+		_, err = validateRolloutsEnvSources(ctx, r.Client, a)
+		Expect(missingEnvSource(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("ConfigMap rollouts-env, referenced by .spec.envFrom[0], does not exist"))
+
+		By("verifying that no error is returned once every reference exists")
+		configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "rollouts-env", Namespace: a.Namespace}}
+		Expect(r.Client.Create(ctx, configMap)).To(Succeed())
+
+		rr, err = validateRolloutsEnvSources(ctx, r.Client, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rr).To(BeNil())
+
+		By("verifying that the RolloutManager is queued when a referenced Secret or ConfigMap changes")
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.enqueueRolloutManagersReferencingSecret(ctx, secret)).To(HaveLen(1))
+		Expect(r.enqueueRolloutManagersReferencingConfigMap(ctx, configMap)).To(HaveLen(1))
+		Expect(r.enqueueRolloutManagersReferencingConfigMap(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: a.Namespace},
+		})).To(BeEmpty())
+	})
+
+	It("should not create the Deployment until the Secrets referenced by .spec.envFrom exist", func() {
+		Expect(os.Setenv(ClusterScopedArgoRolloutsNamespaces, a.Namespace)).To(Succeed())
+		defer func() {
+			Expect(os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)).To(Succeed())
+		}()
+
+		a.Spec.EnvFrom = []corev1.EnvFromSource{
+			{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "rollouts-env"}}},
+		}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&a)}
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&a), &a)).To(Succeed())
+		Expect(a.Status.Phase).To(Equal(v1alpha1.PhasePending))
+		Expect(a.Status.Conditions[0].Reason).To(Equal(v1alpha1.RolloutManagerReasonMissingEnvSource))
+		Expect(a.Status.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).ToNot(Succeed())
+
+		By("creating the Secret")
+		Expect(r.Client.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "rollouts-env", Namespace: a.Namespace}})).To(Succeed())
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&a), &a)).To(Succeed())
+		Expect(a.Status.Conditions[0].Reason).To(Equal(v1alpha1.RolloutManagerReasonSuccess))
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.Spec.Template.Spec.Containers[0].EnvFrom).To(Equal(a.Spec.EnvFrom))
+	})
+})
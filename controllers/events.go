@@ -0,0 +1,68 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reasons of the events recorded on the RolloutManager. Validation failures are recorded with the reason of the condition set on the RolloutManager.
+const (
+	EventReasonResourceCreated     = "ResourceCreated"
+	EventReasonResourceUpdated     = "ResourceUpdated"
+	EventReasonResourceDeleted     = "ResourceDeleted"
+	EventReasonResourceAdopted     = "ResourceAdopted"
+	EventReasonDeploymentRecreated = "DeploymentRecreated"
+	EventReasonPodRestarted        = "PodRestarted"
+)
+
+// recordEvent records an event on the RolloutManager, so that users who cannot read the logs of the operator can follow what it did, through 'kubectl describe'.
+// No event is recorded when cr is nil, such as when the namespace of the RolloutManager no longer exists.
+func (r *RolloutManagerReconciler) recordEvent(cr *rolloutsmanagerv1alpha1.RolloutManager, eventType string, reason string, message string) {
+	if r.Recorder == nil || cr == nil {
+		return
+	}
+	r.Recorder.Event(cr, eventType, reason, message)
+}
+
+// createResource creates a resource managed by the RolloutManager, and records an event on the RolloutManager once it is created.
+func (r *RolloutManagerReconciler) createResource(ctx context.Context, cr *rolloutsmanagerv1alpha1.RolloutManager, obj client.Object) error {
+	if err := r.Client.Create(ctx, obj); err != nil {
+		return err
+	}
+	r.recordEvent(cr, corev1.EventTypeNormal, EventReasonResourceCreated, fmt.Sprintf("Created %s %s", getKind(obj), obj.GetName()))
+	return nil
+}
+
+// updateResource updates a resource managed by the RolloutManager, and records an event on the RolloutManager once it is updated.
+func (r *RolloutManagerReconciler) updateResource(ctx context.Context, cr *rolloutsmanagerv1alpha1.RolloutManager, obj client.Object) error {
+	if err := r.Client.Update(ctx, obj); err != nil {
+		return err
+	}
+	resourceUpdatesTotal.WithLabelValues(getKind(obj)).Inc()
+	r.recordEvent(cr, corev1.EventTypeNormal, EventReasonResourceUpdated, fmt.Sprintf("Updated %s %s", getKind(obj), obj.GetName()))
+	return nil
+}
+
+// deleteResource deletes a resource which is no longer needed, such as a ClusterRole, and records an event on the RolloutManager once it is deleted.
+// A resource which no longer exists is ignored.
+func (r *RolloutManagerReconciler) deleteResource(ctx context.Context, cr *rolloutsmanagerv1alpha1.RolloutManager, obj client.Object) error {
+	if err := r.Client.Delete(ctx, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	r.recordEvent(cr, corev1.EventTypeNormal, EventReasonResourceDeleted, fmt.Sprintf("Deleted %s %s", getKind(obj), obj.GetName()))
+	return nil
+}
+
+// getKind returns the kind of the resource, from the name of its type, since the TypeMeta of typed objects is usually empty.
+func getKind(obj client.Object) string {
+	return reflect.TypeOf(obj).Elem().Name()
+}
@@ -0,0 +1,181 @@
+package rollouts
+
+import (
+	"context"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// drainEvents returns the events recorded by the recorder since it was last drained.
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var res []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			res = append(res, event)
+		default:
+			return res
+		}
+	}
+}
+
+var _ = Describe("RolloutManager events tests", func() {
+	var (
+		ctx      context.Context
+		a        v1alpha1.RolloutManager
+		r        *RolloutManagerReconciler
+		recorder *record.FakeRecorder
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager()
+		r = makeTestReconciler(&a)
+		recorder = record.NewFakeRecorder(100)
+		r.Recorder = recorder
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+	})
+
+	It("should record the creation and update of the managed resources", func() {
+		Expect(os.Setenv(ClusterScopedArgoRolloutsNamespaces, a.Namespace)).To(Succeed())
+		defer func() {
+			Expect(os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)).To(Succeed())
+		}()
+
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&a)}
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+
+		events := drainEvents(recorder)
+		Expect(events).To(ContainElements(
+			"Normal ResourceCreated Created ServiceAccount argo-rollouts",
+			"Normal ResourceCreated Created ClusterRole argo-rollouts",
+			"Normal ResourceCreated Created ClusterRoleBinding argo-rollouts",
+			"Normal ResourceCreated Created Secret argo-rollouts-notification-secret",
+			"Normal ResourceCreated Created ConfigMap argo-rollouts-config",
+			"Normal ResourceCreated Created Deployment argo-rollouts",
+			"Normal ResourceCreated Created Service argo-rollouts-metrics",
+		))
+
+		By("reconciling again, without any change")
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(drainEvents(recorder)).To(BeEmpty())
+
+		By("modifying the Deployment")
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		deployment.Spec.Template.Spec.Containers[0].Image = "quay.io/my/argo-rollouts:v0.0.0"
+		Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(drainEvents(recorder)).To(ConsistOf("Normal ResourceUpdated Updated Deployment argo-rollouts"))
+	})
+
+	It("should record a warning when the RolloutManager is not valid", func() {
+		_, err := r.reconcileRolloutsManager(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(drainEvents(recorder)).To(ConsistOf("Warning " + v1alpha1.RolloutManagerReasonInvalidNamespace + " " + UnsupportedRolloutManagerClusterScopedNamespace))
+	})
+
+	It("should record the recreation of the Deployment after a change of its selector", func() {
+		sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace}}
+		Expect(r.Client.Create(ctx, sa)).To(Succeed())
+
+		Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+		Expect(drainEvents(recorder)).To(ConsistOf("Normal ResourceCreated Created Deployment argo-rollouts"))
+
+		a.Spec.AdditionalMetadata = &v1alpha1.ResourceMetadata{Labels: map[string]string{"new-label": "new-label-value"}}
+		Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+		Expect(drainEvents(recorder)).To(Equal([]string{
+			"Normal DeploymentRecreated Deleted Deployment argo-rollouts to recreate it, since its immutable .spec.selector has changed",
+			"Normal ResourceCreated Created Deployment argo-rollouts",
+		}))
+	})
+
+	It("should record the recreation of the dashboard Deployment after a change of its selector", func() {
+		a.Spec.Dashboard = &v1alpha1.RolloutsDashboardSpec{Enabled: true}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+		drainEvents(recorder)
+
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsDashboardResourceName, deployment)).To(Succeed())
+		deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "my-dashboard"}}
+		Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+		Expect(drainEvents(recorder)).To(Equal([]string{
+			"Normal DeploymentRecreated Deleted Deployment argo-rollouts-dashboard to recreate it, since its immutable .spec.selector has changed",
+			"Normal ResourceCreated Created Deployment argo-rollouts-dashboard",
+		}))
+	})
+
+	It("should record the deletion of the dashboard resources when the dashboard is disabled", func() {
+		a.Spec.Dashboard = &v1alpha1.RolloutsDashboardSpec{Enabled: true}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+		drainEvents(recorder)
+
+		a.Spec.Dashboard.Enabled = false
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+		Expect(r.reconcileRolloutsDashboard(ctx, a)).To(Succeed())
+		Expect(drainEvents(recorder)).To(ConsistOf(
+			"Normal ResourceDeleted Deleted Deployment argo-rollouts-dashboard",
+			"Normal ResourceDeleted Deleted Service argo-rollouts-dashboard",
+			"Normal ResourceDeleted Deleted ServiceAccount argo-rollouts-dashboard",
+			"Normal ResourceDeleted Deleted ClusterRole argo-rollouts-dashboard",
+			"Normal ResourceDeleted Deleted ClusterRoleBinding argo-rollouts-dashboard",
+		))
+	})
+
+	It("should record the restart of the Rollouts controller pods", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName}},
+			},
+		}
+		Expect(r.Client.Create(ctx, deployment)).To(Succeed())
+
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      "argo-rollouts-pod",
+			Namespace: a.Namespace,
+			Labels:    deployment.Spec.Selector.MatchLabels,
+		}}
+		Expect(r.Client.Create(ctx, pod)).To(Succeed())
+
+		Expect(r.restartRolloutsPod(ctx, a)).To(Succeed())
+		Expect(drainEvents(recorder)).To(ConsistOf("Normal PodRestarted Deleted Pod argo-rollouts-pod to restart it, since ConfigMap argo-rollouts-config has changed"))
+	})
+
+	It("should record the deletion of the cluster-scoped RBAC of a RolloutManager which no longer exists", func() {
+		Expect(r.Client.Create(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName}})).To(Succeed())
+		Expect(r.Client.Create(ctx, &rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName}})).To(Succeed())
+
+		Expect(r.removeClusterScopedResourcesIfApplicable(ctx, &a)).To(Succeed())
+		Expect(drainEvents(recorder)).To(ConsistOf(
+			"Normal ResourceDeleted Deleted ClusterRole argo-rollouts",
+			"Normal ResourceDeleted Deleted ClusterRoleBinding argo-rollouts",
+		))
+
+		By("verifying that no event is recorded without a RolloutManager")
+		Expect(r.Client.Create(ctx, &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName}})).To(Succeed())
+		Expect(r.removeClusterScopedResourcesIfApplicable(ctx, nil)).To(Succeed())
+		Expect(drainEvents(recorder)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,135 @@
+package rollouts
+
+import (
+	"context"
+	"time"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Sub-steps of reconcileRolloutsManager, used as the 'step' label of the reconcile metrics.
+const (
+	reconcileStepValidation                  = "validation"
+	reconcileStepCRDs                        = "crds"
+	reconcileStepServiceAccount              = "service_account"
+	reconcileStepRole                        = "role"
+	reconcileStepClusterRole                 = "cluster_role"
+	reconcileStepAggregateToAdminClusterRole = "aggregate_to_admin_cluster_role"
+	reconcileStepAggregateToEditClusterRole  = "aggregate_to_edit_cluster_role"
+	reconcileStepAggregateToViewClusterRole  = "aggregate_to_view_cluster_role"
+	reconcileStepRoleBinding                 = "role_binding"
+	reconcileStepClusterRoleBinding          = "cluster_role_binding"
+	reconcileStepNotificationSecret          = "notification_secret"
+	reconcileStepNotificationConfigMap       = "notification_configmap"
+	reconcileStepConfigMap                   = "configmap"
+	reconcileStepDeployment                  = "deployment"
+	reconcileStepPodDisruptionBudget         = "pod_disruption_budget"
+	reconcileStepMetricsServiceAndMonitor    = "metrics_service"
+	reconcileStepDashboard                   = "dashboard"
+	reconcileStepStatus                      = "status"
+)
+
+// Values of the 'scope' label of the rollouts_manager_rollout_managers metric.
+const (
+	rolloutManagerScopeNamespace = "Namespace"
+	rolloutManagerScopeCluster   = "Cluster"
+)
+
+var (
+	reconcileStepDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "rollouts_manager_reconcile_step_duration_seconds",
+		Help: "Duration of the sub-steps of the reconciliation of a RolloutManager, such as the reconciliation of its ServiceAccount or Deployment.",
+	}, []string{"step"})
+
+	reconcileStepErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rollouts_manager_reconcile_step_errors_total",
+		Help: "Number of errors returned by the sub-steps of the reconciliation of a RolloutManager.",
+	}, []string{"step"})
+
+	rolloutManagersGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rollouts_manager_rollout_managers",
+		Help: "Number of RolloutManagers, by phase and scope.",
+	}, []string{"phase", "scope"})
+
+	// resourceUpdatesTotal counts every update of a managed resource: the resource may have been modified outside of the operator,
+	// or the expected state may have changed, after a change of the RolloutManager or an upgrade of the operator.
+	resourceUpdatesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rollouts_manager_resource_updates_total",
+		Help: "Number of updates of the resources managed by the RolloutManagers, since they did not match the expected state, by kind.",
+	}, []string{"kind"})
+
+	controllerPodRestartsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "rollouts_manager_controller_pod_restarts_total",
+		Help: "Number of rollouts controller pods deleted by the operator to restart them, after a change of their ConfigMap.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		reconcileStepDurationSeconds,
+		reconcileStepErrorsTotal,
+		rolloutManagersGauge,
+		resourceUpdatesTotal,
+		controllerPodRestartsTotal,
+	)
+}
+
+// reconcileStepTimer measures the duration of the sub-steps of reconcileRolloutsManager, which are run one after the other.
+type reconcileStepTimer struct {
+	step  string
+	start time.Time
+}
+
+// startStep ends the sub-step in progress, which succeeded since the next one is started, and starts measuring 'step'.
+func (t *reconcileStepTimer) startStep(step string) {
+	t.end(nil)
+	t.step = step
+	t.start = time.Now()
+}
+
+// end ends the sub-step in progress, if any: err is counted as an error of the sub-step.
+func (t *reconcileStepTimer) end(err error) {
+	if t.step == "" {
+		return
+	}
+	reconcileStepDurationSeconds.WithLabelValues(t.step).Observe(time.Since(t.start).Seconds())
+	if err != nil {
+		reconcileStepErrorsTotal.WithLabelValues(t.step).Inc()
+	}
+	t.step = ""
+}
+
+// updateRolloutManagersMetric counts the RolloutManagers of the cluster by phase and scope. A RolloutManager which has not been reconciled yet is counted in the Unknown phase.
+func (r *RolloutManagerReconciler) updateRolloutManagersMetric(ctx context.Context) error {
+
+	rolloutManagerList := &rolloutsmanagerv1alpha1.RolloutManagerList{}
+	if err := r.Client.List(ctx, rolloutManagerList); err != nil {
+		return err
+	}
+
+	rolloutManagersGauge.Reset()
+	for _, phase := range []rolloutsmanagerv1alpha1.RolloutControllerPhase{rolloutsmanagerv1alpha1.PhaseAvailable, rolloutsmanagerv1alpha1.PhasePending,
+		rolloutsmanagerv1alpha1.PhaseUnknown, rolloutsmanagerv1alpha1.PhaseFailure} {
+		rolloutManagersGauge.WithLabelValues(string(phase), rolloutManagerScopeNamespace).Set(0)
+		rolloutManagersGauge.WithLabelValues(string(phase), rolloutManagerScopeCluster).Set(0)
+	}
+
+	for _, rolloutManager := range rolloutManagerList.Items {
+
+		phase := rolloutManager.Status.Phase
+		if phase == "" {
+			phase = rolloutsmanagerv1alpha1.PhaseUnknown
+		}
+
+		scope := rolloutManagerScopeCluster
+		if rolloutManager.Spec.NamespaceScoped {
+			scope = rolloutManagerScopeNamespace
+		}
+
+		rolloutManagersGauge.WithLabelValues(string(phase), scope).Inc()
+	}
+
+	return nil
+}
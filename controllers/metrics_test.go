@@ -0,0 +1,135 @@
+package rollouts
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// getReconcileStepSampleCount returns the number of times the duration of the reconcile sub-step was measured.
+func getReconcileStepSampleCount(step string) uint64 {
+	metric := &dto.Metric{}
+	Expect(reconcileStepDurationSeconds.WithLabelValues(step).(prometheus.Histogram).Write(metric)).To(Succeed())
+	return metric.GetHistogram().GetSampleCount()
+}
+
+var _ = Describe("Operator metrics tests", func() {
+	var (
+		ctx context.Context
+		a   v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager()
+		r = makeTestReconciler(&a)
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+	})
+
+	It("should count the errors of the sub-steps of the reconciliation", func() {
+		errors0 := testutil.ToFloat64(reconcileStepErrorsTotal.WithLabelValues(reconcileStepDeployment))
+		configMapErrors0 := testutil.ToFloat64(reconcileStepErrorsTotal.WithLabelValues(reconcileStepConfigMap))
+
+		timer := &reconcileStepTimer{}
+		timer.startStep(reconcileStepConfigMap)
+		timer.startStep(reconcileStepDeployment)
+		timer.end(errors.New("failed to create the Deployment"))
+
+		By("verifying that only the sub-step in progress is counted as failed")
+		Expect(testutil.ToFloat64(reconcileStepErrorsTotal.WithLabelValues(reconcileStepDeployment))).To(Equal(errors0 + 1))
+		Expect(testutil.ToFloat64(reconcileStepErrorsTotal.WithLabelValues(reconcileStepConfigMap))).To(Equal(configMapErrors0))
+
+		By("verifying that ending the timer again has no effect")
+		timer.end(errors.New("failed to create the Deployment"))
+		Expect(testutil.ToFloat64(reconcileStepErrorsTotal.WithLabelValues(reconcileStepDeployment))).To(Equal(errors0 + 1))
+	})
+
+	It("should measure the sub-steps, count the RolloutManagers and the resource updates when reconciling a RolloutManager", func() {
+		Expect(os.Setenv(ClusterScopedArgoRolloutsNamespaces, a.Namespace)).To(Succeed())
+		defer func() {
+			Expect(os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)).To(Succeed())
+		}()
+
+		steps := []string{reconcileStepValidation, reconcileStepServiceAccount, reconcileStepClusterRole, reconcileStepConfigMap,
+			reconcileStepDeployment, reconcileStepDashboard, reconcileStepStatus}
+		sampleCounts := map[string]uint64{}
+		for _, step := range steps {
+			sampleCounts[step] = getReconcileStepSampleCount(step)
+		}
+
+		req := reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&a)}
+
+		_, err := r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("verifying that the sub-steps are measured")
+		for _, step := range steps {
+			Expect(getReconcileStepSampleCount(step)).To(Equal(sampleCounts[step]+1), step)
+		}
+
+		By("verifying that the RolloutManager is counted by phase and scope")
+		Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&a), &a)).To(Succeed())
+		Expect(testutil.ToFloat64(rolloutManagersGauge.WithLabelValues(string(a.Status.Phase), rolloutManagerScopeCluster))).To(Equal(float64(1)))
+		Expect(testutil.ToFloat64(rolloutManagersGauge.WithLabelValues(string(a.Status.Phase), rolloutManagerScopeNamespace))).To(Equal(float64(0)))
+
+		By("modifying the Deployment, and verifying that its update is counted")
+		resourceUpdates0 := testutil.ToFloat64(resourceUpdatesTotal.WithLabelValues("Deployment"))
+
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		deployment.Spec.Template.Spec.Containers[0].Image = "quay.io/my/argo-rollouts:v0.0.0"
+		Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(testutil.ToFloat64(resourceUpdatesTotal.WithLabelValues("Deployment"))).To(Equal(resourceUpdates0 + 1))
+
+		By("reconciling again, and verifying that no update is counted")
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(testutil.ToFloat64(resourceUpdatesTotal.WithLabelValues("Deployment"))).To(Equal(resourceUpdates0 + 1))
+
+		By("deleting the RolloutManager, and verifying that it is no longer counted")
+		Expect(r.Client.Delete(ctx, &a)).To(Succeed())
+
+		_, err = r.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(testutil.ToFloat64(rolloutManagersGauge.WithLabelValues(string(a.Status.Phase), rolloutManagerScopeCluster))).To(Equal(float64(0)))
+	})
+
+	It("should count the rollouts controller pods restarted by the operator", func() {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultArgoRolloutsResourceName, Namespace: a.Namespace},
+			Spec: appsv1.DeploymentSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName}},
+			},
+		}
+		Expect(r.Client.Create(ctx, deployment)).To(Succeed())
+
+		for _, name := range []string{"argo-rollouts-pod-1", "argo-rollouts-pod-2"} {
+			Expect(r.Client.Create(ctx, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: a.Namespace,
+				Labels:    deployment.Spec.Selector.MatchLabels,
+			}})).To(Succeed())
+		}
+
+		restarts0 := testutil.ToFloat64(controllerPodRestartsTotal)
+
+		Expect(r.restartRolloutsPod(ctx, a)).To(Succeed())
+		Expect(testutil.ToFloat64(controllerPodRestartsTotal)).To(Equal(restarts0 + 2))
+	})
+})
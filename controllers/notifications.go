@@ -0,0 +1,451 @@
+package rollouts
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Keys of the notification ConfigMap, from https://argo-rollouts.readthedocs.io/en/stable/features/notifications/
+const (
+	NotificationServiceKeyPrefix         = "service."
+	NotificationTemplateKeyPrefix        = "template."
+	NotificationTriggerKeyPrefix         = "trigger."
+	NotificationDefaultTriggersKey       = "defaultTriggers"
+	NotificationSubscriptionsKey         = "subscriptions"
+	UnsupportedNotificationConfiguration = "invalid notification configuration"
+	// MissingNotificationSource is the prefix of the errors returned when a Secret (or Secret key) referenced by .spec.notificationSecretSources, or a ConfigMap referenced by
+	// .spec.notifications.templateSets, does not exist.
+	MissingNotificationSource = "missing notification source"
+)
+
+// NotificationConfigMapAdoptedAnnotation is set on a notification ConfigMap which was created outside of the operator, and adopted when .spec.notifications was set.
+// An adopted ConfigMap is not owned by the RolloutManager, so that it is never deleted: it is released, by removing the annotation, once .spec.notifications is unset.
+const NotificationConfigMapAdoptedAnnotation = "argo-rollouts-manager.argoproj.io/adopted"
+
+// notificationTriggerCondition is a clone of Condition from "github.com/argoproj/notifications-engine/pkg/triggers"
+type notificationTriggerCondition struct {
+	When        string   `yaml:"when"`
+	Send        []string `yaml:"send"`
+	OncePer     string   `yaml:"oncePer,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+}
+
+// notificationSubscription is a clone of DefaultSubscription from "github.com/argoproj/notifications-engine/pkg/subscriptions"
+type notificationSubscription struct {
+	Recipients []string `yaml:"recipients"`
+	Triggers   []string `yaml:"triggers,omitempty"`
+	Selector   string   `yaml:"selector,omitempty"`
+}
+
+// Reconcile the Rollouts notification ConfigMap. The ConfigMap is only managed when .spec.notifications is set.
+func (r *RolloutManagerReconciler) reconcileRolloutsNotificationConfigMap(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	liveConfigMap := &corev1.ConfigMap{}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultRolloutsNotificationConfigMapName, liveConfigMap); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the ConfigMap %s: %w", DefaultRolloutsNotificationConfigMapName, err)
+		}
+		liveConfigMap = nil
+	}
+
+	if cr.Spec.Notifications == nil {
+
+		if liveConfigMap == nil {
+			return nil
+		}
+
+		// If the controller created/owns the ConfigMap, delete it, as it is no longer managed
+		controller := metav1.GetControllerOf(liveConfigMap)
+		if controller != nil && controller.Name == cr.Name {
+			log.Info(fmt.Sprintf(".spec.notifications is not set, deleting ConfigMap %s", liveConfigMap.Name))
+			return r.Client.Delete(ctx, liveConfigMap)
+		}
+
+		// If the ConfigMap was adopted, release it, leaving its data in place
+		if _, adopted := liveConfigMap.Annotations[NotificationConfigMapAdoptedAnnotation]; adopted {
+			log.Info(fmt.Sprintf(".spec.notifications is not set, releasing adopted ConfigMap %s", liveConfigMap.Name))
+			delete(liveConfigMap.Annotations, NotificationConfigMapAdoptedAnnotation)
+			return r.updateResource(ctx, &cr, liveConfigMap)
+		}
+
+		return nil
+	}
+
+	desiredData, err := r.generateDesiredNotificationConfigMapData(ctx, cr)
+	if err != nil {
+		return err
+	}
+
+	expectedConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultRolloutsNotificationConfigMapName,
+			Namespace: cr.Namespace,
+		},
+		Data: desiredData,
+	}
+
+	setRolloutsLabelsAndAnnotationsToObject(&expectedConfigMap.ObjectMeta, cr)
+
+	if liveConfigMap == nil {
+		if err := controllerutil.SetControllerReference(&cr, expectedConfigMap, r.Scheme); err != nil {
+			return err
+		}
+
+		log.Info(fmt.Sprintf("Creating ConfigMap %s", expectedConfigMap.Name))
+		return r.createResource(ctx, &cr, expectedConfigMap)
+	}
+
+	updateNeeded := false
+
+	// A ConfigMap created outside of the operator, before .spec.notifications was set, is adopted: its data is replaced by .spec.notifications, and changes
+	// made to it are then reverted. It is not owned by the RolloutManager, so that it is never deleted, neither with the RolloutManager nor once .spec.notifications is unset.
+	if _, adopted := liveConfigMap.Annotations[NotificationConfigMapAdoptedAnnotation]; !adopted && metav1.GetControllerOf(liveConfigMap) == nil {
+		if liveConfigMap.Annotations == nil {
+			liveConfigMap.Annotations = map[string]string{}
+		}
+		liveConfigMap.Annotations[NotificationConfigMapAdoptedAnnotation] = "true"
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Adopting ConfigMap %s, since .spec.notifications is set", liveConfigMap.Name))
+		r.recordEvent(&cr, corev1.EventTypeNormal, EventReasonResourceAdopted,
+			fmt.Sprintf("Adopted ConfigMap %s, whose data is replaced by .spec.notifications", liveConfigMap.Name))
+	}
+
+	normalizedLiveConfigMap := liveConfigMap.DeepCopy()
+	removeUserLabelsAndAnnotations(&normalizedLiveConfigMap.ObjectMeta, cr)
+
+	if !reflect.DeepEqual(normalizedLiveConfigMap.Labels, expectedConfigMap.Labels) || !reflect.DeepEqual(normalizedLiveConfigMap.Annotations, expectedConfigMap.Annotations) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Labels/Annotations of ConfigMap %s do not match the expected state, hence updating it", liveConfigMap.Name))
+
+		liveConfigMap.Labels = combineStringMaps(liveConfigMap.Labels, expectedConfigMap.Labels)
+		liveConfigMap.Annotations = combineStringMaps(liveConfigMap.Annotations, expectedConfigMap.Annotations)
+	}
+
+	// An empty Data field is returned as nil by the API server
+	if len(liveConfigMap.Data) != len(expectedConfigMap.Data) || (len(expectedConfigMap.Data) > 0 && !reflect.DeepEqual(liveConfigMap.Data, expectedConfigMap.Data)) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Data of ConfigMap %s does not match the expected state, hence updating it", liveConfigMap.Name))
+
+		liveConfigMap.Data = expectedConfigMap.Data
+	}
+
+	if updateNeeded {
+		// The notification engine of Argo Rollouts watches the ConfigMap, so there is no need to restart the Rollouts pod.
+		return r.updateResource(ctx, &cr, liveConfigMap)
+	}
+
+	return nil
+}
+
+// generateDesiredNotificationConfigMapData renders .spec.notifications into the format expected by the notification engine of Argo Rollouts.
+func (r *RolloutManagerReconciler) generateDesiredNotificationConfigMapData(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (map[string]string, error) {
+
+	notifications := cr.Spec.Notifications
+
+	data := map[string]string{}
+
+	// Templates and triggers of the referenced template sets are added first, so that those defined in the spec take precedence.
+	for _, templateSet := range notifications.TemplateSets {
+		templateSetConfigMap := &corev1.ConfigMap{}
+		if err := fetchObject(ctx, r.Client, cr.Namespace, templateSet.Name, templateSetConfigMap); err != nil {
+			return nil, fmt.Errorf("failed to get the notification template set ConfigMap %s: %w", templateSet.Name, err)
+		}
+
+		for key, value := range templateSetConfigMap.Data {
+			if strings.HasPrefix(key, NotificationTemplateKeyPrefix) || strings.HasPrefix(key, NotificationTriggerKeyPrefix) {
+				data[key] = value
+			}
+		}
+	}
+
+	for name, config := range notifications.Services {
+		data[NotificationServiceKeyPrefix+name] = config
+	}
+
+	for _, template := range notifications.Templates {
+		templateString, err := renderNotificationTemplate(template)
+		if err != nil {
+			return nil, err
+		}
+		data[NotificationTemplateKeyPrefix+template.Name] = templateString
+	}
+
+	for _, trigger := range notifications.Triggers {
+		conditions := make([]notificationTriggerCondition, 0, len(trigger.Conditions))
+		for _, condition := range trigger.Conditions {
+			conditions = append(conditions, notificationTriggerCondition(condition))
+		}
+
+		triggerString, err := yaml.Marshal(conditions)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling notification trigger %s to string %s", trigger.Name, err)
+		}
+		data[NotificationTriggerKeyPrefix+trigger.Name] = string(triggerString)
+	}
+
+	if len(notifications.DefaultTriggers) > 0 {
+		defaultTriggersString, err := yaml.Marshal(notifications.DefaultTriggers)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling notification default triggers to string %s", err)
+		}
+		data[NotificationDefaultTriggersKey] = string(defaultTriggersString)
+	}
+
+	if len(notifications.DefaultSubscriptions) > 0 {
+		subscriptions := make([]notificationSubscription, 0, len(notifications.DefaultSubscriptions))
+		for _, subscription := range notifications.DefaultSubscriptions {
+			subscriptions = append(subscriptions, notificationSubscription(subscription))
+		}
+
+		subscriptionsString, err := yaml.Marshal(subscriptions)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling notification default subscriptions to string %s", err)
+		}
+		data[NotificationSubscriptionsKey] = string(subscriptionsString)
+	}
+
+	return data, nil
+}
+
+// renderNotificationTemplate returns the YAML representation of a notification template: its message, followed by its service specific fields.
+func renderNotificationTemplate(template rolloutsmanagerv1alpha1.NotificationTemplate) (string, error) {
+
+	var content yaml.MapSlice
+
+	if template.Message != "" {
+		content = append(content, yaml.MapItem{Key: "message", Value: template.Message})
+	}
+
+	// Sort the service names for deterministic ordering
+	serviceNames := make([]string, 0, len(template.Services))
+	for name := range template.Services {
+		serviceNames = append(serviceNames, name)
+	}
+	sort.Strings(serviceNames)
+
+	for _, name := range serviceNames {
+		var serviceContent yaml.MapSlice
+		if err := yaml.Unmarshal([]byte(template.Services[name]), &serviceContent); err != nil {
+			return "", fmt.Errorf("%s: the '%s' service of template %s is not a valid YAML object: %v", UnsupportedNotificationConfiguration, name, template.Name, err)
+		}
+		content = append(content, yaml.MapItem{Key: name, Value: serviceContent})
+	}
+
+	templateString, err := yaml.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling notification template %s to string %s", template.Name, err)
+	}
+
+	return string(templateString), nil
+}
+
+// validateRolloutsNotifications validates the notification engine configuration of the RolloutManager.
+func validateRolloutsNotifications(cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	if cr.Spec.SkipNotificationSecretDeployment && len(cr.Spec.NotificationSecretSources) > 0 {
+		return validationFailure(fmt.Errorf("%s: .spec.notificationSecretSources may not be set when .spec.skipNotificationSecretDeployment is true", UnsupportedNotificationConfiguration))
+	}
+
+	if errs := rolloutsmanagerv1alpha1.ValidateNotificationSecretSources(field.NewPath("spec", "notificationSecretSources"), cr.Spec.NotificationSecretSources); len(errs) > 0 {
+		return fieldValidationFailure(UnsupportedNotificationConfiguration, errs)
+	}
+
+	notifications := cr.Spec.Notifications
+	if notifications == nil {
+		return nil, nil
+	}
+
+	templateNames := map[string]bool{}
+	for _, template := range notifications.Templates {
+		if templateNames[template.Name] {
+			return validationFailure(fmt.Errorf("%s: template %s is defined more than once", UnsupportedNotificationConfiguration, template.Name))
+		}
+		templateNames[template.Name] = true
+
+		if _, err := renderNotificationTemplate(template); err != nil {
+			return validationFailure(err)
+		}
+	}
+
+	triggerNames := map[string]bool{}
+	for _, trigger := range notifications.Triggers {
+		if triggerNames[trigger.Name] {
+			return validationFailure(fmt.Errorf("%s: trigger %s is defined more than once", UnsupportedNotificationConfiguration, trigger.Name))
+		}
+		triggerNames[trigger.Name] = true
+
+		if len(trigger.Conditions) == 0 {
+			return validationFailure(fmt.Errorf("%s: trigger %s has no conditions", UnsupportedNotificationConfiguration, trigger.Name))
+		}
+		for _, condition := range trigger.Conditions {
+			if len(condition.Send) == 0 {
+				return validationFailure(fmt.Errorf("%s: a condition of trigger %s does not send any template", UnsupportedNotificationConfiguration, trigger.Name))
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// invalidNotificationConfiguration returns true if the error was returned by validateRolloutsNotifications.
+func invalidNotificationConfiguration(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), UnsupportedNotificationConfiguration)
+}
+
+// validateRolloutsNotificationSources verifies that the Secrets and keys referenced by .spec.notificationSecretSources, and the ConfigMaps referenced by .spec.notifications.templateSets,
+// exist, so that the notification Secret and ConfigMap are not reconciled with only part of their content. It also verifies that no two sources provide the same key of the
+// notification Secret, which can only be known once the Secrets of the sources that merge all of their keys are read.
+func validateRolloutsNotificationSources(ctx context.Context, k8sClient client.Client, cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	phasePending := rolloutsmanagerv1alpha1.PhasePending
+
+	pending := func(err error) (*reconcileStatusResult, error) {
+		return &reconcileStatusResult{
+			rolloutController: &phasePending,
+			phase:             &phasePending,
+		}, err
+	}
+
+	// targetKeys maps the keys of the notification Secret to the source which provides them
+	targetKeys := map[string]string{}
+
+	for idx, source := range cr.Spec.NotificationSecretSources {
+		sourceField := fmt.Sprintf(".spec.notificationSecretSources[%d]", idx)
+
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: source.Name}, secret); err != nil {
+			if apierrors.IsNotFound(err) {
+				return pending(fmt.Errorf("%s: Secret %s, referenced by %s, does not exist", MissingNotificationSource, source.Name, sourceField))
+			}
+			return nil, fmt.Errorf("failed to get the Secret %s, referenced by %s: %w", source.Name, sourceField, err)
+		}
+
+		var sourceTargetKeys []string
+		if len(source.Keys) == 0 {
+			for key := range secret.Data {
+				sourceTargetKeys = append(sourceTargetKeys, key)
+			}
+			sort.Strings(sourceTargetKeys)
+		}
+		for _, mapping := range source.Keys {
+			if _, exists := secret.Data[mapping.Key]; !exists {
+				return pending(fmt.Errorf("%s: key %s of Secret %s, referenced by %s, does not exist", MissingNotificationSource, mapping.Key, source.Name, sourceField))
+			}
+			targetKey := mapping.TargetKey
+			if targetKey == "" {
+				targetKey = mapping.Key
+			}
+			sourceTargetKeys = append(sourceTargetKeys, targetKey)
+		}
+
+		for _, targetKey := range sourceTargetKeys {
+			if otherField, exists := targetKeys[targetKey]; exists {
+				return validationFailure(fmt.Errorf("%s: key %s of the notification Secret is provided by both %s and %s", UnsupportedNotificationConfiguration, targetKey, otherField, sourceField))
+			}
+			targetKeys[targetKey] = sourceField
+		}
+	}
+
+	if cr.Spec.Notifications == nil {
+		return nil, nil
+	}
+
+	for idx, templateSet := range cr.Spec.Notifications.TemplateSets {
+		configMap := &corev1.ConfigMap{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: cr.Namespace, Name: templateSet.Name}, configMap); err != nil {
+			if apierrors.IsNotFound(err) {
+				return pending(fmt.Errorf("%s: ConfigMap %s, referenced by .spec.notifications.templateSets[%d], does not exist", MissingNotificationSource, templateSet.Name, idx))
+			}
+			return nil, fmt.Errorf("failed to get the ConfigMap %s, referenced by .spec.notifications.templateSets[%d]: %w", templateSet.Name, idx, err)
+		}
+	}
+
+	return nil, nil
+}
+
+// missingNotificationSource returns true if the error was returned by validateRolloutsNotificationSources.
+func missingNotificationSource(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), MissingNotificationSource)
+}
+
+// enqueueRolloutManagersReferencingSecret queues the RolloutManagers that reference the Secret as a notification Secret source, so that changes to the source are merged into the notification Secret,
+// or from the environment of the Argo Rollouts controller, so that the Secret is validated again.
+func (r *RolloutManagerReconciler) enqueueRolloutManagersReferencingSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+
+	var rolloutManagerList rolloutsmanagerv1alpha1.RolloutManagerList
+
+	if err := r.Client.List(ctx, &rolloutManagerList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "Unable to list RolloutManagers in enqueueRolloutManagersReferencingSecret")
+		return []reconcile.Request{}
+	}
+
+	var res []reconcile.Request
+
+	for idx := range rolloutManagerList.Items {
+		rm := rolloutManagerList.Items[idx]
+		if referencesEnvSource(rm, "Secret", obj.GetName()) {
+			res = append(res, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rm)})
+			continue
+		}
+		for _, source := range rm.Spec.NotificationSecretSources {
+			if source.Name == obj.GetName() {
+				res = append(res, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rm)})
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// enqueueRolloutManagersReferencingConfigMap queues the RolloutManagers that reference the ConfigMap as a notification template set, so that changes to the template set are rendered into the notification ConfigMap,
+// or from the environment of the Argo Rollouts controller, so that the ConfigMap is validated again.
+func (r *RolloutManagerReconciler) enqueueRolloutManagersReferencingConfigMap(ctx context.Context, obj client.Object) []reconcile.Request {
+
+	var rolloutManagerList rolloutsmanagerv1alpha1.RolloutManagerList
+
+	if err := r.Client.List(ctx, &rolloutManagerList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "Unable to list RolloutManagers in enqueueRolloutManagersReferencingConfigMap")
+		return []reconcile.Request{}
+	}
+
+	var res []reconcile.Request
+
+	for idx := range rolloutManagerList.Items {
+		rm := rolloutManagerList.Items[idx]
+		if referencesEnvSource(rm, "ConfigMap", obj.GetName()) {
+			res = append(res, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rm)})
+			continue
+		}
+		if rm.Spec.Notifications == nil {
+			continue
+		}
+		// An adopted notification ConfigMap is not owned by the RolloutManager, so changes to it are not seen through the owner reference
+		if obj.GetName() == DefaultRolloutsNotificationConfigMapName {
+			res = append(res, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rm)})
+			continue
+		}
+		for _, templateSet := range rm.Spec.Notifications.TemplateSets {
+			if templateSet.Name == obj.GetName() {
+				res = append(res, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rm)})
+				break
+			}
+		}
+	}
+
+	return res
+}
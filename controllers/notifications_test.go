@@ -0,0 +1,306 @@
+package rollouts
+
+import (
+	"context"
+	"os"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ = Describe("Rollouts notification ConfigMap tests", func() {
+	var (
+		ctx context.Context
+		a   v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager()
+		r = makeTestReconciler(&a)
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+	})
+
+	It("should not manage the notification ConfigMap when .spec.notifications is not set", func() {
+		Expect(r.reconcileRolloutsNotificationConfigMap(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationConfigMapName, &corev1.ConfigMap{})).ToNot(Succeed())
+
+		By("creating a notification ConfigMap that is not owned by the RolloutManager")
+		userConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultRolloutsNotificationConfigMapName, Namespace: a.Namespace},
+			Data:       map[string]string{"service.slack": "token: $slack-token"},
+		}
+		Expect(r.Client.Create(ctx, userConfigMap)).To(Succeed())
+
+		Expect(r.reconcileRolloutsNotificationConfigMap(ctx, a)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationConfigMapName, configMap)).To(Succeed())
+		Expect(configMap.Data).To(Equal(userConfigMap.Data))
+	})
+
+	It("should render .spec.notifications into the notification ConfigMap, and revert changes made outside of the operator", func() {
+		a.Spec.Notifications = &v1alpha1.RolloutsNotificationConfigurationSpec{
+			Services: map[string]string{
+				"slack": "token: $slack-token\n",
+			},
+			Templates: []v1alpha1.NotificationTemplate{{
+				Name:    "rollout-completed",
+				Message: "Rollout {{.rollout.metadata.name}} has been completed.",
+				Services: map[string]string{
+					"slack": "attachments: |\n  [{\"title\": \"{{.rollout.metadata.name}}\"}]\n",
+				},
+			}},
+			Triggers: []v1alpha1.NotificationTrigger{{
+				Name: "on-rollout-completed",
+				Conditions: []v1alpha1.NotificationTriggerCondition{{
+					When:    "rollout.status.phase == 'Healthy'",
+					Send:    []string{"rollout-completed"},
+					OncePer: "rollout.status.currentPodHash",
+				}},
+			}},
+			DefaultTriggers: []string{"on-rollout-completed"},
+			DefaultSubscriptions: []v1alpha1.NotificationSubscription{{
+				Recipients: []string{"slack:my-channel"},
+				Triggers:   []string{"on-rollout-completed"},
+			}},
+		}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		Expect(r.reconcileRolloutsNotificationConfigMap(ctx, a)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationConfigMapName, configMap)).To(Succeed())
+		Expect(metav1.IsControlledBy(configMap, &a)).To(BeTrue())
+
+		expectedData := map[string]string{
+			"service.slack": "token: $slack-token\n",
+			"template.rollout-completed": "message: Rollout {{.rollout.metadata.name}} has been completed.\n" +
+				"slack:\n" +
+				"  attachments: |\n" +
+				"    [{\"title\": \"{{.rollout.metadata.name}}\"}]\n",
+			"trigger.on-rollout-completed": "- when: rollout.status.phase == 'Healthy'\n" +
+				"  send:\n" +
+				"  - rollout-completed\n" +
+				"  oncePer: rollout.status.currentPodHash\n",
+			"defaultTriggers": "- on-rollout-completed\n",
+			"subscriptions": "- recipients:\n" +
+				"  - slack:my-channel\n" +
+				"  triggers:\n" +
+				"  - on-rollout-completed\n",
+		}
+		Expect(configMap.Data).To(Equal(expectedData))
+
+		By("modifying the notification ConfigMap outside of the operator")
+		configMap.Data["service.slack"] = "token: my-token"
+		configMap.Data["service.email"] = "host: smtp.example.com"
+		Expect(r.Client.Update(ctx, configMap)).To(Succeed())
+
+		Expect(r.reconcileRolloutsNotificationConfigMap(ctx, a)).To(Succeed())
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationConfigMapName, configMap)).To(Succeed())
+		Expect(configMap.Data).To(Equal(expectedData))
+
+		By("unsetting .spec.notifications")
+		a.Spec.Notifications = nil
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		Expect(r.reconcileRolloutsNotificationConfigMap(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationConfigMapName, configMap)).ToNot(Succeed())
+	})
+
+	It("should adopt a notification ConfigMap created outside of the operator once .spec.notifications is set", func() {
+		userConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: DefaultRolloutsNotificationConfigMapName, Namespace: a.Namespace},
+			Data:       map[string]string{"service.email": "host: smtp.example.com"},
+		}
+		Expect(r.Client.Create(ctx, userConfigMap)).To(Succeed())
+
+		a.Spec.Notifications = &v1alpha1.RolloutsNotificationConfigurationSpec{
+			Services: map[string]string{"slack": "token: $slack-token\n"},
+		}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		Expect(r.reconcileRolloutsNotificationConfigMap(ctx, a)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationConfigMapName, configMap)).To(Succeed())
+		Expect(metav1.GetControllerOf(configMap)).To(BeNil(), "an adopted ConfigMap should not be owned, so that it is not deleted with the RolloutManager")
+		Expect(configMap.Annotations).To(HaveKeyWithValue(NotificationConfigMapAdoptedAnnotation, "true"))
+		Expect(configMap.Data).To(Equal(map[string]string{"service.slack": "token: $slack-token\n"}))
+
+		By("verifying that changes made to the adopted ConfigMap are reverted")
+		configMap.Data["service.email"] = "host: smtp.example.com"
+		Expect(r.Client.Update(ctx, configMap)).To(Succeed())
+		Expect(r.enqueueRolloutManagersReferencingConfigMap(ctx, configMap)).To(HaveLen(1))
+
+		Expect(r.reconcileRolloutsNotificationConfigMap(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationConfigMapName, configMap)).To(Succeed())
+		Expect(configMap.Data).To(Equal(map[string]string{"service.slack": "token: $slack-token\n"}))
+
+		By("unsetting .spec.notifications, and verifying that the adopted ConfigMap is released rather than deleted")
+		a.Spec.Notifications = nil
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		Expect(r.reconcileRolloutsNotificationConfigMap(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationConfigMapName, configMap)).To(Succeed())
+		Expect(configMap.Annotations).ToNot(HaveKey(NotificationConfigMapAdoptedAnnotation))
+		Expect(configMap.Data).To(Equal(map[string]string{"service.slack": "token: $slack-token\n"}))
+	})
+
+	It("should merge the templates and triggers of the referenced template sets", func() {
+		templateSet := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-template-set", Namespace: a.Namespace},
+			Data: map[string]string{
+				"template.rollout-completed": "message: from the template set",
+				"template.rollout-aborted":   "message: Rollout aborted",
+				"trigger.on-rollout-aborted": "- when: rollout.status.phase == 'Degraded'\n  send: [rollout-aborted]",
+				"service.slack":              "token: not-copied",
+				"subscriptions":              "- recipients: [not-copied]",
+			},
+		}
+		Expect(r.Client.Create(ctx, templateSet)).To(Succeed())
+
+		a.Spec.Notifications = &v1alpha1.RolloutsNotificationConfigurationSpec{
+			Templates: []v1alpha1.NotificationTemplate{{
+				Name:    "rollout-completed",
+				Message: "from the spec",
+			}},
+			TemplateSets: []v1alpha1.NotificationTemplateSetReference{{Name: templateSet.Name}},
+		}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		Expect(r.reconcileRolloutsNotificationConfigMap(ctx, a)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationConfigMapName, configMap)).To(Succeed())
+		Expect(configMap.Data).To(Equal(map[string]string{
+			"template.rollout-completed": "message: from the spec\n",
+			"template.rollout-aborted":   "message: Rollout aborted",
+			"trigger.on-rollout-aborted": "- when: rollout.status.phase == 'Degraded'\n  send: [rollout-aborted]",
+		}))
+
+		By("verifying that the RolloutManager is queued when the template set changes")
+		Expect(r.enqueueRolloutManagersReferencingConfigMap(ctx, templateSet)).To(HaveLen(1))
+		Expect(r.enqueueRolloutManagersReferencingConfigMap(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: a.Namespace},
+		})).To(BeEmpty())
+	})
+
+	It("should put the RolloutManager in the Pending phase until a referenced template set exists", func() {
+		a.Spec.Notifications = &v1alpha1.RolloutsNotificationConfigurationSpec{
+			TemplateSets: []v1alpha1.NotificationTemplateSetReference{{Name: "my-template-set"}},
+		}
+
+		rr, err := validateRolloutsNotificationSources(ctx, r.Client, a)
+		Expect(missingNotificationSource(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("ConfigMap my-template-set, referenced by .spec.notifications.templateSets[0], does not exist"))
+		Expect(*rr.phase).To(Equal(v1alpha1.PhasePending))
+		Expect(*rr.rolloutController).To(Equal(v1alpha1.PhasePending))
+
+		By("creating the template set")
+		Expect(r.Client.Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "my-template-set", Namespace: a.Namespace}})).To(Succeed())
+
+		rr, err = validateRolloutsNotificationSources(ctx, r.Client, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rr).To(BeNil())
+	})
+
+	It("should report a missing template set on the status of the RolloutManager", func() {
+		Expect(os.Setenv(ClusterScopedArgoRolloutsNamespaces, a.Namespace)).To(Succeed())
+		defer func() {
+			Expect(os.Unsetenv(ClusterScopedArgoRolloutsNamespaces)).To(Succeed())
+		}()
+
+		a.Spec.Notifications = &v1alpha1.RolloutsNotificationConfigurationSpec{
+			TemplateSets: []v1alpha1.NotificationTemplateSetReference{{Name: "my-template-set"}},
+		}
+		Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&a)})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(&a), &a)).To(Succeed())
+		Expect(a.Status.Phase).To(Equal(v1alpha1.PhasePending))
+		Expect(a.Status.Conditions[0].Reason).To(Equal(v1alpha1.RolloutManagerReasonMissingNotificationSource))
+		Expect(a.Status.Conditions[0].Status).To(Equal(metav1.ConditionFalse))
+	})
+})
+
+var _ = Describe("validateRolloutsNotifications tests", func() {
+
+	It("should not allow notification Secret sources when the notification Secret is not deployed", func() {
+		cr := *makeTestRolloutManager()
+		cr.Spec.SkipNotificationSecretDeployment = true
+		cr.Spec.NotificationSecretSources = []v1alpha1.NotificationSecretSource{{Name: "team-secret"}}
+
+		rr, err := validateRolloutsNotifications(cr)
+		Expect(invalidNotificationConfiguration(err)).To(BeTrue())
+		Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+
+		cr.Spec.SkipNotificationSecretDeployment = false
+		_, err = validateRolloutsNotifications(cr)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should not allow two notification Secret source keys with the same target key", func() {
+		cr := *makeTestRolloutManager()
+		cr.Spec.NotificationSecretSources = []v1alpha1.NotificationSecretSource{
+			{Name: "team-secret", Keys: []v1alpha1.NotificationSecretKeyMapping{{Key: "token", TargetKey: "slack-token"}}},
+			{Name: "other-team-secret", Keys: []v1alpha1.NotificationSecretKeyMapping{{Key: "slack-token"}}},
+		}
+
+		rr, err := validateRolloutsNotifications(cr)
+		Expect(invalidNotificationConfiguration(err)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("spec.notificationSecretSources[1].keys[0]: target key slack-token is provided by more than one notification Secret source key"))
+		Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+	})
+
+	DescribeTable("should validate the notification configuration", func(notifications *v1alpha1.RolloutsNotificationConfigurationSpec, expectedErr string) {
+		cr := *makeTestRolloutManager()
+		cr.Spec.Notifications = notifications
+
+		rr, err := validateRolloutsNotifications(cr)
+		if expectedErr == "" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rr).To(BeNil())
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(expectedErr))
+		Expect(invalidNotificationConfiguration(err)).To(BeTrue())
+		Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+		Expect(*rr.rolloutController).To(Equal(v1alpha1.PhaseFailure))
+	},
+		Entry("notifications are not set", nil, ""),
+		Entry("valid notifications", &v1alpha1.RolloutsNotificationConfigurationSpec{
+			Templates: []v1alpha1.NotificationTemplate{{Name: "a", Services: map[string]string{"email": "subject: hello"}}},
+			Triggers:  []v1alpha1.NotificationTrigger{{Name: "b", Conditions: []v1alpha1.NotificationTriggerCondition{{When: "true", Send: []string{"a"}}}}},
+		}, ""),
+		Entry("duplicate template", &v1alpha1.RolloutsNotificationConfigurationSpec{
+			Templates: []v1alpha1.NotificationTemplate{{Name: "a"}, {Name: "a"}},
+		}, "template a is defined more than once"),
+		Entry("template service is not a YAML object", &v1alpha1.RolloutsNotificationConfigurationSpec{
+			Templates: []v1alpha1.NotificationTemplate{{Name: "a", Services: map[string]string{"email": "- subject"}}},
+		}, "the 'email' service of template a is not a valid YAML object"),
+		Entry("duplicate trigger", &v1alpha1.RolloutsNotificationConfigurationSpec{
+			Triggers: []v1alpha1.NotificationTrigger{
+				{Name: "b", Conditions: []v1alpha1.NotificationTriggerCondition{{When: "true", Send: []string{"a"}}}},
+				{Name: "b", Conditions: []v1alpha1.NotificationTriggerCondition{{When: "true", Send: []string{"a"}}}},
+			},
+		}, "trigger b is defined more than once"),
+		Entry("trigger without conditions", &v1alpha1.RolloutsNotificationConfigurationSpec{
+			Triggers: []v1alpha1.NotificationTrigger{{Name: "b"}},
+		}, "trigger b has no conditions"),
+		Entry("trigger condition without templates", &v1alpha1.RolloutsNotificationConfigurationSpec{
+			Triggers: []v1alpha1.NotificationTrigger{{Name: "b", Conditions: []v1alpha1.NotificationTriggerCondition{{When: "true"}}}},
+		}, "a condition of trigger b does not send any template"),
+	)
+})
@@ -0,0 +1,163 @@
+package rollouts
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	jsonpatch "github.com/evanphx/json-patch"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// UnsupportedOverrideConfiguration is the prefix of the errors returned when .spec.overrides of the RolloutManager is invalid.
+const UnsupportedOverrideConfiguration = "invalid override configuration"
+
+// getResourceOverrides returns the overrides of the RolloutManager which target the resource of the given kind and name, in order.
+func getResourceOverrides(cr rolloutsmanagerv1alpha1.RolloutManager, kind string, name string) []rolloutsmanagerv1alpha1.ResourceOverride {
+	var res []rolloutsmanagerv1alpha1.ResourceOverride
+	for _, override := range cr.Spec.Overrides {
+		if override.Kind == kind && override.Name == name {
+			res = append(res, override)
+		}
+	}
+	return res
+}
+
+// applyResourceOverrides applies, in place, the overrides of the RolloutManager which target obj, the desired state of a resource of the given kind.
+//
+// It returns the strategic merge patch from the desired state built by the operator to the overridden one, or nil when no override targets obj.
+// Unlike the JSON patches of the overrides, which may for example append to a list, this patch is idempotent: it is used to apply the overrides
+// to the live resource, and to detect that a field set by an override was changed outside of the operator.
+func applyResourceOverrides(cr rolloutsmanagerv1alpha1.RolloutManager, kind string, obj client.Object) ([]byte, error) {
+
+	overrides := getResourceOverrides(cr, kind, obj.GetName())
+	if len(overrides) == 0 {
+		return nil, nil
+	}
+
+	original, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	modified := original
+	for _, override := range overrides {
+		patch, err := yaml.YAMLToJSON([]byte(override.Patch))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the override of %s %s: %w", kind, obj.GetName(), err)
+		}
+
+		if override.Type == rolloutsmanagerv1alpha1.ResourceOverridePatchTypeJSON {
+			jsonPatch, err := jsonpatch.DecodePatch(patch)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse the override of %s %s: %w", kind, obj.GetName(), err)
+			}
+			modified, err = jsonPatch.Apply(modified)
+			if err != nil {
+				return nil, fmt.Errorf("unable to apply the override of %s %s: %w", kind, obj.GetName(), err)
+			}
+		} else {
+			modified, err = strategicpatch.StrategicMergePatch(modified, patch, obj)
+			if err != nil {
+				return nil, fmt.Errorf("unable to apply the override of %s %s: %w", kind, obj.GetName(), err)
+			}
+		}
+	}
+
+	overridesPatch, err := strategicpatch.CreateTwoWayMergePatch(original, modified, obj)
+	if err != nil {
+		return nil, fmt.Errorf("unable to apply the overrides of %s %s: %w", kind, obj.GetName(), err)
+	}
+
+	if err := replaceObject(obj, modified); err != nil {
+		return nil, fmt.Errorf("unable to apply the overrides of %s %s: %w", kind, obj.GetName(), err)
+	}
+
+	return overridesPatch, nil
+}
+
+// applyResourceOverridesToLiveObject applies the patch returned by applyResourceOverrides to a live resource, in place, once the operator has set
+// the fields that it manages on it. It returns true if the live resource now differs from original, its state on the cluster, and so needs to be updated.
+// Comparing with the state on the cluster, rather than with the state built by the operator, avoids updating the resource on every reconciliation when
+// an override changes a field that is also managed by the operator.
+func applyResourceOverridesToLiveObject(original client.Object, live client.Object, overridesPatch []byte) (bool, error) {
+
+	if err := applyResourceOverridesPatch(live, overridesPatch); err != nil {
+		return false, err
+	}
+
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return false, err
+	}
+	liveJSON, err := json.Marshal(live)
+	if err != nil {
+		return false, err
+	}
+
+	equal, err := jsonEqual(originalJSON, liveJSON)
+	if err != nil {
+		return false, err
+	}
+	return !equal, nil
+}
+
+// applyResourceOverridesPatch applies the patch returned by applyResourceOverrides to obj, in place.
+func applyResourceOverridesPatch(obj client.Object, overridesPatch []byte) error {
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(data, overridesPatch, obj)
+	if err != nil {
+		return fmt.Errorf("unable to apply the overrides of %s: %w", obj.GetName(), err)
+	}
+
+	if err := replaceObject(obj, patched); err != nil {
+		return fmt.Errorf("unable to apply the overrides of %s: %w", obj.GetName(), err)
+	}
+	return nil
+}
+
+// replaceObject replaces the content of obj with the JSON representation data.
+func replaceObject(obj client.Object, data []byte) error {
+	res := reflect.New(reflect.TypeOf(obj).Elem())
+	if err := json.Unmarshal(data, res.Interface()); err != nil {
+		return err
+	}
+	reflect.ValueOf(obj).Elem().Set(res.Elem())
+	return nil
+}
+
+// jsonEqual returns true if the JSON documents x and y are semantically equal, regardless of the order of their keys.
+func jsonEqual(x []byte, y []byte) (bool, error) {
+	var xValue, yValue interface{}
+	if err := json.Unmarshal(x, &xValue); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(y, &yValue); err != nil {
+		return false, err
+	}
+	return reflect.DeepEqual(xValue, yValue), nil
+}
+
+// validateRolloutsOverrides verifies that every override of the RolloutManager targets a resource managed by the operator, and holds a valid patch.
+func validateRolloutsOverrides(cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	if errs := rolloutsmanagerv1alpha1.ValidateOverrides(field.NewPath("spec", "overrides"), cr.Spec.Overrides); len(errs) > 0 {
+		return fieldValidationFailure(UnsupportedOverrideConfiguration, errs)
+	}
+
+	return nil, nil
+}
+
+func invalidOverrideConfiguration(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), UnsupportedOverrideConfiguration)
+}
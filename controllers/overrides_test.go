@@ -0,0 +1,220 @@
+package rollouts
+
+import (
+	"context"
+
+	"github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("RolloutManager overrides tests", func() {
+	var (
+		ctx context.Context
+		a   v1alpha1.RolloutManager
+		r   *RolloutManagerReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		a = *makeTestRolloutManager()
+		r = makeTestReconciler(&a)
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+	})
+
+	It("should apply a strategic merge patch to the Deployment, and revert changes to the fields it sets", func() {
+		a.Spec.Overrides = []v1alpha1.ResourceOverride{{
+			Kind: "Deployment",
+			Name: DefaultArgoRolloutsResourceName,
+			Patch: `
+spec:
+  revisionHistoryLimit: 3
+  template:
+    spec:
+      hostAliases:
+      - ip: 10.0.0.1
+        hostnames: [metrics.example.com]
+      containers:
+      - name: argo-rollouts
+        args: [--loglevel, debug]
+`,
+		}}
+
+		sa, err := r.reconcileRolloutsServiceAccount(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+		deployment := &appsv1.Deployment{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(*deployment.Spec.RevisionHistoryLimit).To(Equal(int32(3)))
+		Expect(deployment.Spec.Template.Spec.HostAliases).To(Equal([]corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"metrics.example.com"}}}))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"--loglevel", "debug"}))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Image).To(Equal(getRolloutsContainerImage(a)), "fields that are not overridden should be kept")
+
+		By("verifying that the Deployment is not updated when nothing changed")
+		resourceVersion := deployment.ResourceVersion
+		Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(deployment.ResourceVersion).To(Equal(resourceVersion))
+
+		By("modifying the fields set by the override, outside of the operator")
+		deployment.Spec.RevisionHistoryLimit = nil
+		deployment.Spec.Template.Spec.HostAliases = nil
+		deployment.Spec.Template.Spec.Containers[0].Args = nil
+		Expect(r.Client.Update(ctx, deployment)).To(Succeed())
+
+		Expect(r.reconcileRolloutsDeployment(ctx, a, *sa)).To(Succeed())
+
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, deployment)).To(Succeed())
+		Expect(*deployment.Spec.RevisionHistoryLimit).To(Equal(int32(3)))
+		Expect(deployment.Spec.Template.Spec.HostAliases).To(HaveLen(1))
+		Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(Equal([]string{"--loglevel", "debug"}))
+	})
+
+	It("should apply a JSON patch to the Role, without updating it on every reconciliation", func() {
+		a.Spec.Overrides = []v1alpha1.ResourceOverride{{
+			Kind:  "Role",
+			Name:  DefaultArgoRolloutsResourceName,
+			Type:  v1alpha1.ResourceOverridePatchTypeJSON,
+			Patch: `[{"op": "add", "path": "/rules/-", "value": {"apiGroups": ["example.com"], "resources": ["widgets"], "verbs": ["get"]}}]`,
+		}}
+
+		expectedRules := append(GetPolicyRules(), rbacv1.PolicyRule{APIGroups: []string{"example.com"}, Resources: []string{"widgets"}, Verbs: []string{"get"}})
+
+		role, err := r.reconcileRolloutsRole(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, role)).To(Succeed())
+		Expect(role.Rules).To(Equal(expectedRules))
+
+		By("verifying that the Role is not updated when nothing changed")
+		resourceVersion := role.ResourceVersion
+		_, err = r.reconcileRolloutsRole(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, role)).To(Succeed())
+		Expect(role.ResourceVersion).To(Equal(resourceVersion))
+
+		By("removing the rule added by the override, outside of the operator")
+		role.Rules = GetPolicyRules()
+		Expect(r.Client.Update(ctx, role)).To(Succeed())
+
+		_, err = r.reconcileRolloutsRole(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, role)).To(Succeed())
+		Expect(role.Rules).To(Equal(expectedRules))
+	})
+
+	It("should apply the overrides of the ServiceAccount, metrics Service and ServiceMonitor", func() {
+		a.Spec.Overrides = []v1alpha1.ResourceOverride{
+			{
+				Kind:  "ServiceAccount",
+				Name:  DefaultArgoRolloutsResourceName,
+				Patch: `{"imagePullSecrets": [{"name": "mirror-credentials"}]}`,
+			},
+			{
+				Kind:  "Service",
+				Name:  DefaultArgoRolloutsMetricsServiceName,
+				Patch: "metadata:\n  annotations:\n    example.com/scrape: 'true'\nspec:\n  ports:\n  - name: metrics\n    port: 8090\n    appProtocol: http\n",
+			},
+			{
+				Kind:  "ServiceMonitor",
+				Name:  DefaultArgoRolloutsResourceName,
+				Patch: "spec:\n  endpoints:\n  - port: metrics\n    interval: 30s\n",
+			},
+		}
+		Expect(r.Client.Create(ctx, &crdv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: serviceMonitorsCRDName}})).To(Succeed())
+
+		serviceAccount, err := r.reconcileRolloutsServiceAccount(ctx, a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(serviceAccount.ImagePullSecrets).To(Equal([]corev1.LocalObjectReference{{Name: "mirror-credentials"}}))
+
+		Expect(r.reconcileRolloutsMetricsServiceAndMonitor(ctx, a)).To(Succeed())
+
+		service := &corev1.Service{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsMetricsServiceName, service)).To(Succeed())
+		Expect(service.Annotations).To(HaveKeyWithValue("example.com/scrape", "true"))
+		Expect(service.Spec.Ports).To(HaveLen(1))
+		Expect(*service.Spec.Ports[0].AppProtocol).To(Equal("http"))
+
+		serviceMonitor := &monitoringv1.ServiceMonitor{}
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, serviceMonitor)).To(Succeed())
+		Expect(serviceMonitor.Spec.Endpoints).To(Equal([]monitoringv1.Endpoint{{Port: "metrics", Interval: "30s"}}))
+
+		By("verifying that the resources are not updated when nothing changed")
+		serviceResourceVersion, serviceMonitorResourceVersion := service.ResourceVersion, serviceMonitor.ResourceVersion
+		Expect(r.reconcileRolloutsMetricsServiceAndMonitor(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsMetricsServiceName, service)).To(Succeed())
+		Expect(service.ResourceVersion).To(Equal(serviceResourceVersion))
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, serviceMonitor)).To(Succeed())
+		Expect(serviceMonitor.ResourceVersion).To(Equal(serviceMonitorResourceVersion))
+
+		By("modifying the ServiceMonitor outside of the operator")
+		serviceMonitor.Spec.Endpoints[0].Interval = "5m"
+		Expect(r.Client.Update(ctx, serviceMonitor)).To(Succeed())
+
+		Expect(r.reconcileRolloutsMetricsServiceAndMonitor(ctx, a)).To(Succeed())
+		Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, serviceMonitor)).To(Succeed())
+		Expect(serviceMonitor.Spec.Endpoints).To(Equal([]monitoringv1.Endpoint{{Port: "metrics", Interval: "30s"}}))
+	})
+
+	It("should return an error when a JSON patch cannot be applied", func() {
+		a.Spec.Overrides = []v1alpha1.ResourceOverride{{
+			Kind:  "ServiceAccount",
+			Name:  DefaultArgoRolloutsResourceName,
+			Type:  v1alpha1.ResourceOverridePatchTypeJSON,
+			Patch: `[{"op": "replace", "path": "/automountServiceAccountToken/missing", "value": false}]`,
+		}}
+		_, err := r.reconcileRolloutsServiceAccount(ctx, a)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("unable to apply the override of ServiceAccount argo-rollouts"))
+	})
+})
+
+var _ = Describe("validateRolloutsOverrides tests", func() {
+
+	DescribeTable("should validate the overrides", func(override v1alpha1.ResourceOverride, expectedErr string) {
+		cr := *makeTestRolloutManager()
+		cr.Spec.Overrides = []v1alpha1.ResourceOverride{override}
+
+		rr, err := validateRolloutsOverrides(cr)
+		if expectedErr == "" {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rr).To(BeNil())
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(expectedErr))
+		Expect(invalidOverrideConfiguration(err)).To(BeTrue())
+		Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+		Expect(*rr.rolloutController).To(Equal(v1alpha1.PhaseFailure))
+	},
+		Entry("valid strategic merge patch", v1alpha1.ResourceOverride{
+			Kind: "Deployment", Name: DefaultArgoRolloutsResourceName, Patch: "spec:\n  revisionHistoryLimit: 3\n",
+		}, ""),
+		Entry("valid JSON patch", v1alpha1.ResourceOverride{
+			Kind: "Service", Name: DefaultArgoRolloutsMetricsServiceName, Type: v1alpha1.ResourceOverridePatchTypeJSON,
+			Patch: `[{"op": "add", "path": "/metadata/annotations/a", "value": "b"}]`,
+		}, ""),
+		Entry("kind that is not managed by the operator", v1alpha1.ResourceOverride{
+			Kind: "ConfigMap", Name: DefaultRolloutsConfigMapName, Patch: "{}",
+		}, "resources of kind ConfigMap are not managed by the operator"),
+		Entry("name that is not managed by the operator", v1alpha1.ResourceOverride{
+			Kind: "Service", Name: DefaultArgoRolloutsResourceName, Patch: "{}",
+		}, "the operator manages the Service argo-rollouts-metrics, not argo-rollouts"),
+		Entry("invalid YAML", v1alpha1.ResourceOverride{
+			Kind: "Deployment", Name: DefaultArgoRolloutsResourceName, Patch: "spec: [",
+		}, "the patch of Deployment argo-rollouts is not valid YAML or JSON"),
+		Entry("strategic merge patch which is not an object", v1alpha1.ResourceOverride{
+			Kind: "Deployment", Name: DefaultArgoRolloutsResourceName, Patch: "- spec",
+		}, "the patch of Deployment argo-rollouts is not a valid strategic merge patch"),
+		Entry("JSON patch which is not a list of operations", v1alpha1.ResourceOverride{
+			Kind: "Deployment", Name: DefaultArgoRolloutsResourceName, Type: v1alpha1.ResourceOverridePatchTypeJSON, Patch: `{"op": "add"}`,
+		}, "the patch of Deployment argo-rollouts is not a valid JSON patch"),
+	)
+})
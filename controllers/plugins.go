@@ -0,0 +1,399 @@
+package rollouts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// PluginFetcherContainerName is the name of the init container which downloads the plugins, when plugin prefetch is enabled.
+	PluginFetcherContainerName = rolloutsmanagerv1alpha1.PluginFetcherContainerName
+
+	// PrefetchedPluginsDirectory is the directory of the plugin-bin volume to which the plugins are downloaded by the init container.
+	// The Argo Rollouts controller copies the plugins from this directory into the plugin-bin directory itself, so they must differ.
+	PrefetchedPluginsDirectory = "/home/argo-rollouts/plugin-bin/prefetched"
+
+	// PluginSourcesDirectory is the directory of the plugin-bin volume under which the sources of the plugins are mounted.
+	PluginSourcesDirectory = "/home/argo-rollouts/plugin-bin/sources"
+
+	// PluginSourceNamePrefix is the prefix of the volume, and init container, names of the plugins that are loaded from a source.
+	PluginSourceNamePrefix = rolloutsmanagerv1alpha1.PluginSourceNamePrefix
+
+	// pluginFetcherScript downloads each plugin passed as a (name, location, sha256) triplet of arguments, and verifies its checksum when set.
+	// Failures are written to the termination log of the container, from which they are reported on the RolloutManager status.
+	pluginFetcherScript = `set -eu
+while [ "$#" -gt 0 ]; do
+  name="$1"; location="$2"; sha256="$3"; shift 3
+  dest="` + PrefetchedPluginsDirectory + `/$name"
+  mkdir -p "$(dirname "$dest")"
+  if ! curl -fsSL --retry 5 --retry-delay 2 -o "$dest" "$location"; then
+    echo "failed to download plugin $name from $location" | tee /dev/termination-log
+    exit 1
+  fi
+  if [ -n "$sha256" ] && ! echo "$sha256  $dest" | sha256sum -c - >/dev/null 2>&1; then
+    echo "the sha256 checksum of plugin $name downloaded from $location does not match $sha256" | tee /dev/termination-log
+    exit 1
+  fi
+  chmod +x "$dest"
+done
+`
+)
+
+// isPluginPrefetchEnabled returns true if the plugins of the RolloutManager are downloaded by an init container.
+func isPluginPrefetchEnabled(cr rolloutsmanagerv1alpha1.RolloutManager) bool {
+	return cr.Spec.Plugins.Prefetch != nil && cr.Spec.Plugins.Prefetch.Enabled
+}
+
+// hasPluginInitContainers returns true if the Argo Rollouts controller pod has init containers which load the plugins.
+func hasPluginInitContainers(cr rolloutsmanagerv1alpha1.RolloutManager) bool {
+	return isPluginPrefetchEnabled(cr) || len(pluginSourceInitContainers(cr)) > 0
+}
+
+// isRemotePluginLocation returns true if the plugin is downloaded from an http(s):// location, rather than read from the filesystem.
+func isRemotePluginLocation(location string) bool {
+	return strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://")
+}
+
+// getPluginLocation returns the location of the plugin to write to the Rollouts ConfigMap: when the plugin is loaded from a source,
+// or downloaded by the init container, this is the file:// location of the plugin in the pod, otherwise the location from the RolloutManager.
+func getPluginLocation(cr rolloutsmanagerv1alpha1.RolloutManager, plugin rolloutsmanagerv1alpha1.Plugin) string {
+	if plugin.Source != nil {
+		return "file://" + getPluginSourceMountPath(plugin) + "/" + getPluginSourceFile(plugin)
+	}
+	if isPluginPrefetchEnabled(cr) && isRemotePluginLocation(plugin.Location) {
+		return "file://" + PrefetchedPluginsDirectory + "/" + plugin.Name
+	}
+	return plugin.Location
+}
+
+// getOpenShiftRoutePlugin returns the OpenShift Route traffic management plugin, which the operator adds to every Rollouts ConfigMap, see reconcileConfigMap.
+func getOpenShiftRoutePlugin(openShiftRoutePluginLocation string) rolloutsmanagerv1alpha1.Plugin {
+	return rolloutsmanagerv1alpha1.Plugin{
+		Name:     OpenShiftRolloutPluginName,
+		Location: openShiftRoutePluginLocation,
+	}
+}
+
+// getUniquePlugins returns the traffic management, metric and step plugins of the RolloutManager, in the same order as in the Rollouts ConfigMap:
+// each plugin type sorted by name. When a plugin name is repeated within a plugin type, only its first definition is used.
+// The OpenShift Route plugin, which is added by the operator rather than by the RolloutManager, is not part of them.
+func getUniquePlugins(cr rolloutsmanagerv1alpha1.RolloutManager) []rolloutsmanagerv1alpha1.Plugin {
+	var res []rolloutsmanagerv1alpha1.Plugin
+
+	for _, plugins := range [][]rolloutsmanagerv1alpha1.Plugin{cr.Spec.Plugins.TrafficManagement, cr.Spec.Plugins.Metric, cr.Spec.Plugins.Step} {
+		pluginsMap := map[string]rolloutsmanagerv1alpha1.Plugin{}
+		for _, plugin := range plugins {
+			// The OpenShift Route plugin may not be set through the CR, see reconcileConfigMap
+			if plugin.Name == OpenShiftRolloutPluginName {
+				continue
+			}
+			if _, exists := pluginsMap[plugin.Name]; !exists {
+				pluginsMap[plugin.Name] = plugin
+			}
+		}
+
+		names := make([]string, 0, len(pluginsMap))
+		for name := range pluginsMap {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			res = append(res, pluginsMap[name])
+		}
+	}
+
+	return res
+}
+
+// getPluginsToPrefetch returns the plugins of the RolloutManager that are downloaded by the init container, starting with the OpenShift Route plugin,
+// so that no plugin is downloaded by the Argo Rollouts controller itself.
+func getPluginsToPrefetch(cr rolloutsmanagerv1alpha1.RolloutManager, openShiftRoutePluginLocation string) []rolloutsmanagerv1alpha1.Plugin {
+	var res []rolloutsmanagerv1alpha1.Plugin
+	for _, plugin := range append([]rolloutsmanagerv1alpha1.Plugin{getOpenShiftRoutePlugin(openShiftRoutePluginLocation)}, getUniquePlugins(cr)...) {
+		if plugin.Source == nil && isRemotePluginLocation(plugin.Location) {
+			res = append(res, plugin)
+		}
+	}
+	return res
+}
+
+// getPluginsWithSource returns the plugins of the RolloutManager that are loaded from an OCI image, a PersistentVolumeClaim, a ConfigMap or a Secret.
+func getPluginsWithSource(cr rolloutsmanagerv1alpha1.RolloutManager) []rolloutsmanagerv1alpha1.Plugin {
+	var res []rolloutsmanagerv1alpha1.Plugin
+	sourceNames := map[string]bool{}
+	for _, plugin := range getUniquePlugins(cr) {
+		// A plugin name used by more than one plugin type is loaded to the same path, as with the plugin-bin directory of the Argo Rollouts controller.
+		if plugin.Source == nil || sourceNames[getPluginSourceName(plugin)] {
+			continue
+		}
+		sourceNames[getPluginSourceName(plugin)] = true
+		res = append(res, plugin)
+	}
+	return res
+}
+
+// getPluginSourceProblem returns a description of what is wrong with the location or source of the plugin, or "" if they are valid.
+func getPluginSourceProblem(plugin rolloutsmanagerv1alpha1.Plugin) string {
+	source := plugin.Source
+
+	if source == nil {
+		if plugin.Location == "" {
+			return "has neither a location nor a source"
+		}
+		return ""
+	}
+
+	if plugin.Location != "" {
+		return "may not have both a location and a source"
+	}
+
+	sourcesSet := 0
+	for _, set := range []bool{source.Image != nil, source.PersistentVolumeClaim != nil, source.ConfigMap != nil, source.Secret != nil} {
+		if set {
+			sourcesSet++
+		}
+	}
+	if sourcesSet != 1 {
+		return "must have exactly one of image, persistentVolumeClaim, configMap and secret in its source"
+	}
+
+	switch {
+	case source.Image != nil && (source.Image.Reference == "" || source.Image.Path == ""):
+		return "must have both a reference and a path in its image source"
+	case source.PersistentVolumeClaim != nil && (source.PersistentVolumeClaim.Name == "" || source.PersistentVolumeClaim.Path == ""):
+		return "must have both a name and a path in its persistentVolumeClaim source"
+	case source.ConfigMap != nil && (source.ConfigMap.Name == "" || source.ConfigMap.Path == ""):
+		return "must have both a name and a path in its configMap source"
+	case source.Secret != nil && (source.Secret.Name == "" || source.Secret.Path == ""):
+		return "must have both a name and a path in its secret source"
+	}
+
+	return ""
+}
+
+// getPluginSourceName returns the name of the volume, and of the init container when loaded from an image, of a plugin with a source.
+// Plugin names are not valid volume names, so a hash of the name is used instead.
+func getPluginSourceName(plugin rolloutsmanagerv1alpha1.Plugin) string {
+	hash := sha256.Sum256([]byte(plugin.Name))
+	return PluginSourceNamePrefix + hex.EncodeToString(hash[:])[:12]
+}
+
+// getPluginSourceMountPath returns the directory at which the source of the plugin is mounted in the pod.
+func getPluginSourceMountPath(plugin rolloutsmanagerv1alpha1.Plugin) string {
+	return PluginSourcesDirectory + "/" + getPluginSourceName(plugin)
+}
+
+// getPluginSourceFile returns the path of the plugin executable, relative to the mount path of its source.
+func getPluginSourceFile(plugin rolloutsmanagerv1alpha1.Plugin) string {
+	source := plugin.Source
+	switch {
+	case source.Image != nil:
+		return path.Base(source.Image.Path)
+	case source.PersistentVolumeClaim != nil:
+		return strings.TrimPrefix(source.PersistentVolumeClaim.Path, "/")
+	case source.ConfigMap != nil:
+		return source.ConfigMap.Path
+	case source.Secret != nil:
+		return source.Secret.Path
+	}
+	return ""
+}
+
+// pluginSourceVolumes returns the volumes of the plugins that are loaded from a source.
+func pluginSourceVolumes(cr rolloutsmanagerv1alpha1.RolloutManager) []corev1.Volume {
+
+	// NOTE: When updating this function, ensure that normalizeDeployment is updated as well. See that function for details.
+
+	var res []corev1.Volume
+
+	// The mode is set explicitly, as it would otherwise be defaulted to a non-executable mode by the API server.
+	executableMode := int32(0555)
+
+	for _, plugin := range getPluginsWithSource(cr) {
+		volume := corev1.Volume{Name: getPluginSourceName(plugin)}
+		source := plugin.Source
+
+		switch {
+		case source.Image != nil:
+			// The init container copies the plugin out of the image into this volume
+			volume.VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+		case source.PersistentVolumeClaim != nil:
+			volume.VolumeSource = corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: source.PersistentVolumeClaim.Name,
+				ReadOnly:  true,
+			}}
+		case source.ConfigMap != nil:
+			volume.VolumeSource = corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: source.ConfigMap.Name},
+				Items:                []corev1.KeyToPath{{Key: source.ConfigMap.Path, Path: source.ConfigMap.Path}},
+				DefaultMode:          &executableMode,
+			}}
+		case source.Secret != nil:
+			volume.VolumeSource = corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+				SecretName:  source.Secret.Name,
+				Items:       []corev1.KeyToPath{{Key: source.Secret.Path, Path: source.Secret.Path}},
+				DefaultMode: &executableMode,
+			}}
+		default:
+			continue
+		}
+
+		res = append(res, volume)
+	}
+
+	return res
+}
+
+// pluginSourceVolumeMounts returns the volume mounts of the Argo Rollouts controller container for the plugins that are loaded from a source.
+func pluginSourceVolumeMounts(cr rolloutsmanagerv1alpha1.RolloutManager) []corev1.VolumeMount {
+
+	// NOTE: When updating this function, ensure that normalizeDeployment is updated as well. See that function for details.
+
+	var res []corev1.VolumeMount
+	for _, volume := range pluginSourceVolumes(cr) {
+		res = append(res, corev1.VolumeMount{
+			Name:      volume.Name,
+			MountPath: PluginSourcesDirectory + "/" + volume.Name,
+			ReadOnly:  true,
+		})
+	}
+	return res
+}
+
+// pluginSourceInitContainers returns the init containers which copy the plugins that are loaded from an OCI image into their volume.
+func pluginSourceInitContainers(cr rolloutsmanagerv1alpha1.RolloutManager) []corev1.Container {
+
+	// NOTE: When updating this function, ensure that normalizeDeployment is updated as well. See that function for details.
+
+	var res []corev1.Container
+	for _, plugin := range getPluginsWithSource(cr) {
+		if plugin.Source.Image == nil {
+			continue
+		}
+
+		mountPath := getPluginSourceMountPath(plugin)
+
+		res = append(res, corev1.Container{
+			Name:            getPluginSourceName(plugin),
+			Image:           plugin.Source.Image.Reference,
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Command:         []string{"cp", plugin.Source.Image.Path, mountPath + "/" + getPluginSourceFile(plugin)},
+			Args:            make([]string, 0),
+			SecurityContext: pluginInitContainerSecurityContext(),
+			VolumeMounts: []corev1.VolumeMount{
+				{
+					MountPath: mountPath,
+					Name:      getPluginSourceName(plugin),
+				},
+			},
+		})
+	}
+	return res
+}
+
+// getPluginFetcherImage returns the image of the init container which downloads the plugins.
+func getPluginFetcherImage(cr rolloutsmanagerv1alpha1.RolloutManager) string {
+	if cr.Spec.Plugins.Prefetch != nil && cr.Spec.Plugins.Prefetch.Image != "" {
+		return cr.Spec.Plugins.Prefetch.Image
+	}
+	return DefaultPluginFetcherImage
+}
+
+// pluginFetcherContainer returns the init container which downloads the plugins into the plugin-bin volume.
+func pluginFetcherContainer(cr rolloutsmanagerv1alpha1.RolloutManager, openShiftRoutePluginLocation string) corev1.Container {
+
+	// NOTE: When updating this function, ensure that normalizeDeployment is updated as well. See that function for details.
+
+	args := make([]string, 0)
+	for _, plugin := range getPluginsToPrefetch(cr, openShiftRoutePluginLocation) {
+		args = append(args, plugin.Name, plugin.Location, plugin.SHA256)
+	}
+
+	return corev1.Container{
+		Name:            PluginFetcherContainerName,
+		Image:           getPluginFetcherImage(cr),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		// The plugins are passed as positional arguments of the script, rather than being part of the script itself, so that they are never interpreted by the shell.
+		Command:         []string{"sh", "-c", pluginFetcherScript, PluginFetcherContainerName},
+		Args:            args,
+		SecurityContext: pluginInitContainerSecurityContext(),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				MountPath: "/home/argo-rollouts/plugin-bin",
+				Name:      "plugin-bin",
+			},
+		},
+	}
+}
+
+// pluginInitContainerSecurityContext returns the security context of the init containers which load the plugins.
+func pluginInitContainerSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{
+				"ALL",
+			},
+		},
+		AllowPrivilegeEscalation: boolPtr(false),
+		ReadOnlyRootFilesystem:   boolPtr(true),
+		RunAsNonRoot:             boolPtr(true),
+		RunAsUser:                int64Ptr(DefaultPluginInitContainerUserID),
+		RunAsGroup:               int64Ptr(DefaultPluginInitContainerUserID),
+		SeccompProfile: &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		},
+	}
+}
+
+// getPluginFetchFailure returns a message describing why an init container of a Rollouts controller pod failed to load the plugins,
+// or "" if none failed.
+func (r *RolloutManagerReconciler) getPluginFetchFailure(ctx context.Context, deployment appsv1.Deployment) (string, error) {
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, client.InNamespace(deployment.Namespace), client.MatchingLabels(deployment.Spec.Selector.MatchLabels)); err != nil {
+		return "", fmt.Errorf("failed to list Rollouts Pods: %w", err)
+	}
+
+	for _, pod := range podList.Items {
+		for _, status := range pod.Status.InitContainerStatuses {
+			if status.Name != PluginFetcherContainerName && !strings.HasPrefix(status.Name, PluginSourceNamePrefix) {
+				continue
+			}
+
+			if status.State.Running != nil {
+				continue
+			}
+
+			// While the container waits to be restarted after a failure, the failure is found in its last state.
+			terminated := status.State.Terminated
+			if status.State.Waiting != nil {
+				terminated = status.LastTerminationState.Terminated
+			}
+			if terminated != nil && terminated.ExitCode != 0 {
+				message := strings.TrimSpace(terminated.Message)
+				if message == "" {
+					message = fmt.Sprintf("exit code %d", terminated.ExitCode)
+				}
+				return fmt.Sprintf("the %s init container of Pod %s failed to load the plugins: %s", status.Name, pod.Name, message), nil
+			}
+
+			// The image of the init container could not be pulled, or the container could not be created.
+			if waiting := status.State.Waiting; terminated == nil && waiting != nil && waiting.Reason != "" && waiting.Reason != "PodInitializing" {
+				return fmt.Sprintf("the %s init container of Pod %s is waiting: %s: %s", status.Name, pod.Name, waiting.Reason, waiting.Message), nil
+			}
+		}
+	}
+
+	return "", nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -19,19 +20,37 @@ type reconcileStatusResult struct {
 
 	// phase: if non-nil, .status.phase will be set to this value, after call to reconcileRolloutsManager
 	phase *rolloutsmanagerv1alpha1.RolloutControllerPhase
+
+	// conditions: additional conditions, such as the CRDVersionSkew condition and the conditions of the reconcile steps, to be set on RolloutManager's .status.conditions, after call to reconcileRolloutsManager
+	conditions []metav1.Condition
 }
 
-func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (reconcileStatusResult, error) {
+func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (res reconcileStatusResult, err error) {
+
+	// step is the condition type of the reconcile step in progress: the step that returned is reported as failed (if its condition is not successful), and the following steps as not reconciled.
+	// It is cleared once all the steps have been reconciled.
+	step := rolloutsmanagerv1alpha1.RolloutManagerConfigurationValidConditionType
+
+	// timer measures the duration of the sub-steps, such as the reconciliation of the ServiceAccount: the sub-step in progress when returning with an error is the one which failed.
+	timer := &reconcileStepTimer{}
+
+	defer func() {
+		timer.end(err)
+		res.conditions = append(res.conditions, createStepConditions(step, res.condition)...)
+	}()
 
 	log.Info("validating RolloutManager's scope")
+	timer.startStep(reconcileStepValidation)
 	if rr, err := validateRolloutsScope(cr, r.NamespaceScopedArgoRolloutsController); err != nil {
 		if invalidRolloutScope(err) {
 			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidScoped)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
 			return *rr, nil
 		}
 
 		if invalidRolloutNamespace(err) {
 			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidNamespace)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
 			return *rr, nil
 		}
 
@@ -39,11 +58,137 @@ func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context,
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
+	log.Info("validating RolloutManager's HA configuration")
+	if rr, err := validateRolloutsHA(cr); err != nil {
+		if invalidHAConfiguration(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidHAConfiguration)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's HA configuration.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	log.Info("validating RolloutManager's notification configuration")
+	if rr, err := validateRolloutsNotifications(cr); err != nil {
+		if invalidNotificationConfiguration(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidNotificationConfiguration)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's notification configuration.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	log.Info("validating RolloutManager's controller configuration")
+	if rr, err := validateRolloutsControllerConfiguration(cr); err != nil {
+		if invalidControllerConfiguration(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidControllerConfiguration)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's controller configuration.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	log.Info("validating RolloutManager's extra command arguments")
+	if rr, err := validateRolloutsExtraCommandArgs(cr); err != nil {
+		if invalidExtraCommandArgs(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidExtraCommandArgs)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's extra command arguments.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	log.Info("validating RolloutManager's plugin configuration")
+	if rr, err := validateRolloutsPlugins(cr); err != nil {
+		if invalidPluginConfiguration(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidPluginConfiguration)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's plugin configuration.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	log.Info("validating RolloutManager's pod configuration")
+	if rr, err := validateRolloutsPodConfiguration(cr); err != nil {
+		if invalidPodConfiguration(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidPodConfiguration)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's pod configuration.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	log.Info("validating RolloutManager's overrides")
+	if rr, err := validateRolloutsOverrides(cr); err != nil {
+		if invalidOverrideConfiguration(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidOverrideConfiguration)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's overrides.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	log.Info("validating RolloutManager's CRD management")
+	if rr, err := validateRolloutsCRDManagement(cr); err != nil {
+		if invalidCRDManagementConfiguration(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidCRDManagementConfiguration)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's CRD management.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	log.Info("validating RolloutManager's environment variable sources")
+	if rr, err := validateRolloutsEnvSources(ctx, r.Client, cr); err != nil {
+		if missingEnvSource(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonMissingEnvSource)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's environment variable sources.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	log.Info("validating RolloutManager's notification sources")
+	if rr, err := validateRolloutsNotificationSources(ctx, r.Client, cr); err != nil {
+		if missingNotificationSource(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonMissingNotificationSource)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+		if invalidNotificationConfiguration(err) {
+			rr.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidNotificationConfiguration)
+			r.recordEvent(&cr, corev1.EventTypeWarning, rr.condition.Reason, rr.condition.Message)
+			return *rr, nil
+		}
+
+		log.Error(err, "failed to validate RolloutManager's notification sources.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
 	log.Info("searching for existing RolloutManagers")
 	if res, err := checkForExistingRolloutManager(ctx, r.Client, cr); err != nil {
 		if multipleRolloutManagersExist(err) {
 
 			res.condition = createCondition(err.Error(), rolloutsmanagerv1alpha1.RolloutManagerReasonMultipleClusterScopedRolloutManager)
+			r.recordEvent(&cr, corev1.EventTypeWarning, res.condition.Reason, res.condition.Message)
 
 			return *res, nil
 		}
@@ -51,7 +196,20 @@ func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context,
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
+	step = rolloutsmanagerv1alpha1.RolloutManagerCRDsReconciledConditionType
+
+	log.Info("reconciling Rollouts CRDs")
+	timer.startStep(reconcileStepCRDs)
+	crdVersionSkewCondition, err := r.reconcileRolloutsCRDs(ctx, cr)
+	if err != nil {
+		log.Error(err, "failed to reconcile Rollouts CRDs.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	step = rolloutsmanagerv1alpha1.RolloutManagerRBACReconciledConditionType
+
 	log.Info("reconciling Rollouts ServiceAccount")
+	timer.startStep(reconcileStepServiceAccount)
 	sa, err := r.reconcileRolloutsServiceAccount(ctx, cr)
 	if err != nil {
 		log.Error(err, "failed to reconcile Rollout's ServiceAccount.")
@@ -63,6 +221,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context,
 
 	if cr.Spec.NamespaceScoped {
 		log.Info("reconciling Rollouts Roles")
+		timer.startStep(reconcileStepRole)
 		role, err = r.reconcileRolloutsRole(ctx, cr)
 		if err != nil {
 			log.Error(err, "failed to reconcile Rollout's Role.")
@@ -70,6 +229,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context,
 		}
 	} else {
 		log.Info("reconciling Rollouts ClusterRoles")
+		timer.startStep(reconcileStepClusterRole)
 		clusterRole, err = r.reconcileRolloutsClusterRole(ctx, cr)
 		if err != nil {
 			log.Error(err, "failed to reconcile Rollout's ClusterRoles.")
@@ -78,18 +238,21 @@ func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context,
 	}
 
 	log.Info("reconciling aggregate-to-admin ClusterRole")
+	timer.startStep(reconcileStepAggregateToAdminClusterRole)
 	if err := r.reconcileRolloutsAggregateToAdminClusterRole(ctx, cr); err != nil {
 		log.Error(err, "failed to reconcile Rollout's aggregate-to-admin ClusterRoles.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
 	log.Info("reconciling aggregate-to-edit ClusterRole")
+	timer.startStep(reconcileStepAggregateToEditClusterRole)
 	if err := r.reconcileRolloutsAggregateToEditClusterRole(ctx, cr); err != nil {
 		log.Error(err, "failed to reconcile Rollout's aggregate-to-edit ClusterRoles.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
 	log.Info("reconciling aggregate-to-view ClusterRole")
+	timer.startStep(reconcileStepAggregateToViewClusterRole)
 	if err := r.reconcileRolloutsAggregateToViewClusterRole(ctx, cr); err != nil {
 		log.Error(err, "failed to reconcile Rollout's aggregate-to-view ClusterRoles.")
 		return wrapCondition(createCondition(err.Error())), err
@@ -97,50 +260,100 @@ func (r *RolloutManagerReconciler) reconcileRolloutsManager(ctx context.Context,
 
 	if cr.Spec.NamespaceScoped {
 		log.Info("reconciling Rollouts RoleBindings")
+		timer.startStep(reconcileStepRoleBinding)
 		if err := r.reconcileRolloutsRoleBinding(ctx, cr, role, sa); err != nil {
 			log.Error(err, "failed to reconcile Rollout's RoleBindings.")
 			return wrapCondition(createCondition(err.Error())), err
 		}
 	} else {
 		log.Info("reconciling Rollouts ClusterRoleBinding")
+		timer.startStep(reconcileStepClusterRoleBinding)
 		if err := r.reconcileRolloutsClusterRoleBinding(ctx, clusterRole, sa, cr); err != nil {
 			log.Error(err, "failed to reconcile Rollout's ClusterRoleBinding.")
 			return wrapCondition(createCondition(err.Error())), err
 		}
 	}
 
+	step = rolloutsmanagerv1alpha1.RolloutManagerNotificationsReconciledConditionType
+
 	log.Info("reconciling Rollouts Secret")
+	timer.startStep(reconcileStepNotificationSecret)
 	if err := r.reconcileRolloutsSecrets(ctx, cr); err != nil {
 		log.Error(err, "failed to reconcile Rollout's Secret.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
+	log.Info("reconciling Rollouts notification ConfigMap")
+	timer.startStep(reconcileStepNotificationConfigMap)
+	if err := r.reconcileRolloutsNotificationConfigMap(ctx, cr); err != nil {
+		log.Error(err, "failed to reconcile Rollout's notification ConfigMap.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	step = rolloutsmanagerv1alpha1.RolloutManagerConfigMapReconciledConditionType
+
 	log.Info("reconciling ConfigMap for plugins")
+	timer.startStep(reconcileStepConfigMap)
 	if err := r.reconcileConfigMap(ctx, cr); err != nil {
 		log.Error(err, "failed to reconcile Rollout's ConfigMap.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
+	step = rolloutsmanagerv1alpha1.RolloutManagerDeploymentReconciledConditionType
+
 	log.Info("reconciling Rollouts Deployment")
+	timer.startStep(reconcileStepDeployment)
 	if err := r.reconcileRolloutsDeployment(ctx, cr, *sa); err != nil {
 		log.Error(err, "failed to reconcile Rollout's Deployment.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
+	step = rolloutsmanagerv1alpha1.RolloutManagerPodDisruptionBudgetReconciledConditionType
+
+	log.Info("reconciling Rollouts PodDisruptionBudget")
+	timer.startStep(reconcileStepPodDisruptionBudget)
+	if err := r.reconcileRolloutsPodDisruptionBudget(ctx, cr); err != nil {
+		log.Error(err, "failed to reconcile Rollout's PodDisruptionBudget.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	step = rolloutsmanagerv1alpha1.RolloutManagerMetricsReconciledConditionType
+
 	log.Info("reconciling Rollouts Metrics Service")
+	timer.startStep(reconcileStepMetricsServiceAndMonitor)
 	if err := r.reconcileRolloutsMetricsServiceAndMonitor(ctx, cr); err != nil {
 		log.Error(err, "failed to reconcile Rollout's Metrics Service.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
+	step = rolloutsmanagerv1alpha1.RolloutManagerDashboardReconciledConditionType
+
+	log.Info("reconciling Rollouts dashboard")
+	timer.startStep(reconcileStepDashboard)
+	if err := r.reconcileRolloutsDashboard(ctx, cr); err != nil {
+		log.Error(err, "failed to reconcile Rollout's dashboard.")
+		return wrapCondition(createCondition(err.Error())), err
+	}
+
+	// All the steps were reconciled: a failure found while determining the status of the workloads is only reported by the Reconciled condition.
+	step = ""
+
 	log.Info("reconciling status of workloads")
+	timer.startStep(reconcileStepStatus)
 	rr, err := r.determineStatusPhase(ctx, cr)
 	if err != nil {
 		log.Error(err, "failed to reconcile status of workloads.")
 		return wrapCondition(createCondition(err.Error())), err
 	}
 
-	rr.condition = createCondition("") // success
+	if crdVersionSkewCondition != nil {
+		rr.conditions = append(rr.conditions, *crdVersionSkewCondition)
+	}
+
+	// A failure found while determining the status of the workloads takes precedence over the success condition.
+	if rr.condition.Reason == "" {
+		rr.condition = createCondition("") // success
+	}
 
 	return rr, nil
 }
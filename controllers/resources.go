@@ -1,13 +1,17 @@
 package rollouts
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	monitoringv1 "github.com/coreos/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -27,20 +31,27 @@ func (r *RolloutManagerReconciler) reconcileRolloutsServiceAccount(ctx context.C
 	}
 	setRolloutsLabelsAndAnnotationsToObject(&expectedServiceAccount.ObjectMeta, cr)
 
+	overriddenServiceAccount := expectedServiceAccount.DeepCopy()
+	overridesPatch, err := applyResourceOverrides(cr, "ServiceAccount", overriddenServiceAccount)
+	if err != nil {
+		return nil, err
+	}
+
 	liveServiceAccount := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: expectedServiceAccount.Name, Namespace: expectedServiceAccount.Namespace}}
 	if err := fetchObject(ctx, r.Client, cr.Namespace, liveServiceAccount.Name, liveServiceAccount); err != nil {
 		if !apierrors.IsNotFound(err) {
 			return nil, fmt.Errorf("failed to get the ServiceAccount associated with %s: %w", liveServiceAccount.Name, err)
 		}
 
-		if err := controllerutil.SetControllerReference(&cr, expectedServiceAccount, r.Scheme); err != nil {
+		if err := controllerutil.SetControllerReference(&cr, overriddenServiceAccount, r.Scheme); err != nil {
 			return nil, err
 		}
 
-		log.Info(fmt.Sprintf("Creating ServiceAccount %s", expectedServiceAccount.Name))
-		return expectedServiceAccount, r.Client.Create(ctx, expectedServiceAccount)
+		log.Info(fmt.Sprintf("Creating ServiceAccount %s", overriddenServiceAccount.Name))
+		return overriddenServiceAccount, r.createResource(ctx, &cr, overriddenServiceAccount)
 	}
 
+	originalServiceAccount := liveServiceAccount.DeepCopy()
 	updateNeeded := false
 
 	normalizedLiveServiceAccount := liveServiceAccount.DeepCopy()
@@ -54,9 +65,15 @@ func (r *RolloutManagerReconciler) reconcileRolloutsServiceAccount(ctx context.C
 		liveServiceAccount.Annotations = combineStringMaps(liveServiceAccount.Annotations, expectedServiceAccount.Annotations)
 	}
 
+	if overridesPatch != nil {
+		if updateNeeded, err = applyResourceOverridesToLiveObject(originalServiceAccount, liveServiceAccount, overridesPatch); err != nil {
+			return nil, err
+		}
+	}
+
 	if updateNeeded {
 		// Update if the Role already exists and needs to be modified
-		return liveServiceAccount, r.Client.Update(ctx, liveServiceAccount)
+		return liveServiceAccount, r.updateResource(ctx, &cr, liveServiceAccount)
 	}
 
 	return liveServiceAccount, nil
@@ -74,6 +91,13 @@ func (r *RolloutManagerReconciler) reconcileRolloutsRole(ctx context.Context, cr
 	}
 	setRolloutsLabelsAndAnnotationsToObject(&expectedRole.ObjectMeta, cr)
 
+	overriddenRole := expectedRole.DeepCopy()
+	overriddenRole.Rules = expectedPolicyRules
+	overridesPatch, err := applyResourceOverrides(cr, "Role", overriddenRole)
+	if err != nil {
+		return nil, err
+	}
+
 	liveRole := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: expectedRole.Name, Namespace: expectedRole.Namespace}}
 
 	if err := fetchObject(ctx, r.Client, cr.Namespace, liveRole.Name, liveRole); err != nil {
@@ -81,15 +105,15 @@ func (r *RolloutManagerReconciler) reconcileRolloutsRole(ctx context.Context, cr
 			return nil, fmt.Errorf("failed to reconcile the Role for the ServiceAccount associated with %s: %w", liveRole.Name, err)
 		}
 
-		if err = controllerutil.SetControllerReference(&cr, expectedRole, r.Scheme); err != nil {
+		if err = controllerutil.SetControllerReference(&cr, overriddenRole, r.Scheme); err != nil {
 			return nil, err
 		}
 
-		log.Info(fmt.Sprintf("Creating Role %s", expectedRole.Name))
-		expectedRole.Rules = expectedPolicyRules
-		return expectedRole, r.Client.Create(ctx, expectedRole)
+		log.Info(fmt.Sprintf("Creating Role %s", overriddenRole.Name))
+		return overriddenRole, r.createResource(ctx, &cr, overriddenRole)
 	}
 
+	originalRole := liveRole.DeepCopy()
 	updateNeeded := false
 
 	if !reflect.DeepEqual(liveRole.Rules, expectedPolicyRules) {
@@ -111,9 +135,15 @@ func (r *RolloutManagerReconciler) reconcileRolloutsRole(ctx context.Context, cr
 		liveRole.Annotations = combineStringMaps(liveRole.Annotations, expectedRole.Annotations)
 	}
 
+	if overridesPatch != nil {
+		if updateNeeded, err = applyResourceOverridesToLiveObject(originalRole, liveRole, overridesPatch); err != nil {
+			return nil, err
+		}
+	}
+
 	if updateNeeded {
 		// Update if the Role already exists and needs to be modified
-		return liveRole, r.Client.Update(ctx, liveRole)
+		return liveRole, r.updateResource(ctx, &cr, liveRole)
 	}
 
 	return liveRole, nil
@@ -129,6 +159,14 @@ func (r *RolloutManagerReconciler) reconcileRolloutsClusterRole(ctx context.Cont
 		},
 	}
 	setRolloutsLabelsAndAnnotationsToObject(&expectedClusterRole.ObjectMeta, cr)
+
+	overriddenClusterRole := expectedClusterRole.DeepCopy()
+	overriddenClusterRole.Rules = expectedPolicyRules
+	overridesPatch, err := applyResourceOverrides(cr, "ClusterRole", overriddenClusterRole)
+	if err != nil {
+		return nil, err
+	}
+
 	liveClusterRole := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: expectedClusterRole.Name, Namespace: expectedClusterRole.Namespace}}
 	if err := fetchObject(ctx, r.Client, "", liveClusterRole.Name, liveClusterRole); err != nil {
 		if !apierrors.IsNotFound(err) {
@@ -136,10 +174,10 @@ func (r *RolloutManagerReconciler) reconcileRolloutsClusterRole(ctx context.Cont
 		}
 
 		log.Info(fmt.Sprintf("Creating ClusterRole %s", liveClusterRole.Name))
-		expectedClusterRole.Rules = expectedPolicyRules
-		return expectedClusterRole, r.Client.Create(ctx, expectedClusterRole)
+		return overriddenClusterRole, r.createResource(ctx, &cr, overriddenClusterRole)
 	}
 
+	originalClusterRole := liveClusterRole.DeepCopy()
 	updateNeeded := false
 
 	if !reflect.DeepEqual(liveClusterRole.Rules, expectedPolicyRules) {
@@ -159,9 +197,15 @@ func (r *RolloutManagerReconciler) reconcileRolloutsClusterRole(ctx context.Cont
 		liveClusterRole.Annotations = combineStringMaps(liveClusterRole.Annotations, expectedClusterRole.Annotations)
 	}
 
+	if overridesPatch != nil {
+		if updateNeeded, err = applyResourceOverridesToLiveObject(originalClusterRole, liveClusterRole, overridesPatch); err != nil {
+			return nil, err
+		}
+	}
+
 	if updateNeeded {
 		// Update if the ClusterRole already exists and needs to be modified
-		return liveClusterRole, r.Client.Update(ctx, liveClusterRole)
+		return liveClusterRole, r.updateResource(ctx, &cr, liveClusterRole)
 	}
 	return liveClusterRole, nil
 }
@@ -211,7 +255,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsRoleBinding(ctx context.Cont
 		}
 
 		log.Info(fmt.Sprintf("Creating RoleBinding %s", expectedRoleBinding.Name))
-		return r.Client.Create(ctx, expectedRoleBinding)
+		return r.createResource(ctx, &cr, expectedRoleBinding)
 	}
 
 	updateNeeded := false
@@ -236,7 +280,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsRoleBinding(ctx context.Cont
 
 	if updateNeeded {
 		// Update if the RoleBinding already exists and needs to be modified
-		if err := r.Client.Update(ctx, liveRoleBinding); err != nil {
+		if err := r.updateResource(ctx, &cr, liveRoleBinding); err != nil {
 			return err
 		}
 	}
@@ -284,7 +328,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsClusterRoleBinding(ctx conte
 		}
 
 		log.Info(fmt.Sprintf("Creating ClusterRoleBinding %s", expectedClusterRoleBinding.Name))
-		return r.Client.Create(ctx, expectedClusterRoleBinding)
+		return r.createResource(ctx, &cr, expectedClusterRoleBinding)
 	}
 
 	updateNeeded := false
@@ -307,7 +351,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsClusterRoleBinding(ctx conte
 
 	if updateNeeded {
 		// Update if the ClusterRoleBinding already exists and needs to be modified
-		if err := r.Client.Update(ctx, liveClusterRoleBinding); err != nil {
+		if err := r.updateResource(ctx, &cr, liveClusterRoleBinding); err != nil {
 			return err
 		}
 	}
@@ -316,7 +360,8 @@ func (r *RolloutManagerReconciler) reconcileRolloutsClusterRoleBinding(ctx conte
 }
 
 // removeClusterScopedResourcesIfApplicable will remove the ClusterRole and ClusterRoleBinding that are created when a cluster-scoped RolloutManager is created.
-func (r *RolloutManagerReconciler) removeClusterScopedResourcesIfApplicable(ctx context.Context) error {
+// The deletions are recorded as events on cr, the RolloutManager that no longer exists, unless it is nil.
+func (r *RolloutManagerReconciler) removeClusterScopedResourcesIfApplicable(ctx context.Context, cr *rolloutsmanagerv1alpha1.RolloutManager) error {
 
 	clusterRole := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
@@ -332,10 +377,8 @@ func (r *RolloutManagerReconciler) removeClusterScopedResourcesIfApplicable(ctx
 	} else {
 		// ClusterRole does exist, so delete it.
 		log.Info("deleting Rollouts ClusterRole for RolloutManager that no longer exists")
-		if err := r.Client.Delete(ctx, clusterRole); err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
+		if err := r.deleteResource(ctx, cr, clusterRole); err != nil {
+			return err
 		}
 	}
 
@@ -361,10 +404,8 @@ func (r *RolloutManagerReconciler) removeClusterScopedResourcesIfApplicable(ctx
 		} else {
 			// ClusterRole '*aggregate*' does exist, so delete it.
 			log.Info("deleting ClusterRole", "name", roleName)
-			if err := r.Client.Delete(ctx, clusterRole); err != nil {
-				if !apierrors.IsNotFound(err) {
-					return err
-				}
+			if err := r.deleteResource(ctx, cr, clusterRole); err != nil {
+				return err
 			}
 		}
 	}
@@ -383,14 +424,12 @@ func (r *RolloutManagerReconciler) removeClusterScopedResourcesIfApplicable(ctx
 	} else {
 		// ClusterRoleBinding does exist, so delete it.
 		log.Info("deleting Rollouts ClusterRoleBinding for RolloutManager that no longer exists")
-		if err := r.Client.Delete(ctx, clusterRoleBinding); err != nil {
-			if !apierrors.IsNotFound(err) {
-				return err
-			}
+		if err := r.deleteResource(ctx, cr, clusterRoleBinding); err != nil {
+			return err
 		}
 	}
 
-	return nil
+	return r.removeRolloutsDashboardClusterScopedResources(ctx, cr)
 }
 
 // Reconciles aggregate-to-admin ClusterRole.
@@ -417,7 +456,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToAdminClusterRole(
 
 		log.Info(fmt.Sprintf("Creating aggregated ClusterRole %s", liveClusterRole.Name))
 		expectedClusterRole.Rules = expectedPolicyRules
-		return r.Client.Create(ctx, expectedClusterRole)
+		return r.createResource(ctx, &cr, expectedClusterRole)
 	}
 
 	updateNeeded := false
@@ -429,7 +468,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToAdminClusterRole(
 	}
 
 	normalizedLiveClusterRole := liveClusterRole.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, cr)
+	removeUnexpectedLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, expectedClusterRole.ObjectMeta)
 	if !reflect.DeepEqual(normalizedLiveClusterRole.Labels, expectedClusterRole.Labels) || !reflect.DeepEqual(normalizedLiveClusterRole.Annotations, expectedClusterRole.Annotations) {
 		updateNeeded = true
 		log.Info(fmt.Sprintf("Labels/Annotations of aggregated ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
@@ -440,7 +479,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToAdminClusterRole(
 
 	if updateNeeded {
 		// Update if the aggregated ClusterRole already exists and needs to be modified
-		return r.Client.Update(ctx, liveClusterRole)
+		return r.updateResource(ctx, &cr, liveClusterRole)
 	}
 	return nil
 }
@@ -469,7 +508,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToEditClusterRole(c
 
 		log.Info(fmt.Sprintf("Creating aggregated ClusterRole %s", expectedClusterRole.Name))
 		expectedClusterRole.Rules = expectedPolicyRules
-		return r.Client.Create(ctx, expectedClusterRole)
+		return r.createResource(ctx, &cr, expectedClusterRole)
 	}
 
 	updateNeeded := false
@@ -481,7 +520,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToEditClusterRole(c
 	}
 
 	normalizedLiveClusterRole := liveClusterRole.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, cr)
+	removeUnexpectedLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, expectedClusterRole.ObjectMeta)
 	if !reflect.DeepEqual(normalizedLiveClusterRole.Labels, expectedClusterRole.Labels) || !reflect.DeepEqual(normalizedLiveClusterRole.Annotations, expectedClusterRole.Annotations) {
 		updateNeeded = true
 		log.Info(fmt.Sprintf("Labels/Annotations of aggregated ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
@@ -492,7 +531,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToEditClusterRole(c
 
 	if updateNeeded {
 		// Update if the aggregated ClusterRole already exists and needs to be modified
-		return r.Client.Update(ctx, liveClusterRole)
+		return r.updateResource(ctx, &cr, liveClusterRole)
 	}
 	return nil
 }
@@ -521,7 +560,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToViewClusterRole(c
 
 		log.Info(fmt.Sprintf("Creating aggregated ClusterRole %s", expectedClusterRole.Name))
 		expectedClusterRole.Rules = expectedPolicyRules
-		return r.Client.Create(ctx, expectedClusterRole)
+		return r.createResource(ctx, &cr, expectedClusterRole)
 	}
 
 	updateNeeded := false
@@ -533,7 +572,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToViewClusterRole(c
 	}
 
 	normalizedLiveClusterRole := liveClusterRole.DeepCopy()
-	removeUserLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, cr)
+	removeUnexpectedLabelsAndAnnotations(&normalizedLiveClusterRole.ObjectMeta, expectedClusterRole.ObjectMeta)
 	if !reflect.DeepEqual(normalizedLiveClusterRole.Labels, expectedClusterRole.Labels) || !reflect.DeepEqual(normalizedLiveClusterRole.Annotations, expectedClusterRole.Annotations) {
 		updateNeeded = true
 		log.Info(fmt.Sprintf("Labels/Annotations of aggregated ClusterRole %s do not match the expected state, hence updating it", liveClusterRole.Name))
@@ -544,7 +583,7 @@ func (r *RolloutManagerReconciler) reconcileRolloutsAggregateToViewClusterRole(c
 
 	if updateNeeded {
 		// Update if the aggregated ClusterRole already exists and needs to be modified
-		return r.Client.Update(ctx, liveClusterRole)
+		return r.updateResource(ctx, &cr, liveClusterRole)
 	}
 
 	return nil
@@ -576,6 +615,10 @@ func (r *RolloutManagerReconciler) reconcileRolloutsMetricsServiceAndMonitor(ctx
 	existingServiceMonitor := &monitoringv1.ServiceMonitor{}
 	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, existingServiceMonitor); err != nil {
 		if apierrors.IsNotFound(err) {
+			if cr.Spec.SkipServiceMonitorDeployment {
+				// ServiceMonitor does not exist, but SkipServiceMonitorDeployment is set to true, hence skipping the creation
+				return nil
+			}
 			if err := r.createServiceMonitorIfAbsent(ctx, cr.Namespace, cr, DefaultArgoRolloutsResourceName, reconciledSvc.Name); err != nil {
 				return err
 			}
@@ -587,14 +630,32 @@ func (r *RolloutManagerReconciler) reconcileRolloutsMetricsServiceAndMonitor(ctx
 		}
 
 	} else {
+		// If SkipServiceMonitorDeployment is true, and the ServiceMonitor exists (and is owned by us), delete it
+		if cr.Spec.SkipServiceMonitorDeployment {
+			controller := metav1.GetControllerOf(existingServiceMonitor)
+			if controller != nil && controller.Name == cr.Name {
+				log.Info(fmt.Sprintf("SkipServiceMonitorDeployment has been set to true, deleting ServiceMonitor %s", existingServiceMonitor.Name))
+				if err := r.deleteResource(ctx, &cr, existingServiceMonitor); err != nil {
+					return err
+				}
+			}
+			// Otherwise, the ServiceMonitor exists, but the controller didn't create it, so just return (don't touch it)
+			return nil
+		}
+
 		log.Info("A ServiceMonitor instance already exists",
 			"Namespace", existingServiceMonitor.Namespace, "Name", existingServiceMonitor.Name)
 
-		// Check if existing ServiceMonitor matches expected content
-		if !serviceMonitorMatches(existingServiceMonitor, reconciledSvc.Name) {
-			log.Info("Updating existing ServiceMonitor instance",
-				"Namespace", existingServiceMonitor.Namespace, "Name", existingServiceMonitor.Name)
+		overridesPatch, err := applyResourceOverrides(cr, "ServiceMonitor", generateDesiredServiceMonitor(cr.Namespace, DefaultArgoRolloutsResourceName, reconciledSvc.Name))
+		if err != nil {
+			return err
+		}
+
+		originalServiceMonitor := existingServiceMonitor.DeepCopy()
 
+		// Check if existing ServiceMonitor matches expected content
+		updateNeeded := !serviceMonitorMatches(existingServiceMonitor, reconciledSvc.Name)
+		if updateNeeded {
 			// Update ServiceMonitor with expected content
 			existingServiceMonitor.Spec.Selector.MatchLabels = map[string]string{
 				"app.kubernetes.io/name": reconciledSvc.Name,
@@ -604,8 +665,19 @@ func (r *RolloutManagerReconciler) reconcileRolloutsMetricsServiceAndMonitor(ctx
 					Port: "metrics",
 				},
 			}
+		}
 
-			if err := r.Client.Update(ctx, existingServiceMonitor); err != nil {
+		if overridesPatch != nil {
+			if updateNeeded, err = applyResourceOverridesToLiveObject(originalServiceMonitor, existingServiceMonitor, overridesPatch); err != nil {
+				return err
+			}
+		}
+
+		if updateNeeded {
+			log.Info("Updating existing ServiceMonitor instance",
+				"Namespace", existingServiceMonitor.Namespace, "Name", existingServiceMonitor.Name)
+
+			if err := r.updateResource(ctx, &cr, existingServiceMonitor); err != nil {
 				log.Error(err, "Error updating existing ServiceMonitor instance",
 					"Namespace", existingServiceMonitor.Namespace, "Name", existingServiceMonitor.Name)
 				return err
@@ -643,25 +715,32 @@ func (r *RolloutManagerReconciler) reconcileRolloutsMetricsService(ctx context.C
 		DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName,
 	}
 
+	overriddenSvc := expectedSvc.DeepCopy()
+	overridesPatch, err := applyResourceOverrides(cr, "Service", overriddenSvc)
+	if err != nil {
+		return nil, err
+	}
+
 	liveService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: expectedSvc.Name, Namespace: expectedSvc.Namespace}}
 	if err := fetchObject(ctx, r.Client, cr.Namespace, liveService.Name, liveService); err != nil {
 		if !apierrors.IsNotFound(err) {
 			return nil, fmt.Errorf("failed to get the Service %s: %w", expectedSvc.Name, err)
 		}
 
-		if err := controllerutil.SetControllerReference(&cr, expectedSvc, r.Scheme); err != nil {
+		if err := controllerutil.SetControllerReference(&cr, overriddenSvc, r.Scheme); err != nil {
 			return nil, err
 		}
 
-		log.Info(fmt.Sprintf("Creating Service %s", expectedSvc.Name))
-		if err := r.Client.Create(ctx, expectedSvc); err != nil {
-			log.Error(err, "Error creating Service", "Name", expectedSvc.Name)
+		log.Info(fmt.Sprintf("Creating Service %s", overriddenSvc.Name))
+		if err := r.createResource(ctx, &cr, overriddenSvc); err != nil {
+			log.Error(err, "Error creating Service", "Name", overriddenSvc.Name)
 			return nil, err
 		}
-		liveService = expectedSvc
+		liveService = overriddenSvc
 
 	}
 
+	originalService := liveService.DeepCopy()
 	updateNeeded := false
 
 	if !reflect.DeepEqual(liveService.Spec.Ports, expectedSvc.Spec.Ports) {
@@ -680,9 +759,15 @@ func (r *RolloutManagerReconciler) reconcileRolloutsMetricsService(ctx context.C
 		liveService.Annotations = combineStringMaps(liveService.Annotations, expectedSvc.Annotations)
 	}
 
+	if overridesPatch != nil {
+		if updateNeeded, err = applyResourceOverridesToLiveObject(originalService, liveService, overridesPatch); err != nil {
+			return nil, err
+		}
+	}
+
 	if updateNeeded {
 		// Update if the Service already exists and needs to be modified
-		if err := r.Client.Update(ctx, liveService); err != nil {
+		if err := r.updateResource(ctx, &cr, liveService); err != nil {
 			log.Error(err, "Error updating Ports of metrics Service", "Name", liveService.Name)
 			return liveService, err
 		}
@@ -692,6 +777,10 @@ func (r *RolloutManagerReconciler) reconcileRolloutsMetricsService(ctx context.C
 
 }
 
+// NotificationSecretSourceKeysAnnotation is set on the notification Secret to the comma-separated list of the keys merged from .spec.notificationSecretSources, so that the keys
+// which are no longer provided by a source are removed, while the keys added to the Secret by users are left untouched.
+const NotificationSecretSourceKeysAnnotation = "argo-rollouts-manager.argoproj.io/notification-secret-source-keys"
+
 // Reconciles Secrets for Rollouts controller
 func (r *RolloutManagerReconciler) reconcileRolloutsSecrets(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
 
@@ -705,6 +794,15 @@ func (r *RolloutManagerReconciler) reconcileRolloutsSecrets(ctx context.Context,
 
 	setRolloutsLabelsAndAnnotationsToObject(&expectedSecret.ObjectMeta, cr)
 
+	// Keys of the source Secrets that are merged into the notification Secret
+	sourceData, err := r.getNotificationSecretSourceData(ctx, cr)
+	if err != nil {
+		return err
+	}
+	if len(sourceData) > 0 {
+		expectedSecret.Data = sourceData
+	}
+
 	// If the Secret doesn't exist (or an unrelated error occurred)....
 	liveSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: expectedSecret.Name, Namespace: expectedSecret.Namespace}}
 	if err := fetchObject(ctx, r.Client, cr.Namespace, liveSecret.Name, liveSecret); err != nil {
@@ -721,9 +819,12 @@ func (r *RolloutManagerReconciler) reconcileRolloutsSecrets(ctx context.Context,
 		if err := controllerutil.SetControllerReference(&cr, expectedSecret, r.Scheme); err != nil {
 			return err
 		}
+		if len(sourceData) > 0 {
+			expectedSecret.Annotations[NotificationSecretSourceKeysAnnotation] = getNotificationSecretSourceKeys(sourceData)
+		}
 
 		log.Info(fmt.Sprintf("Creating Secret %s", expectedSecret.Name))
-		return r.Client.Create(ctx, expectedSecret)
+		return r.createResource(ctx, &cr, expectedSecret)
 
 	}
 
@@ -756,18 +857,197 @@ func (r *RolloutManagerReconciler) reconcileRolloutsSecrets(ctx context.Context,
 		liveSecret.Annotations = combineStringMaps(liveSecret.Annotations, expectedSecret.Annotations)
 	}
 
+	// Merge the keys of the source Secrets, leaving the other keys of the notification Secret untouched
+	for key, value := range sourceData {
+		if liveValue, exists := liveSecret.Data[key]; !exists || !bytes.Equal(liveValue, value) {
+			if !updateNeeded {
+				log.Info(fmt.Sprintf("Data of Secret %s does not match the notification Secret sources, hence updating it", liveSecret.Name))
+			}
+			updateNeeded = true
+
+			if liveSecret.Data == nil {
+				liveSecret.Data = map[string][]byte{}
+			}
+			liveSecret.Data[key] = value
+		}
+	}
+
+	// Remove the keys which were merged from a source Secret, but are no longer provided by any source
+	if previousSourceKeys := liveSecret.Annotations[NotificationSecretSourceKeysAnnotation]; previousSourceKeys != "" {
+		for _, key := range splitList(previousSourceKeys) {
+			if _, exists := sourceData[key]; exists {
+				continue
+			}
+			if _, exists := liveSecret.Data[key]; exists {
+				log.Info(fmt.Sprintf("Key %s of Secret %s is no longer provided by the notification Secret sources, hence removing it", key, liveSecret.Name))
+				delete(liveSecret.Data, key)
+				updateNeeded = true
+			}
+		}
+	}
+
+	if sourceKeys := getNotificationSecretSourceKeys(sourceData); liveSecret.Annotations[NotificationSecretSourceKeysAnnotation] != sourceKeys {
+		updateNeeded = true
+		if sourceKeys == "" {
+			delete(liveSecret.Annotations, NotificationSecretSourceKeysAnnotation)
+		} else {
+			if liveSecret.Annotations == nil {
+				liveSecret.Annotations = map[string]string{}
+			}
+			liveSecret.Annotations[NotificationSecretSourceKeysAnnotation] = sourceKeys
+		}
+	}
+
 	if updateNeeded {
 		// Update if the Secret already exists and needs to be modified
-		return r.Client.Update(ctx, liveSecret)
+		return r.updateResource(ctx, &cr, liveSecret)
 	}
 
 	// secret found, do nothing
 	return nil
 }
 
+// getNotificationSecretSourceKeys returns the sorted, comma-separated list of the keys merged from the notification Secret sources, which is the value of NotificationSecretSourceKeysAnnotation.
+func getNotificationSecretSourceKeys(sourceData map[string][]byte) string {
+	keys := make([]string, 0, len(sourceData))
+	for key := range sourceData {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// getNotificationSecretSourceData returns the keys of the Secrets referenced by .spec.notificationSecretSources, under their target name.
+// The sources are verified by validateRolloutsNotificationSources beforehand: they exist, and no two of them provide the same key.
+func (r *RolloutManagerReconciler) getNotificationSecretSourceData(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (map[string][]byte, error) {
+
+	data := map[string][]byte{}
+
+	for _, source := range cr.Spec.NotificationSecretSources {
+
+		sourceSecret := &corev1.Secret{}
+		if err := fetchObject(ctx, r.Client, cr.Namespace, source.Name, sourceSecret); err != nil {
+			return nil, fmt.Errorf("failed to get the notification Secret source %s: %w", source.Name, err)
+		}
+
+		if len(source.Keys) == 0 {
+			for key, value := range sourceSecret.Data {
+				data[key] = value
+			}
+			continue
+		}
+
+		for _, mapping := range source.Keys {
+			value, exists := sourceSecret.Data[mapping.Key]
+			if !exists {
+				return nil, fmt.Errorf("key %s does not exist in the notification Secret source %s", mapping.Key, source.Name)
+			}
+
+			targetKey := mapping.TargetKey
+			if targetKey == "" {
+				targetKey = mapping.Key
+			}
+			data[targetKey] = value
+		}
+	}
+
+	return data, nil
+}
+
+// Reconciles Rollouts PodDisruptionBudget.
+func (r *RolloutManagerReconciler) reconcileRolloutsPodDisruptionBudget(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) error {
+
+	expectedPDB := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DefaultArgoRolloutsResourceName,
+			Namespace: cr.Namespace,
+		},
+	}
+	setRolloutsLabelsAndAnnotationsToObject(&expectedPDB.ObjectMeta, cr)
+
+	if cr.Spec.PodDisruptionBudget != nil {
+		expectedPDB.Spec = policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   cr.Spec.PodDisruptionBudget.MinAvailable,
+			MaxUnavailable: cr.Spec.PodDisruptionBudget.MaxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName,
+				},
+			},
+		}
+
+		if expectedPDB.Spec.MinAvailable == nil && expectedPDB.Spec.MaxUnavailable == nil {
+			maxUnavailable := intstr.FromInt(1)
+			expectedPDB.Spec.MaxUnavailable = &maxUnavailable
+		}
+	}
+
+	livePDB := &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: expectedPDB.Name, Namespace: expectedPDB.Namespace}}
+	if err := fetchObject(ctx, r.Client, cr.Namespace, livePDB.Name, livePDB); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to get the PodDisruptionBudget %s: %w", livePDB.Name, err)
+		}
+
+		if cr.Spec.PodDisruptionBudget == nil {
+			// PodDisruptionBudget does not exist, and none is requested
+			return nil
+		}
+
+		if err := controllerutil.SetControllerReference(&cr, expectedPDB, r.Scheme); err != nil {
+			return err
+		}
+
+		log.Info(fmt.Sprintf("Creating PodDisruptionBudget %s", expectedPDB.Name))
+		return r.createResource(ctx, &cr, expectedPDB)
+	}
+
+	// If a PodDisruptionBudget is no longer requested, and the existing one is owned by us, delete it
+	if cr.Spec.PodDisruptionBudget == nil {
+
+		controller := metav1.GetControllerOf(livePDB)
+		if controller != nil && controller.Name == cr.Name {
+			log.Info(fmt.Sprintf("PodDisruptionBudget is no longer specified in RolloutManager, deleting PodDisruptionBudget %s", livePDB.Name))
+			return r.Client.Delete(ctx, livePDB)
+		}
+
+		return nil
+	}
+
+	updateNeeded := false
+
+	normalizedLivePDB := livePDB.DeepCopy()
+	removeUserLabelsAndAnnotations(&normalizedLivePDB.ObjectMeta, cr)
+
+	if !reflect.DeepEqual(normalizedLivePDB.Labels, expectedPDB.Labels) || !reflect.DeepEqual(normalizedLivePDB.Annotations, expectedPDB.Annotations) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Labels/Annotations of PodDisruptionBudget %s do not match the expected state, hence updating it", livePDB.Name))
+
+		livePDB.Labels = combineStringMaps(livePDB.Labels, expectedPDB.Labels)
+		livePDB.Annotations = combineStringMaps(livePDB.Annotations, expectedPDB.Annotations)
+	}
+
+	if !reflect.DeepEqual(livePDB.Spec.MinAvailable, expectedPDB.Spec.MinAvailable) ||
+		!reflect.DeepEqual(livePDB.Spec.MaxUnavailable, expectedPDB.Spec.MaxUnavailable) ||
+		!reflect.DeepEqual(livePDB.Spec.Selector, expectedPDB.Spec.Selector) {
+		updateNeeded = true
+		log.Info(fmt.Sprintf("Spec of PodDisruptionBudget %s does not match the expected state, hence updating it", livePDB.Name))
+
+		livePDB.Spec.MinAvailable = expectedPDB.Spec.MinAvailable
+		livePDB.Spec.MaxUnavailable = expectedPDB.Spec.MaxUnavailable
+		livePDB.Spec.Selector = expectedPDB.Spec.Selector
+	}
+
+	if updateNeeded {
+		return r.updateResource(ctx, &cr, livePDB)
+	}
+
+	return nil
+}
+
 func setRolloutsAggregatedClusterRoleLabels(obj *metav1.ObjectMeta, name string, aggregationType string) {
 
 	obj.Labels = map[string]string{}
+	obj.Annotations = map[string]string{}
 	obj.Labels["app.kubernetes.io/component"] = "aggregate-cluster-role"
 	obj.Labels["app.kubernetes.io/name"] = name
 	obj.Labels["app.kubernetes.io/part-of"] = DefaultArgoRolloutsResourceName
@@ -1206,8 +1486,9 @@ func GetAggregateToViewPolicyRules() []rbacv1.PolicyRule {
 	}
 }
 
-func (r *RolloutManagerReconciler) createServiceMonitorIfAbsent(ctx context.Context, namespace string, rolloutManager rolloutsmanagerv1alpha1.RolloutManager, name, serviceMonitorLabel string) error {
-	serviceMonitor := &monitoringv1.ServiceMonitor{
+// generateDesiredServiceMonitor returns the ServiceMonitor which selects the metrics Service of the Rollouts controller, before .spec.overrides are applied.
+func generateDesiredServiceMonitor(namespace string, name string, serviceMonitorLabel string) *monitoringv1.ServiceMonitor {
+	return &monitoringv1.ServiceMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
@@ -1225,6 +1506,14 @@ func (r *RolloutManagerReconciler) createServiceMonitorIfAbsent(ctx context.Cont
 			},
 		},
 	}
+}
+
+func (r *RolloutManagerReconciler) createServiceMonitorIfAbsent(ctx context.Context, namespace string, rolloutManager rolloutsmanagerv1alpha1.RolloutManager, name, serviceMonitorLabel string) error {
+	serviceMonitor := generateDesiredServiceMonitor(namespace, name, serviceMonitorLabel)
+	if _, err := applyResourceOverrides(rolloutManager, "ServiceMonitor", serviceMonitor); err != nil {
+		return err
+	}
+
 	log.Info("Creating a new ServiceMonitor instance",
 		"Namespace", serviceMonitor.Namespace, "Name", serviceMonitor.Name)
 
@@ -1235,7 +1524,7 @@ func (r *RolloutManagerReconciler) createServiceMonitorIfAbsent(ctx context.Cont
 		return err
 	}
 
-	err := r.Client.Create(ctx, serviceMonitor)
+	err := r.createResource(ctx, &rolloutManager, serviceMonitor)
 	if err != nil {
 		log.Error(err, "Error creating a new ServiceMonitor instance",
 			"Namespace", serviceMonitor.Namespace, "Name", serviceMonitor.Name)
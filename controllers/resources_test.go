@@ -10,10 +10,13 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -242,7 +245,7 @@ var _ = Describe("Resource creation and cleanup tests", func() {
 			Expect(r.Client.Create(ctx, unrelatedRoleBinding)).To(Succeed())
 
 			By("calling removeClusterScopedResourcesIfApplicable, which should delete the cluster scoped resources")
-			Expect(r.removeClusterScopedResourcesIfApplicable(ctx)).To(Succeed())
+			Expect(r.removeClusterScopedResourcesIfApplicable(ctx, nil)).To(Succeed())
 
 			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(clusterRole), clusterRole)).ToNot(Succeed(),
 				"ClusterRole should have been deleted")
@@ -259,7 +262,7 @@ var _ = Describe("Resource creation and cleanup tests", func() {
 			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(clusterRoleView), clusterRoleView)).ToNot(Succeed(),
 				"ClusterRole should have been deleted")
 
-			Expect(r.removeClusterScopedResourcesIfApplicable(ctx)).To(Succeed(), "calling the function again should not return an error")
+			Expect(r.removeClusterScopedResourcesIfApplicable(ctx, nil)).To(Succeed(), "calling the function again should not return an error")
 
 		})
 	})
@@ -572,7 +575,7 @@ var _ = Describe("Resource creation and cleanup tests", func() {
 			Expect(r.Client.Create(ctx, unrelatedRoleBinding)).To(Succeed())
 
 			By("calling removeClusterScopedResourcesIfApplicable, which should delete the cluster scoped resources")
-			Expect(r.removeClusterScopedResourcesIfApplicable(ctx)).To(Succeed())
+			Expect(r.removeClusterScopedResourcesIfApplicable(ctx, nil)).To(Succeed())
 
 			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(clusterRole), clusterRole)).ToNot(Succeed(),
 				"ClusterRole should have been deleted")
@@ -582,7 +585,7 @@ var _ = Describe("Resource creation and cleanup tests", func() {
 				"Unrelated ClusterRole should not have been deleted")
 			Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(unrelatedRoleBinding), unrelatedRoleBinding)).To(Succeed(), "Unrelated ClusterRoleBinding should not have been deleted")
 
-			Expect(r.removeClusterScopedResourcesIfApplicable(ctx)).To(Succeed(), "calling the function again should not return an error")
+			Expect(r.removeClusterScopedResourcesIfApplicable(ctx, nil)).To(Succeed(), "calling the function again should not return an error")
 
 		})
 
@@ -1027,6 +1030,40 @@ var _ = Describe("Resource creation and cleanup tests", func() {
 
 		})
 
+		It("Verify that the ServiceMonitor is not created, and is deleted if it is owned by the RolloutManager, when SkipServiceMonitorDeployment is set", func() {
+			smCRD := &crdv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: serviceMonitorsCRDName,
+				},
+			}
+			Expect(r.Client.Create(ctx, smCRD)).To(Succeed())
+
+			By("reconciling the RolloutManager without SkipServiceMonitorDeployment, so that the ServiceMonitor is created")
+			Expect(r.reconcileRolloutsMetricsServiceAndMonitor(ctx, *a)).To(Succeed())
+			sm := &monitoringv1.ServiceMonitor{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, sm)).To(Succeed())
+
+			By("setting SkipServiceMonitorDeployment, and verifying that the ServiceMonitor is deleted")
+			a.Spec.SkipServiceMonitorDeployment = true
+			Expect(r.reconcileRolloutsMetricsServiceAndMonitor(ctx, *a)).To(Succeed())
+			err := fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, sm)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "ServiceMonitor should have been deleted")
+
+			service := &corev1.Service{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsMetricsServiceName, service)).To(Succeed(), "the metrics Service should still exist")
+
+			By("creating a ServiceMonitor which is not owned by the RolloutManager, and verifying that it is left untouched")
+			unownedServiceMonitor := &monitoringv1.ServiceMonitor{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      DefaultArgoRolloutsResourceName,
+					Namespace: a.Namespace,
+				},
+			}
+			Expect(r.Client.Create(ctx, unownedServiceMonitor)).To(Succeed())
+			Expect(r.reconcileRolloutsMetricsServiceAndMonitor(ctx, *a)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, sm)).To(Succeed())
+		})
+
 		It("Verify ServiceMonitor is not created if the CRD does not exist.", func() {
 			res, err := r.Reconcile(ctx, req)
 			Expect(err).ToNot(HaveOccurred())
@@ -1137,6 +1174,211 @@ var _ = Describe("Resource creation and cleanup tests", func() {
 			Expect(secret.OwnerReferences).To(ContainElement(testRef))
 			Expect(len(secret.OwnerReferences)).To(Equal(1))
 		})
+
+		It("Verify that the keys of the notification Secret sources are merged into the notification secret, and kept in sync", func() {
+
+			teamSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-secret", Namespace: a.Namespace},
+				Data: map[string][]byte{
+					"token":    []byte("slack-token"),
+					"password": []byte("not-copied"),
+				},
+			}
+			Expect(r.Client.Create(ctx, teamSecret)).To(Succeed())
+
+			emailSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "email-secret", Namespace: a.Namespace},
+				Data: map[string][]byte{
+					"email-username": []byte("user"),
+					"email-password": []byte("password"),
+				},
+			}
+			Expect(r.Client.Create(ctx, emailSecret)).To(Succeed())
+
+			By("Creating RolloutManager with notification Secret sources")
+			a.Spec.NotificationSecretSources = []v1alpha1.NotificationSecretSource{
+				{Name: teamSecret.Name, Keys: []v1alpha1.NotificationSecretKeyMapping{{Key: "token", TargetKey: "slack-token"}}},
+				{Name: emailSecret.Name},
+			}
+			Expect(r.Client.Update(ctx, &a)).To(Succeed())
+			Expect(r.reconcileRolloutsSecrets(ctx, a)).To(Succeed())
+
+			secret := &corev1.Secret{}
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationSecretName, secret)).To(Succeed())
+			expectedData := map[string][]byte{
+				"slack-token":    []byte("slack-token"),
+				"email-username": []byte("user"),
+				"email-password": []byte("password"),
+			}
+			Expect(secret.Data).To(Equal(expectedData))
+
+			By("Adding a key to the notification secret, and modifying a key that comes from a source")
+			secret.Data["my-key"] = []byte("my-value")
+			secret.Data["slack-token"] = []byte("modified")
+			Expect(r.Client.Update(ctx, secret)).To(Succeed())
+
+			By("Updating a source Secret")
+			emailSecret.Data["email-password"] = []byte("new-password")
+			Expect(r.Client.Update(ctx, emailSecret)).To(Succeed())
+
+			Expect(r.reconcileRolloutsSecrets(ctx, a)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationSecretName, secret)).To(Succeed())
+			expectedData["email-password"] = []byte("new-password")
+			expectedData["my-key"] = []byte("my-value")
+			Expect(secret.Data).To(Equal(expectedData))
+
+			By("Verifying that the RolloutManager is queued when a source Secret changes")
+			Expect(r.enqueueRolloutManagersReferencingSecret(ctx, emailSecret)).To(HaveLen(1))
+			Expect(r.enqueueRolloutManagersReferencingSecret(ctx, secret)).To(BeEmpty())
+
+			By("Removing a key mapping, and verifying that its key is removed from the notification secret")
+			a.Spec.NotificationSecretSources = []v1alpha1.NotificationSecretSource{
+				{Name: teamSecret.Name, Keys: []v1alpha1.NotificationSecretKeyMapping{{Key: "password"}}},
+				{Name: emailSecret.Name},
+			}
+			Expect(r.Client.Update(ctx, &a)).To(Succeed())
+			Expect(r.reconcileRolloutsSecrets(ctx, a)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationSecretName, secret)).To(Succeed())
+			delete(expectedData, "slack-token")
+			expectedData["password"] = []byte("not-copied")
+			Expect(secret.Data).To(Equal(expectedData))
+			Expect(secret.Annotations[NotificationSecretSourceKeysAnnotation]).To(Equal("email-password,email-username,password"))
+
+			By("Removing every source, and verifying that only the keys added by users are left")
+			a.Spec.NotificationSecretSources = nil
+			Expect(r.Client.Update(ctx, &a)).To(Succeed())
+			Expect(r.reconcileRolloutsSecrets(ctx, a)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultRolloutsNotificationSecretName, secret)).To(Succeed())
+			Expect(secret.Data).To(Equal(map[string][]byte{"my-key": []byte("my-value")}))
+			Expect(secret.Annotations).ToNot(HaveKey(NotificationSecretSourceKeysAnnotation))
+		})
+
+		DescribeTable("Verify that the RolloutManager is put in the Pending phase when a notification Secret source cannot be read", func(source v1alpha1.NotificationSecretSource, expectedErr string) {
+
+			Expect(r.Client.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-secret", Namespace: a.Namespace},
+				Data:       map[string][]byte{"token": []byte("slack-token")},
+			})).To(Succeed())
+
+			a.Spec.NotificationSecretSources = []v1alpha1.NotificationSecretSource{source}
+			Expect(r.Client.Update(ctx, &a)).To(Succeed())
+
+			rr, err := validateRolloutsNotificationSources(ctx, r.Client, a)
+			Expect(missingNotificationSource(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(expectedErr))
+			Expect(*rr.phase).To(Equal(v1alpha1.PhasePending))
+
+			Expect(r.reconcileRolloutsSecrets(ctx, a)).ToNot(Succeed())
+		},
+			Entry("Secret does not exist", v1alpha1.NotificationSecretSource{Name: "does-not-exist"},
+				"Secret does-not-exist, referenced by .spec.notificationSecretSources[0], does not exist"),
+			Entry("key does not exist", v1alpha1.NotificationSecretSource{Name: "team-secret", Keys: []v1alpha1.NotificationSecretKeyMapping{{Key: "does-not-exist"}}},
+				"key does-not-exist of Secret team-secret, referenced by .spec.notificationSecretSources[0], does not exist"),
+		)
+
+		It("Verify that the RolloutManager is put in the Failure phase when two notification Secret sources provide the same key", func() {
+
+			Expect(r.Client.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-secret", Namespace: a.Namespace},
+				Data:       map[string][]byte{"token": []byte("slack-token")},
+			})).To(Succeed())
+			Expect(r.Client.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "other-team-secret", Namespace: a.Namespace},
+				Data:       map[string][]byte{"slack-token": []byte("other-slack-token")},
+			})).To(Succeed())
+
+			a.Spec.NotificationSecretSources = []v1alpha1.NotificationSecretSource{
+				{Name: "team-secret", Keys: []v1alpha1.NotificationSecretKeyMapping{{Key: "token", TargetKey: "slack-token"}}},
+				{Name: "other-team-secret"},
+			}
+
+			rr, err := validateRolloutsNotificationSources(ctx, r.Client, a)
+			Expect(invalidNotificationConfiguration(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("key slack-token of the notification Secret is provided by both .spec.notificationSecretSources[0] and .spec.notificationSecretSources[1]"))
+			Expect(*rr.phase).To(Equal(v1alpha1.PhaseFailure))
+
+			By("mapping the key to another target key")
+			a.Spec.NotificationSecretSources[0].Keys[0].TargetKey = "team-slack-token"
+
+			rr, err = validateRolloutsNotificationSources(ctx, r.Client, a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rr).To(BeNil())
+		})
+	})
+
+	Context("Rollouts PodDisruptionBudget reconciliation tests", func() {
+		var (
+			ctx context.Context
+			a   v1alpha1.RolloutManager
+			r   *RolloutManagerReconciler
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			a = *makeTestRolloutManager()
+			r = makeTestReconciler(&a)
+			err := createNamespace(r, a.Namespace)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Verify that the PodDisruptionBudget is created, updated and deleted based on the RolloutManager spec", func() {
+
+			pdb := &policyv1.PodDisruptionBudget{}
+
+			By("calling reconcileRolloutsPodDisruptionBudget without a PodDisruptionBudget in the spec")
+			Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).ToNot(Succeed(), "PodDisruptionBudget should not exist")
+
+			By("setting an empty PodDisruptionBudget in the spec")
+			a.Spec.PodDisruptionBudget = &v1alpha1.RolloutsPodDisruptionBudgetSpec{}
+			Expect(r.Client.Update(ctx, &a)).To(Succeed())
+			Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).To(Succeed())
+			Expect(pdb.Spec.MinAvailable).To(BeNil())
+			Expect(pdb.Spec.MaxUnavailable).To(Equal(intOrStringPtr(intstr.FromInt(1))), "maxUnavailable should default to 1")
+			Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName}))
+
+			By("setting minAvailable in the spec")
+			a.Spec.PodDisruptionBudget = &v1alpha1.RolloutsPodDisruptionBudgetSpec{MinAvailable: intOrStringPtr(intstr.FromString("50%"))}
+			Expect(r.Client.Update(ctx, &a)).To(Succeed())
+			Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).To(Succeed())
+			Expect(pdb.Spec.MinAvailable).To(Equal(intOrStringPtr(intstr.FromString("50%"))))
+			Expect(pdb.Spec.MaxUnavailable).To(BeNil())
+
+			By("modifying the PodDisruptionBudget outside of the operator")
+			pdb.Spec.MinAvailable = intOrStringPtr(intstr.FromInt(0))
+			Expect(r.Client.Update(ctx, pdb)).To(Succeed())
+			Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).To(Succeed())
+			Expect(pdb.Spec.MinAvailable).To(Equal(intOrStringPtr(intstr.FromString("50%"))), "the change should be reverted")
+
+			By("removing the PodDisruptionBudget from the spec")
+			a.Spec.PodDisruptionBudget = nil
+			Expect(r.Client.Update(ctx, &a)).To(Succeed())
+			Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).ToNot(Succeed(), "PodDisruptionBudget should be deleted")
+		})
+
+		It("Verify that RolloutManager does not delete a PodDisruptionBudget it doesn't own", func() {
+
+			pdb := &policyv1.PodDisruptionBudget{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      DefaultArgoRolloutsResourceName,
+					Namespace: a.Namespace,
+				},
+			}
+			Expect(r.Client.Create(ctx, pdb)).To(Succeed())
+
+			Expect(r.reconcileRolloutsPodDisruptionBudget(ctx, a)).To(Succeed())
+			Expect(fetchObject(ctx, r.Client, a.Namespace, DefaultArgoRolloutsResourceName, pdb)).To(Succeed(), "PodDisruptionBudget should still exist")
+		})
 	})
 
 })
@@ -2,17 +2,51 @@ package rollouts
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const (
+	// requeueMinDelay is the minimum delay after which a RolloutManager which is not available is reconciled again
+	requeueMinDelay = 5 * time.Second
+
+	// requeueMaxDelay is the maximum delay after which a RolloutManager which is not available is reconciled again
+	requeueMaxDelay = time.Minute
+)
+
+// DeploymentProgressDeadlineExceededReason is the reason of the Progressing condition of a Deployment which did not progress within its deadline
+const DeploymentProgressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+// rolloutsContainerFailureReasons are the reasons for which a container of a Rollouts controller pod may be waiting, which require an action from the user.
+var rolloutsContainerFailureReasons = []string{
+	"CrashLoopBackOff",
+	"ImagePullBackOff",
+	"ErrImagePull",
+	"InvalidImageName",
+	"CreateContainerConfigError",
+	"CreateContainerError",
+}
+
 // determineStatusPhase calculates and returns RolloutManager's current .status.phase and .status.rolloutcontroller, both based on Deployment status.
+// The phase is Failure, with a condition describing why, when the pods of the Argo Rollouts controller cannot start or the Deployment did not progress within its deadline.
 func (r *RolloutManagerReconciler) determineStatusPhase(ctx context.Context, cr rolloutsmanagerv1alpha1.RolloutManager) (reconcileStatusResult, error) {
 
 	status := rolloutsmanagerv1alpha1.PhaseUnknown
 
+	// failure and failureReason describe why the Argo Rollouts controller failed, if it did
+	failure, failureReason := "", ""
+
 	deploy := &appsv1.Deployment{}
 	if err := fetchObject(ctx, r.Client, cr.Namespace, DefaultArgoRolloutsResourceName, deploy); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -31,10 +65,52 @@ func (r *RolloutManagerReconciler) determineStatusPhase(ctx context.Context, cr
 				status = rolloutsmanagerv1alpha1.PhaseAvailable
 			}
 		}
+
+		// Pods which cannot load their plugins never become ready: report why, rather than remaining Pending.
+		if status != rolloutsmanagerv1alpha1.PhaseAvailable && hasPluginInitContainers(cr) && deploy.Spec.Selector != nil {
+			var err error
+			if failure, err = r.getPluginFetchFailure(ctx, *deploy); err != nil {
+				log.Error(err, "error retrieving the status of the plugin init containers")
+				return reconcileStatusResult{}, err
+			}
+			failureReason = rolloutsmanagerv1alpha1.RolloutManagerReasonPluginFetchFailed
+		}
+
+		// Likewise for pods whose containers cannot be pulled, or keep crashing.
+		if failure == "" && status != rolloutsmanagerv1alpha1.PhaseAvailable && deploy.Spec.Selector != nil {
+			var err error
+			if failure, err = r.getRolloutsPodFailure(ctx, *deploy); err != nil {
+				log.Error(err, "error retrieving the status of the Rollouts controller containers")
+				return reconcileStatusResult{}, err
+			}
+			failureReason = rolloutsmanagerv1alpha1.RolloutManagerReasonRolloutsControllerFailed
+		}
+
+		// The Deployment controller gives up on a rollout which does not progress within .spec.progressDeadlineSeconds.
+		if progressing := getDeploymentCondition(*deploy, appsv1.DeploymentProgressing); failure == "" && progressing != nil &&
+			progressing.Status == corev1.ConditionFalse && progressing.Reason == DeploymentProgressDeadlineExceededReason {
+
+			failure = fmt.Sprintf("the Deployment %s did not progress within its deadline: %s", deploy.Name, progressing.Message)
+			failureReason = rolloutsmanagerv1alpha1.RolloutManagerReasonProgressDeadlineExceeded
+		}
+
+		// Automation waiting on the RolloutManager should not wait forever for a controller which never becomes available.
+		if failure == "" && status != rolloutsmanagerv1alpha1.PhaseAvailable && readinessDeadlineExceeded(cr, time.Now()) {
+			failure = fmt.Sprintf("the Argo Rollouts controller did not become available within %s", getReadinessDeadline(cr))
+			failureReason = rolloutsmanagerv1alpha1.RolloutManagerReasonReadinessDeadlineExceeded
+		}
+
+		if failure != "" {
+			status = rolloutsmanagerv1alpha1.PhaseFailure
+		}
 	}
 
 	var res reconcileStatusResult
 
+	if failure != "" {
+		res.condition = createCondition(failure, failureReason)
+	}
+
 	if cr.Status.RolloutController != status {
 		res.rolloutController = &status
 	}
@@ -45,3 +121,144 @@ func (r *RolloutManagerReconciler) determineStatusPhase(ctx context.Context, cr
 
 	return res, nil
 }
+
+// getReadinessDeadline returns the duration the Argo Rollouts controller may take to become available, or 0 if there is no deadline.
+func getReadinessDeadline(cr rolloutsmanagerv1alpha1.RolloutManager) time.Duration {
+	if cr.Spec.ReadinessDeadlineSeconds == nil {
+		return 0
+	}
+	return time.Duration(*cr.Spec.ReadinessDeadlineSeconds) * time.Second
+}
+
+// readinessDeadlineExceeded returns true if the Argo Rollouts controller has been progressing towards availability, as reported by the Progressing
+// condition of the RolloutManager, for longer than the readiness deadline. Once exceeded, the deadline remains exceeded until the .spec of the
+// RolloutManager changes, since the Progressing condition is then false.
+func readinessDeadlineExceeded(cr rolloutsmanagerv1alpha1.RolloutManager, now time.Time) bool {
+
+	deadline := getReadinessDeadline(cr)
+	if deadline == 0 {
+		return false
+	}
+
+	if reconciled := meta.FindStatusCondition(cr.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerConditionType); reconciled != nil &&
+		reconciled.Reason == rolloutsmanagerv1alpha1.RolloutManagerReasonReadinessDeadlineExceeded {
+		return cr.Status.ObservedGeneration == cr.Generation
+	}
+
+	progressing := meta.FindStatusCondition(cr.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType)
+	if progressing == nil || progressing.Status != metav1.ConditionTrue {
+		return false
+	}
+
+	return now.Sub(progressing.LastTransitionTime.Time) > deadline
+}
+
+// getRequeueDelay returns the delay after which the RolloutManager should be reconciled again, or 0 if it does not need to be.
+// While the RolloutManager is not available, it is requeued with a delay which grows with the time since it stopped being ready,
+// bounded by requeueMinDelay and requeueMaxDelay, and never beyond the readiness deadline.
+func getRequeueDelay(cr rolloutsmanagerv1alpha1.RolloutManager, now time.Time) time.Duration {
+
+	if cr.Status.Phase != rolloutsmanagerv1alpha1.PhasePending && cr.Status.Phase != rolloutsmanagerv1alpha1.PhaseUnknown {
+		return 0
+	}
+
+	delay := requeueMinDelay
+	if ready := meta.FindStatusCondition(cr.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerReadyConditionType); ready != nil {
+		delay = now.Sub(ready.LastTransitionTime.Time)
+	}
+
+	progressing := meta.FindStatusCondition(cr.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType)
+	if deadline := getReadinessDeadline(cr); deadline != 0 && progressing != nil && progressing.Status == metav1.ConditionTrue {
+		if remaining := progressing.LastTransitionTime.Add(deadline).Sub(now); remaining < delay {
+			delay = remaining
+		}
+	}
+
+	if delay < requeueMinDelay {
+		return requeueMinDelay
+	}
+	if delay > requeueMaxDelay {
+		return requeueMaxDelay
+	}
+	return delay
+}
+
+// getDeploymentCondition returns the condition of the given type of the Deployment, or nil if it is not set.
+func getDeploymentCondition(deployment appsv1.Deployment, conditionType appsv1.DeploymentConditionType) *appsv1.DeploymentCondition {
+	for idx := range deployment.Status.Conditions {
+		if deployment.Status.Conditions[idx].Type == conditionType {
+			return &deployment.Status.Conditions[idx]
+		}
+	}
+	return nil
+}
+
+// getRolloutsPodFailure returns a message describing why a container of a Rollouts controller pod cannot run, such as an image which cannot be pulled,
+// or a container which keeps crashing, along with its last termination message. It returns "" if none failed.
+func (r *RolloutManagerReconciler) getRolloutsPodFailure(ctx context.Context, deployment appsv1.Deployment) (string, error) {
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(ctx, podList, client.InNamespace(deployment.Namespace), client.MatchingLabels(deployment.Spec.Selector.MatchLabels)); err != nil {
+		return "", fmt.Errorf("failed to list Rollouts Pods: %w", err)
+	}
+
+	for _, pod := range podList.Items {
+		for _, status := range pod.Status.ContainerStatuses {
+
+			waiting := status.State.Waiting
+			if waiting == nil || !contains(rolloutsContainerFailureReasons, waiting.Reason) {
+				continue
+			}
+
+			message := fmt.Sprintf("the %s container of Pod %s is waiting: %s", status.Name, pod.Name, waiting.Reason)
+			if waiting.Message != "" {
+				message += ": " + waiting.Message
+			}
+
+			if terminated := status.LastTerminationState.Terminated; terminated != nil {
+				message += fmt.Sprintf(", it last terminated with exit code %d", terminated.ExitCode)
+				if terminationMessage := strings.TrimSpace(terminated.Message); terminationMessage != "" {
+					message += ": " + terminationMessage
+				}
+			}
+
+			return message, nil
+		}
+	}
+
+	return "", nil
+}
+
+// RolloutsPodLabelSelector selects the Rollouts controller Pods, which are the only Pods watched, and cached, by the operator.
+func RolloutsPodLabelSelector() labels.Selector {
+	return labels.SelectorFromSet(labels.Set{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName})
+}
+
+// isRolloutsPod returns true if obj is a Rollouts controller Pod.
+func isRolloutsPod(obj client.Object) bool {
+	return RolloutsPodLabelSelector().Matches(labels.Set(obj.GetLabels()))
+}
+
+// enqueueRolloutManagersForRolloutsPod queues the RolloutManagers in the namespace of a Rollouts controller Pod, so that failures of the containers and plugin init containers of the Pod are reported on the RolloutManager.
+func (r *RolloutManagerReconciler) enqueueRolloutManagersForRolloutsPod(ctx context.Context, obj client.Object) []reconcile.Request {
+
+	if !isRolloutsPod(obj) {
+		return []reconcile.Request{}
+	}
+
+	var rolloutManagerList rolloutsmanagerv1alpha1.RolloutManagerList
+
+	if err := r.Client.List(ctx, &rolloutManagerList, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.Error(err, "Unable to list RolloutManagers in enqueueRolloutManagersForRolloutsPod")
+		return []reconcile.Request{}
+	}
+
+	var res []reconcile.Request
+
+	for idx := range rolloutManagerList.Items {
+		rm := rolloutManagerList.Items[idx]
+		res = append(res, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rm)})
+	}
+
+	return res
+}
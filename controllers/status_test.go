@@ -2,11 +2,13 @@ package rollouts
 
 import (
 	"context"
+	"time"
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -67,4 +69,301 @@ var _ = Describe("RolloutManager Test", func() {
 		Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhaseAvailable))
 
 	})
+
+	It("should report a Failure when the plugin fetcher init container fails to download the plugins", func() {
+		ctx := context.Background()
+		a := makeTestRolloutManager()
+		a.Spec.Plugins.Prefetch = &rolloutsmanagerv1alpha1.PluginPrefetchSpec{Enabled: true}
+
+		r := makeTestReconciler(a)
+		Expect(createNamespace(r, a.Namespace)).To(Succeed())
+
+		var requiredReplicas int32 = 1
+		deploy := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      DefaultArgoRolloutsResourceName,
+				Namespace: a.Namespace,
+			},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &requiredReplicas,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName},
+				},
+			},
+		}
+		Expect(r.Client.Create(ctx, deploy)).To(Succeed())
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "argo-rollouts-pod",
+				Namespace: a.Namespace,
+				Labels:    deploy.Spec.Selector.MatchLabels,
+			},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{{
+					Name:  PluginFetcherContainerName,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				}},
+			},
+		}
+		Expect(r.Client.Create(ctx, pod)).To(Succeed())
+
+		By("while the plugins are being downloaded")
+		rr, err := r.determineStatusPhase(ctx, *a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhasePending))
+		Expect(rr.condition.Reason).To(BeEmpty())
+
+		By("after the download of a plugin has failed, and the init container is restarted")
+		pod.Status.InitContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+		pod.Status.InitContainerStatuses[0].LastTerminationState = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode: 1,
+			Message:  "failed to download plugin argoproj-labs/gatewayAPI from https://example.com/gatewayapi\n",
+		}}
+		Expect(r.Client.Status().Update(ctx, pod)).To(Succeed())
+
+		rr, err = r.determineStatusPhase(ctx, *a)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*rr.rolloutController).To(Equal(rolloutsmanagerv1alpha1.PhaseFailure))
+		Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhaseFailure))
+		Expect(rr.condition.Reason).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerReasonPluginFetchFailed))
+		Expect(rr.condition.Message).To(Equal("the plugin-fetcher init container of Pod argo-rollouts-pod failed to load the plugins: " +
+			"failed to download plugin argoproj-labs/gatewayAPI from https://example.com/gatewayapi"))
+
+		By("verifying that the RolloutManager is queued when the Pod changes")
+		Expect(isRolloutsPod(pod)).To(BeTrue())
+		Expect(r.enqueueRolloutManagersForRolloutsPod(ctx, pod)).To(HaveLen(1))
+
+		By("verifying that other Pods are ignored")
+		otherPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other-pod", Namespace: a.Namespace, Labels: map[string]string{DefaultRolloutsSelectorKey: "other"}}}
+		Expect(isRolloutsPod(otherPod)).To(BeFalse())
+		Expect(r.enqueueRolloutManagersForRolloutsPod(ctx, otherPod)).To(BeEmpty())
+	})
+
+	When("the Deployment of the Rollouts controller is not available", func() {
+		var (
+			ctx    context.Context
+			a      *rolloutsmanagerv1alpha1.RolloutManager
+			r      *RolloutManagerReconciler
+			deploy *appsv1.Deployment
+			pod    *corev1.Pod
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			a = makeTestRolloutManager()
+
+			r = makeTestReconciler(a)
+			Expect(createNamespace(r, a.Namespace)).To(Succeed())
+
+			deploy = &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      DefaultArgoRolloutsResourceName,
+					Namespace: a.Namespace,
+				},
+				Spec: appsv1.DeploymentSpec{
+					Replicas: int32Ptr(1),
+					Selector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{DefaultRolloutsSelectorKey: DefaultArgoRolloutsResourceName},
+					},
+				},
+			}
+			Expect(r.Client.Create(ctx, deploy)).To(Succeed())
+
+			pod = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "argo-rollouts-pod",
+					Namespace: a.Namespace,
+					Labels:    deploy.Spec.Selector.MatchLabels,
+				},
+				Status: corev1.PodStatus{
+					ContainerStatuses: []corev1.ContainerStatus{{
+						Name:  RolloutsContainerName,
+						State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}},
+					}},
+				},
+			}
+			Expect(r.Client.Create(ctx, pod)).To(Succeed())
+		})
+
+		It("should remain Pending while the container is being created", func() {
+			rr, err := r.determineStatusPhase(ctx, *a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhasePending))
+			Expect(rr.condition.Reason).To(BeEmpty())
+		})
+
+		It("should report a Failure when the image of the container cannot be pulled", func() {
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+				Reason:  "ImagePullBackOff",
+				Message: "Back-off pulling image \"quay.io/argoproj/argo-rollouts:v0.0.0\"",
+			}}
+			Expect(r.Client.Status().Update(ctx, pod)).To(Succeed())
+
+			rr, err := r.determineStatusPhase(ctx, *a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*rr.rolloutController).To(Equal(rolloutsmanagerv1alpha1.PhaseFailure))
+			Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhaseFailure))
+			Expect(rr.condition.Reason).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerReasonRolloutsControllerFailed))
+			Expect(rr.condition.Message).To(Equal("the argo-rollouts container of Pod argo-rollouts-pod is waiting: ImagePullBackOff: " +
+				"Back-off pulling image \"quay.io/argoproj/argo-rollouts:v0.0.0\""))
+		})
+
+		It("should report a Failure, with the last termination message, when the container keeps crashing", func() {
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+			pod.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 2,
+				Message:  "unknown flag: --unknown\n",
+			}}
+			Expect(r.Client.Status().Update(ctx, pod)).To(Succeed())
+
+			rr, err := r.determineStatusPhase(ctx, *a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhaseFailure))
+			Expect(rr.condition.Reason).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerReasonRolloutsControllerFailed))
+			Expect(rr.condition.Message).To(Equal("the argo-rollouts container of Pod argo-rollouts-pod is waiting: CrashLoopBackOff, " +
+				"it last terminated with exit code 2: unknown flag: --unknown"))
+		})
+
+		It("should report a Failure when the Deployment did not progress within its deadline", func() {
+			deploy.Status.Conditions = []appsv1.DeploymentCondition{{
+				Type:    appsv1.DeploymentProgressing,
+				Status:  corev1.ConditionFalse,
+				Reason:  DeploymentProgressDeadlineExceededReason,
+				Message: "ReplicaSet \"argo-rollouts-5d8b9c\" has timed out progressing.",
+			}}
+			Expect(r.Client.Status().Update(ctx, deploy)).To(Succeed())
+
+			rr, err := r.determineStatusPhase(ctx, *a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhaseFailure))
+			Expect(rr.condition.Reason).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerReasonProgressDeadlineExceeded))
+			Expect(rr.condition.Message).To(Equal("the Deployment argo-rollouts did not progress within its deadline: " +
+				"ReplicaSet \"argo-rollouts-5d8b9c\" has timed out progressing."))
+		})
+
+		It("should report a Failure once the Rollouts controller has been progressing for longer than the readiness deadline", func() {
+			a.Spec.ReadinessDeadlineSeconds = int32Ptr(60)
+			a.Status.Conditions = []metav1.Condition{
+				createCondition(""),
+				{
+					Type:               rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType,
+					Status:             metav1.ConditionTrue,
+					Reason:             rolloutsmanagerv1alpha1.RolloutManagerReasonNotAvailable,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-30 * time.Second)),
+				},
+			}
+
+			rr, err := r.determineStatusPhase(ctx, *a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhasePending))
+
+			By("exceeding the readiness deadline")
+			a.Status.Conditions[1].LastTransitionTime = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+
+			rr, err = r.determineStatusPhase(ctx, *a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhaseFailure))
+			Expect(rr.condition.Reason).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerReasonReadinessDeadlineExceeded))
+			Expect(rr.condition.Message).To(Equal("the Argo Rollouts controller did not become available within 1m0s"))
+
+			By("disabling the readiness deadline")
+			a.Spec.ReadinessDeadlineSeconds = int32Ptr(0)
+
+			rr, err = r.determineStatusPhase(ctx, *a)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhasePending))
+		})
+	})
+})
+
+var _ = Describe("readinessDeadlineExceeded tests", func() {
+
+	var (
+		now time.Time
+		cr  rolloutsmanagerv1alpha1.RolloutManager
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		cr = rolloutsmanagerv1alpha1.RolloutManager{ObjectMeta: metav1.ObjectMeta{Generation: 1}}
+		cr.Status.ObservedGeneration = 1
+	})
+
+	It("should not be exceeded before the RolloutManager is progressing", func() {
+		Expect(readinessDeadlineExceeded(cr, now)).To(BeFalse())
+	})
+
+	It("should not have a deadline unless one is set", func() {
+		cr.Status.Conditions = []metav1.Condition{{
+			Type:               rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType,
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(now.Add(-5 * time.Minute)),
+		}}
+		Expect(readinessDeadlineExceeded(cr, now.Add(24*time.Hour))).To(BeFalse())
+
+		cr.Spec.ReadinessDeadlineSeconds = int32Ptr(0)
+		Expect(readinessDeadlineExceeded(cr, now.Add(24*time.Hour))).To(BeFalse())
+
+		cr.Spec.ReadinessDeadlineSeconds = int32Ptr(600)
+		Expect(readinessDeadlineExceeded(cr, now)).To(BeFalse())
+		Expect(readinessDeadlineExceeded(cr, now.Add(6*time.Minute))).To(BeTrue())
+	})
+
+	It("should remain exceeded until the generation of the RolloutManager changes", func() {
+		cr.Spec.ReadinessDeadlineSeconds = int32Ptr(600)
+		cr.Status.Conditions = []metav1.Condition{
+			createCondition("timed out", rolloutsmanagerv1alpha1.RolloutManagerReasonReadinessDeadlineExceeded),
+			{
+				Type:               rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType,
+				Status:             metav1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(now),
+			},
+		}
+		Expect(readinessDeadlineExceeded(cr, now)).To(BeTrue())
+
+		cr.Generation = 2
+		Expect(readinessDeadlineExceeded(cr, now)).To(BeFalse())
+	})
+})
+
+var _ = Describe("getRequeueDelay tests", func() {
+
+	now := time.Now()
+
+	readyCondition := func(since time.Duration) metav1.Condition {
+		return metav1.Condition{
+			Type:               rolloutsmanagerv1alpha1.RolloutManagerReadyConditionType,
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(now.Add(-since)),
+		}
+	}
+
+	progressingCondition := func(since time.Duration) metav1.Condition {
+		return metav1.Condition{
+			Type:               rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType,
+			Status:             metav1.ConditionTrue,
+			LastTransitionTime: metav1.NewTime(now.Add(-since)),
+		}
+	}
+
+	DescribeTable("should return the delay after which the RolloutManager is reconciled again", func(phase rolloutsmanagerv1alpha1.RolloutControllerPhase, readinessDeadlineSeconds *int32, conditions []metav1.Condition, expected time.Duration) {
+		cr := rolloutsmanagerv1alpha1.RolloutManager{}
+		cr.Spec.ReadinessDeadlineSeconds = readinessDeadlineSeconds
+		cr.Status.Phase = phase
+		cr.Status.Conditions = conditions
+
+		Expect(getRequeueDelay(cr, now)).To(Equal(expected))
+	},
+		Entry("available", rolloutsmanagerv1alpha1.PhaseAvailable, nil, nil, time.Duration(0)),
+		Entry("failed", rolloutsmanagerv1alpha1.PhaseFailure, nil, []metav1.Condition{readyCondition(time.Hour)}, time.Duration(0)),
+		Entry("pending, without conditions", rolloutsmanagerv1alpha1.PhasePending, nil, nil, requeueMinDelay),
+		Entry("pending, since a moment", rolloutsmanagerv1alpha1.PhasePending, nil, []metav1.Condition{readyCondition(time.Second)}, requeueMinDelay),
+		Entry("pending, since 20 seconds", rolloutsmanagerv1alpha1.PhasePending, nil, []metav1.Condition{readyCondition(20 * time.Second)}, 20*time.Second),
+		Entry("unknown, since an hour", rolloutsmanagerv1alpha1.PhaseUnknown, nil, []metav1.Condition{readyCondition(time.Hour)}, requeueMaxDelay),
+		Entry("pending for an hour, without readiness deadline", rolloutsmanagerv1alpha1.PhasePending, nil,
+			[]metav1.Condition{readyCondition(time.Hour), progressingCondition(time.Hour)}, requeueMaxDelay),
+		Entry("pending, shortly before the readiness deadline", rolloutsmanagerv1alpha1.PhasePending, int32Ptr(60),
+			[]metav1.Condition{readyCondition(50 * time.Second), progressingCondition(50 * time.Second)}, 10*time.Second),
+	)
 })
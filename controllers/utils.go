@@ -10,9 +10,12 @@ import (
 
 	rolloutsmanagerv1alpha1 "github.com/argoproj-labs/argo-rollouts-manager/api/v1alpha1"
 	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -21,14 +24,19 @@ const (
 	UnsupportedRolloutManagerClusterScoped          = "when Subscription has environment variable NAMESPACE_SCOPED_ARGO_ROLLOUTS set to True, there may not exist any cluster-scoped RolloutManagers: in this case, only namespace-scoped RolloutManager resources are supported"
 	UnsupportedRolloutManagerNamespaceScoped        = "when Subscription has environment variable NAMESPACE_SCOPED_ARGO_ROLLOUTS set to False, there may not exist any namespace-scoped RolloutManagers: only a single cluster-scoped RolloutManager is supported"
 	UnsupportedRolloutManagerClusterScopedNamespace = "Namespace is not specified in CLUSTER_SCOPED_ARGO_ROLLOUTS_NAMESPACES environment variable of Subscription resource. If you wish to install a cluster-scoped Argo Rollouts instance outside the default namespace, ensure it is defined in CLUSTER_SCOPED_ARGO_ROLLOUTS_NAMESPACES"
+	UnsupportedLeaderElectionDisabled               = "leader election may not be disabled when more than one replica of the Argo Rollouts controller is requested"
+	UnsupportedPodDisruptionBudgetConfiguration     = "only one of .spec.podDisruptionBudget.minAvailable and .spec.podDisruptionBudget.maxUnavailable may be set"
+	UnsupportedRecreateStrategyParameters           = ".spec.strategy.maxSurge and .spec.strategy.maxUnavailable may only be set when .spec.strategy.type is RollingUpdate"
+	UnsupportedZeroRollingUpdateParameters          = ".spec.strategy.maxSurge and .spec.strategy.maxUnavailable may not both be zero"
 )
 
 // pluginItem is a clone of PluginItem from "github.com/argoproj/argo-rollouts/utils/plugin/types"
 // We clone it here, to avoid a dependency on argo-rollouts.
 type pluginItem struct {
-	Name     string `json:"name" yaml:"name"`
-	Location string `json:"location" yaml:"location"`
-	Sha256   string `json:"sha256" yaml:"sha256"`
+	Name     string   `json:"name" yaml:"name"`
+	Location string   `json:"location" yaml:"location"`
+	Sha256   string   `json:"sha256" yaml:"sha256"`
+	Args     []string `json:"args,omitempty" yaml:"args,omitempty"`
 }
 
 func setRolloutsLabelsAndAnnotationsToObject(obj *metav1.ObjectMeta, cr rolloutsmanagerv1alpha1.RolloutManager) {
@@ -186,20 +194,58 @@ func contains(s []string, g string) bool {
 	return false
 }
 
-// isMergable returns error if any of the extraArgs is already part of the default command Arguments.
-func isMergable(extraArgs []string, cmd []string) error {
-	if len(extraArgs) > 0 {
-		for _, arg := range extraArgs {
-			if len(arg) > 2 && arg[:2] == "--" {
-				if ok := contains(cmd, arg); ok {
-					err := errors.New("duplicate argument error")
-					log.Error(err, fmt.Sprintf("Arg %s is already part of the default command arguments", arg))
-					return err
-				}
-			}
+// mergeCommandArgs merges the extra arguments of the RolloutManager into the arguments built by the operator.
+// A flag that is set by both keeps its position in args, with the values from extraArgs. Other flags of extraArgs are appended, in order.
+func mergeCommandArgs(args []string, extraArgs []string) []string {
+
+	extraCommandArgs := rolloutsmanagerv1alpha1.ParseCommandArgs(extraArgs)
+
+	extraByFlag := map[string]rolloutsmanagerv1alpha1.CommandArg{}
+	for _, extraArg := range extraCommandArgs {
+		if extraArg.Flag != "" {
+			extraByFlag[extraArg.Flag] = extraArg
 		}
 	}
-	return nil
+
+	res := make([]string, 0, len(args)+len(extraArgs))
+	merged := map[string]bool{}
+	for _, arg := range rolloutsmanagerv1alpha1.ParseCommandArgs(args) {
+		if extraArg, exists := extraByFlag[arg.Flag]; exists {
+			res = append(res, extraArg.Tokens...)
+			merged[arg.Flag] = true
+			continue
+		}
+		res = append(res, arg.Tokens...)
+	}
+
+	for _, extraArg := range extraCommandArgs {
+		if extraArg.Flag != "" && merged[extraArg.Flag] {
+			continue
+		}
+		res = append(res, extraArg.Tokens...)
+	}
+
+	return res
+}
+
+// validationFailure returns the result of a validation of the RolloutManager which failed with err: the RolloutManager, and its Rollouts controller, are in the Failure phase.
+func validationFailure(err error) (*reconcileStatusResult, error) {
+	phaseFailure := rolloutsmanagerv1alpha1.PhaseFailure
+
+	return &reconcileStatusResult{
+		rolloutController: &phaseFailure,
+		phase:             &phaseFailure,
+	}, err
+}
+
+// fieldValidationFailure returns the result of a validation of the RolloutManager which failed with errs, the errors of a validation rule which is
+// shared with the webhook: the first error is reported, prefixed by reason.
+func fieldValidationFailure(reason string, errs field.ErrorList) (*reconcileStatusResult, error) {
+	detail := errs[0].Detail
+	if detail == "" {
+		detail = errs[0].ErrorBody()
+	}
+	return validationFailure(fmt.Errorf("%s: %s: %s", reason, errs[0].Field, detail))
 }
 
 // validateRolloutsScope will check scope of Rollouts controller configured in RolloutManager and scope allowed by Admin (Configured in Subscription.Spec.Config.Env)
@@ -253,6 +299,47 @@ func validateRolloutsScope(cr rolloutsmanagerv1alpha1.RolloutManager, namespaceS
 	}
 }
 
+// validateRolloutsHA validates the replicas, leader election and PodDisruptionBudget settings of the RolloutManager.
+func validateRolloutsHA(cr rolloutsmanagerv1alpha1.RolloutManager) (*reconcileStatusResult, error) {
+
+	// Multiple controller pods without leader election would all reconcile the same Rollouts.
+	if getRolloutsReplicas(cr) > 1 && cr.Spec.LeaderElection != nil &&
+		cr.Spec.LeaderElection.Enabled != nil && !*cr.Spec.LeaderElection.Enabled {
+
+		return validationFailure(errors.New(UnsupportedLeaderElectionDisabled))
+	}
+
+	if pdb := cr.Spec.PodDisruptionBudget; pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+
+		return validationFailure(errors.New(UnsupportedPodDisruptionBudgetConfiguration))
+	}
+
+	if strategy := cr.Spec.Strategy; strategy != nil && (strategy.MaxSurge != nil || strategy.MaxUnavailable != nil) {
+
+		if strategy.Type == appsv1.RecreateDeploymentStrategyType {
+			return validationFailure(errors.New(UnsupportedRecreateStrategyParameters))
+		}
+
+		// The rolling update could never make progress, the Deployment API rejects it.
+		if isZeroIntOrPercent(strategy.MaxSurge) && isZeroIntOrPercent(strategy.MaxUnavailable) {
+			return validationFailure(errors.New(UnsupportedZeroRollingUpdateParameters))
+		}
+	}
+
+	return nil, nil
+}
+
+// isZeroIntOrPercent returns true if the value is set to 0 or 0%. Unset values default to a non-zero value.
+func isZeroIntOrPercent(value *intstr.IntOrString) bool {
+	if value == nil {
+		return false
+	}
+	if value.Type == intstr.Int {
+		return value.IntVal == 0
+	}
+	return value.StrVal == "0%" || value.StrVal == "0"
+}
+
 // allowedClusterScopedNamespace will check that current namespace is allowed to host cluster-scoped Argo Rollouts.
 func allowedClusterScopedNamespace(cr rolloutsmanagerv1alpha1.RolloutManager) bool {
 	clusterConfigNamespaces := splitList(os.Getenv(ClusterScopedArgoRolloutsNamespaces))
@@ -328,11 +415,36 @@ func invalidRolloutNamespace(err error) bool {
 	return err.Error() == UnsupportedRolloutManagerClusterScopedNamespace
 }
 
+func invalidHAConfiguration(err error) bool {
+	return err.Error() == UnsupportedLeaderElectionDisabled ||
+		err.Error() == UnsupportedPodDisruptionBudgetConfiguration ||
+		err.Error() == UnsupportedRecreateStrategyParameters ||
+		err.Error() == UnsupportedZeroRollingUpdateParameters
+}
+
 // updateStatusConditionOfRolloutManager calls Set Condition of RolloutManager status
 func updateStatusConditionOfRolloutManager(ctx context.Context, rr reconcileStatusResult, rm *rolloutsmanagerv1alpha1.RolloutManager, k8sClient client.Client, log logr.Logger) error {
 
 	changed, newConditions := insertOrUpdateConditionsInSlice(rr.condition, rm.Status.Conditions)
 
+	crdVersionSkewConditionSet := false
+	for _, condition := range rr.conditions {
+		conditionChanged, conditions := insertOrUpdateConditionsInSlice(condition, newConditions)
+		changed = changed || conditionChanged
+		newConditions = conditions
+
+		if condition.Type == rolloutsmanagerv1alpha1.RolloutManagerCRDVersionSkewConditionType {
+			crdVersionSkewConditionSet = true
+		}
+	}
+
+	if !crdVersionSkewConditionSet && !isCRDManagementEnabled(*rm) {
+		// The CRDVersionSkew condition is only reported while the management of the CRDs is enabled
+		crdConditionRemoved, conditions := removeConditionFromSlice(rolloutsmanagerv1alpha1.RolloutManagerCRDVersionSkewConditionType, newConditions)
+		changed = changed || crdConditionRemoved
+		newConditions = conditions
+	}
+
 	if rr.phase != nil && *rr.phase != rm.Status.Phase {
 		rm.Status.Phase = *rr.phase
 		changed = true
@@ -343,6 +455,21 @@ func updateStatusConditionOfRolloutManager(ctx context.Context, rr reconcileStat
 		changed = true
 	}
 
+	// The Ready and Progressing conditions summarize the Reconciled condition and the phase, for tools that only understand the standard conditions.
+	for _, condition := range []metav1.Condition{
+		createReadyCondition(rr.condition, rm.Status.Phase),
+		createProgressingCondition(rr.condition, rm.Status.Phase),
+	} {
+		conditionChanged, conditions := insertOrUpdateConditionsInSlice(condition, newConditions)
+		changed = changed || conditionChanged
+		newConditions = conditions
+	}
+
+	if rm.Status.ObservedGeneration != rm.Generation {
+		rm.Status.ObservedGeneration = rm.Generation
+		changed = true
+	}
+
 	if changed {
 		rm.Status.Conditions = newConditions
 
@@ -354,6 +481,19 @@ func updateStatusConditionOfRolloutManager(ctx context.Context, rr reconcileStat
 	return nil
 }
 
+// removeConditionFromSlice removes the condition of the given type from a slice of []metav1.Condition, and returns true if it was present
+func removeConditionFromSlice(conditionType string, existingConditions []metav1.Condition) (bool, []metav1.Condition) {
+
+	var res []metav1.Condition
+	for _, condition := range existingConditions {
+		if condition.Type != conditionType {
+			res = append(res, condition)
+		}
+	}
+
+	return len(res) != len(existingConditions), res
+}
+
 // insertOrUpdateConditionsInSlice is a generic function for inserting/updating metav1.Condition into a slice of []metav1.Condition
 func insertOrUpdateConditionsInSlice(newCondition metav1.Condition, existingConditions []metav1.Condition) (bool, []metav1.Condition) {
 
@@ -481,4 +621,34 @@ func removeUserLabelsAndAnnotations(obj *metav1.ObjectMeta, cr rolloutsmanagerv1
 		}
 	}
 
+	// The expected labels/annotations are never nil, while the API server omits them when they are empty: normalize obj so that the two can be compared.
+	if obj.Labels == nil {
+		obj.Labels = map[string]string{}
+	}
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
+
+}
+
+// removeUnexpectedLabelsAndAnnotations will remove the labels/annotations from obj that are not in 'expected', for resources whose expected labels differ from the default labels, such as the aggregated ClusterRoles.
+func removeUnexpectedLabelsAndAnnotations(obj *metav1.ObjectMeta, expected metav1.ObjectMeta) {
+
+	for k := range obj.Labels {
+		if _, exists := expected.Labels[k]; !exists {
+			delete(obj.Labels, k)
+		}
+	}
+	for k := range obj.Annotations {
+		if _, exists := expected.Annotations[k]; !exists {
+			delete(obj.Annotations, k)
+		}
+	}
+
+	if obj.Labels == nil {
+		obj.Labels = map[string]string{}
+	}
+	if obj.Annotations == nil {
+		obj.Annotations = map[string]string{}
+	}
 }
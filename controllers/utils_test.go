@@ -9,11 +9,15 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	crdv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
@@ -95,7 +99,7 @@ var _ = Describe("updateStatusConditionOfRolloutManager tests", func() {
 			Expect(updateStatusConditionOfRolloutManager(ctx, rsr, &rolloutsManager, k8sClient, logger.FromContext(ctx))).To(Succeed())
 
 			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&rolloutsManager), &rolloutsManager)).To(Succeed())
-			Expect(rolloutsManager.Status.Conditions).To(HaveLen(1))
+			Expect(rolloutsManager.Status.Conditions).To(HaveLen(3))
 			Expect(rolloutsManager.Status.Conditions[0].Message).To(Equal(newCondition.Message))
 			Expect(rolloutsManager.Status.Conditions[0].Reason).To(Equal(newCondition.Reason))
 
@@ -110,6 +114,83 @@ var _ = Describe("updateStatusConditionOfRolloutManager tests", func() {
 			Entry("should return error when len(reason) > 1", "my reason 1", "my reason 2"))
 	})
 
+	When("reconcileStatusResult contains a successful condition", func() {
+		It("should set the Ready and Progressing conditions from the phase, and the observed generation", func() {
+			Expect(k8sClient.Create(ctx, &rolloutsManager)).To(Succeed())
+			rolloutsManager.Generation = 2
+
+			pending := rolloutsmanagerv1alpha1.PhasePending
+			rsr := reconcileStatusResult{
+				condition: createCondition(""),
+				phase:     &pending,
+			}
+			Expect(updateStatusConditionOfRolloutManager(ctx, rsr, &rolloutsManager, k8sClient, logger.FromContext(ctx))).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&rolloutsManager), &rolloutsManager)).To(Succeed())
+			Expect(rolloutsManager.Status.ObservedGeneration).To(Equal(int64(2)))
+			Expect(meta.IsStatusConditionFalse(rolloutsManager.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerReadyConditionType)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(rolloutsManager.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType)).To(BeTrue())
+
+			By("setting the phase to Available")
+			available := rolloutsmanagerv1alpha1.PhaseAvailable
+			rsr.phase = &available
+			Expect(updateStatusConditionOfRolloutManager(ctx, rsr, &rolloutsManager, k8sClient, logger.FromContext(ctx))).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&rolloutsManager), &rolloutsManager)).To(Succeed())
+			ready := meta.FindStatusCondition(rolloutsManager.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerReadyConditionType)
+			Expect(ready.Status).To(Equal(metav1.ConditionTrue))
+			Expect(ready.Reason).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerReasonAvailable))
+			Expect(meta.IsStatusConditionFalse(rolloutsManager.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType)).To(BeTrue())
+
+			By("failing to reconcile the RolloutManager")
+			rsr.condition = createCondition(UnsupportedLeaderElectionDisabled, rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidHAConfiguration)
+			Expect(updateStatusConditionOfRolloutManager(ctx, rsr, &rolloutsManager, k8sClient, logger.FromContext(ctx))).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&rolloutsManager), &rolloutsManager)).To(Succeed())
+			ready = meta.FindStatusCondition(rolloutsManager.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerReadyConditionType)
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerReasonInvalidHAConfiguration))
+			Expect(ready.Message).To(Equal(UnsupportedLeaderElectionDisabled))
+			Expect(meta.IsStatusConditionFalse(rolloutsManager.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerProgressingConditionType)).To(BeTrue())
+		})
+	})
+
+	When("reconcileStatusResult contains a CRDVersionSkew condition", func() {
+		It("should set the condition, and remove it once the management of the CRDs is disabled", func() {
+			rolloutsManager.Spec.CRDManagement = &rolloutsmanagerv1alpha1.RolloutsCRDManagementSpec{Enabled: true}
+			Expect(k8sClient.Create(ctx, &rolloutsManager)).To(Succeed())
+
+			rsr := reconcileStatusResult{
+				condition: createCondition(""),
+				conditions: []metav1.Condition{{
+					Type:    rolloutsmanagerv1alpha1.RolloutManagerCRDVersionSkewConditionType,
+					Status:  metav1.ConditionTrue,
+					Reason:  rolloutsmanagerv1alpha1.RolloutManagerReasonCRDVersionSkew,
+					Message: "skew",
+				}},
+			}
+			Expect(updateStatusConditionOfRolloutManager(ctx, rsr, &rolloutsManager, k8sClient, logger.FromContext(ctx))).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&rolloutsManager), &rolloutsManager)).To(Succeed())
+			Expect(rolloutsManager.Status.Conditions).To(HaveLen(4))
+			Expect(rolloutsManager.Status.Conditions[1].Type).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerCRDVersionSkewConditionType))
+			Expect(rolloutsManager.Status.Conditions[1].Reason).To(Equal(rolloutsmanagerv1alpha1.RolloutManagerReasonCRDVersionSkew))
+
+			By("keeping the condition when it is not part of the result, while the management of the CRDs is enabled")
+			rsr.conditions = nil
+			Expect(updateStatusConditionOfRolloutManager(ctx, rsr, &rolloutsManager, k8sClient, logger.FromContext(ctx))).To(Succeed())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&rolloutsManager), &rolloutsManager)).To(Succeed())
+			Expect(rolloutsManager.Status.Conditions).To(HaveLen(4))
+
+			By("disabling the management of the CRDs")
+			rolloutsManager.Spec.CRDManagement = nil
+			Expect(updateStatusConditionOfRolloutManager(ctx, rsr, &rolloutsManager, k8sClient, logger.FromContext(ctx))).To(Succeed())
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&rolloutsManager), &rolloutsManager)).To(Succeed())
+			Expect(rolloutsManager.Status.Conditions).To(HaveLen(3))
+			Expect(meta.FindStatusCondition(rolloutsManager.Status.Conditions, rolloutsmanagerv1alpha1.RolloutManagerCRDVersionSkewConditionType)).To(BeNil())
+		})
+	})
+
 })
 
 var _ = Describe("checkForExistingRolloutManager tests", func() {
@@ -417,6 +498,67 @@ var _ = Describe("validateRolloutsScope tests", func() {
 	})
 })
 
+var _ = Describe("validateRolloutsHA tests", func() {
+
+	DescribeTable("should validate the HA configuration of the RolloutManager", func(spec rolloutsmanagerv1alpha1.RolloutManagerSpec, expectErr bool) {
+
+		cr := rolloutsmanagerv1alpha1.RolloutManager{Spec: spec}
+
+		rr, err := validateRolloutsHA(cr)
+		if !expectErr {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rr).To(BeNil())
+			return
+		}
+
+		Expect(err).To(HaveOccurred())
+		Expect(invalidHAConfiguration(err)).To(BeTrue())
+		Expect(*rr.phase).To(Equal(rolloutsmanagerv1alpha1.PhaseFailure))
+		Expect(*rr.rolloutController).To(Equal(rolloutsmanagerv1alpha1.PhaseFailure))
+	},
+		Entry("default spec", rolloutsmanagerv1alpha1.RolloutManagerSpec{}, false),
+		Entry("multiple replicas with default leader election", rolloutsmanagerv1alpha1.RolloutManagerSpec{
+			Replicas: int32Ptr(3),
+		}, false),
+		Entry("single replica with leader election disabled", rolloutsmanagerv1alpha1.RolloutManagerSpec{
+			Replicas:       int32Ptr(1),
+			LeaderElection: &rolloutsmanagerv1alpha1.RolloutsLeaderElectionSpec{Enabled: boolPtr(false)},
+		}, false),
+		Entry("multiple replicas with leader election disabled", rolloutsmanagerv1alpha1.RolloutManagerSpec{
+			Replicas:       int32Ptr(2),
+			LeaderElection: &rolloutsmanagerv1alpha1.RolloutsLeaderElectionSpec{Enabled: boolPtr(false)},
+		}, true),
+		Entry("PodDisruptionBudget with only minAvailable", rolloutsmanagerv1alpha1.RolloutManagerSpec{
+			PodDisruptionBudget: &rolloutsmanagerv1alpha1.RolloutsPodDisruptionBudgetSpec{MinAvailable: intOrStringPtr(intstr.FromInt(1))},
+		}, false),
+		Entry("PodDisruptionBudget with both minAvailable and maxUnavailable", rolloutsmanagerv1alpha1.RolloutManagerSpec{
+			PodDisruptionBudget: &rolloutsmanagerv1alpha1.RolloutsPodDisruptionBudgetSpec{
+				MinAvailable:   intOrStringPtr(intstr.FromInt(1)),
+				MaxUnavailable: intOrStringPtr(intstr.FromString("50%")),
+			},
+		}, true),
+		Entry("Recreate strategy", rolloutsmanagerv1alpha1.RolloutManagerSpec{
+			Strategy: &rolloutsmanagerv1alpha1.RolloutsDeploymentStrategySpec{Type: appsv1.RecreateDeploymentStrategyType},
+		}, false),
+		Entry("Recreate strategy with maxSurge", rolloutsmanagerv1alpha1.RolloutManagerSpec{
+			Strategy: &rolloutsmanagerv1alpha1.RolloutsDeploymentStrategySpec{
+				Type:     appsv1.RecreateDeploymentStrategyType,
+				MaxSurge: intOrStringPtr(intstr.FromInt(1)),
+			},
+		}, true),
+		Entry("RollingUpdate strategy with maxSurge 0 and the default maxUnavailable", rolloutsmanagerv1alpha1.RolloutManagerSpec{
+			Strategy: &rolloutsmanagerv1alpha1.RolloutsDeploymentStrategySpec{MaxSurge: intOrStringPtr(intstr.FromInt(0))},
+		}, false),
+		Entry("RollingUpdate strategy with both maxSurge and maxUnavailable zero", rolloutsmanagerv1alpha1.RolloutManagerSpec{
+			Strategy: &rolloutsmanagerv1alpha1.RolloutsDeploymentStrategySpec{
+				Type:           appsv1.RollingUpdateDeploymentStrategyType,
+				MaxSurge:       intOrStringPtr(intstr.FromInt(0)),
+				MaxUnavailable: intOrStringPtr(intstr.FromString("0%")),
+			},
+		}, true),
+	)
+})
+
 var _ = Describe("removeUserLabelsAndAnnotations tests", func() {
 	var (
 		obj       metav1.ObjectMeta
@@ -588,19 +730,14 @@ var _ = Describe("insertOrUpdateConditionsInSlice tests", func() {
 
 })
 
-var _ = Describe("isMergable tests", func() {
-	DescribeTable("checking for duplicate arguments", func(extraArgs, cmd []string, expectedErr bool) {
-		err := isMergable(extraArgs, cmd)
-		if expectedErr {
-			Expect(err).To(HaveOccurred())
-		} else {
-			Expect(err).NotTo(HaveOccurred())
-		}
+var _ = Describe("mergeCommandArgs tests", func() {
+	DescribeTable("merging extra arguments into the default command arguments", func(args, extraArgs, expectedArgs []string) {
+		Expect(mergeCommandArgs(args, extraArgs)).To(Equal(expectedArgs))
 	},
-		Entry("no extraArgs", []string{}, []string{"--cmd1", "--cmd2"}, false),
-		Entry("extraArgs with no `--` args", []string{"arg1", "arg2"}, []string{"--cmd1", "--cmd2"}, false),
-		Entry("extraArgs with `--` args but no duplicates", []string{"--arg1", "--arg2"}, []string{"--cmd1", "--cmd2"}, false),
-		Entry("extraArgs with duplicate `--` args", []string{"--arg1", "--cmd1"}, []string{"--cmd1", "--cmd2"}, true),
+		Entry("no extraArgs", []string{"--cmd1", "--cmd2"}, []string{}, []string{"--cmd1", "--cmd2"}),
+		Entry("extraArgs with no duplicates", []string{"--cmd1", "value1"}, []string{"--arg1", "--arg2", "value2"}, []string{"--cmd1", "value1", "--arg1", "--arg2", "value2"}),
+		Entry("extraArgs overriding the value of a flag", []string{"--cmd1", "value1", "--cmd2"}, []string{"--arg1", "--cmd1", "value2"}, []string{"--cmd1", "value2", "--cmd2", "--arg1"}),
+		Entry("extraArgs overriding a flag with an inline value", []string{"--cmd1=true", "--cmd2", "10s"}, []string{"--cmd2=20s", "--cmd1", "false"}, []string{"--cmd1", "false", "--cmd2=20s"}),
 	)
 })
 
@@ -770,6 +907,7 @@ func makeTestReconciler(obj ...client.Object) *RolloutManagerReconciler {
 		Client:                       cl,
 		Scheme:                       s,
 		OpenShiftRoutePluginLocation: "file://non-empty-test-url", // Set a non-real, non-empty value for unit tests: override this to test a specific value
+		Recorder:                     &record.FakeRecorder{},      // Events are discarded: replace it with record.NewFakeRecorder to verify them
 	}
 }
 
@@ -777,3 +915,11 @@ func createNamespace(r *RolloutManagerReconciler, n string) error {
 	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: n}}
 	return r.Client.Create(context.Background(), ns)
 }
+
+func int32Ptr(val int32) *int32 {
+	return &val
+}
+
+func intOrStringPtr(val intstr.IntOrString) *intstr.IntOrString {
+	return &val
+}